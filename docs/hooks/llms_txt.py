@@ -16,8 +16,9 @@ _PREAMBLE = """\
 > Zero-dependency MCP server that exposes Google Search Console search analytics and URL inspection to AI assistants.
 
 The Google Search Console MCP server provides native binaries for Go and C#.
-It exposes four MCP tools -- query_search_analytics, list_sites, list_sitemaps,
-inspect_url -- directly to AI assistants like Claude, GitHub Copilot, and Cursor.
+It exposes four core MCP tools -- query_search_analytics, list_sites, list_sitemaps,
+inspect_url -- directly to AI assistants like Claude, GitHub Copilot, and Cursor,
+and the Go server adds further analysis tools.
 Author: Nick Cosentino (https://www.devleader.ca).
 
 """
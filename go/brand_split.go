@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/config"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// segmentBrand is the query_search_analytics segment value that splits rows
+// into branded and non-branded queries.
+const segmentBrand = "brand"
+
+// brandSplitInput is the input schema for the brand_split tool.
+type brandSplitInput struct {
+	SiteURL               string                      `json:"site_url"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	DateRange             string                      `json:"date_range,omitempty"`
+	Comparison            string                      `json:"comparison,omitempty"`
+	Granularity           string                      `json:"granularity,omitempty"`
+	BrandTerms            []string                    `json:"brand_terms,omitempty"`
+	BrandPatterns         []string                    `json:"brand_patterns,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	MaxRows               int                         `json:"max_rows,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+func brandSplit(
+	ctx context.Context,
+	client *searchconsole.Client,
+	analysisConfig config.AnalysisConfig,
+	input brandSplitInput,
+) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*analysis.BrandSplitResult]("splitting brand traffic", nil, err)
+	}
+	granularity := input.Granularity
+	if granularity == "" {
+		granularity = analysis.GranularityWeek
+	}
+	if granularity != analysis.GranularityDay && granularity != analysis.GranularityWeek {
+		err := fmt.Errorf("invalid granularity %q: must be day or week", granularity)
+		return marshalToolResult[*analysis.BrandSplitResult]("splitting brand traffic", nil, err)
+	}
+	matcher, err := brandMatcherFor(analysisConfig, input.SiteURL, input.BrandTerms, input.BrandPatterns)
+	if err != nil {
+		return marshalToolResult[*analysis.BrandSplitResult]("splitting brand traffic", nil, err)
+	}
+
+	current, previous, comparison, err := queryPeriodPair(ctx, client, periodQuery{
+		SiteURL:    input.SiteURL,
+		StartDate:  input.StartDate,
+		EndDate:    input.EndDate,
+		DateRange:  input.DateRange,
+		Comparison: input.Comparison,
+		Dimensions: []string{"date", "query"},
+		SearchType: input.SearchType,
+		Options: searchconsole.SearchAnalyticsOptions{
+			DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+			MaxRows:               input.MaxRows,
+		},
+	})
+	if err != nil {
+		return marshalToolResult[*analysis.BrandSplitResult]("splitting brand traffic", nil, err)
+	}
+	result, err := analysis.BrandSplit(current, previous, comparison, matcher, granularity)
+	return formatToolResult("splitting brand traffic", input.OutputFormat, nil, result, err)
+}
+
+// brandMatcherFor returns the brand matcher for a tool call on siteURL. Terms
+// and patterns passed with the call override the analysis config; otherwise
+// the config's entry for the site's domain, or its "*" entry, is used.
+func brandMatcherFor(
+	analysisConfig config.AnalysisConfig,
+	siteURL string,
+	terms, patterns []string,
+) (*analysis.BrandMatcher, error) {
+	if len(terms) == 0 && len(patterns) == 0 {
+		brand, ok := propertyConfigFor(analysisConfig.Brands, siteURL)
+		if !ok {
+			return nil, fmt.Errorf(
+				"no brand terms configured for %q: pass brand_terms or brand_patterns, or add the property to the brands section of the analysis config file",
+				siteURL)
+		}
+		terms, patterns = brand.Terms, brand.Patterns
+	}
+	return analysis.NewBrandMatcher(terms, patterns)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/config"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestQuerySearchAnalytics_BrandSegment_UsesConfiguredTerms(t *testing.T) {
+	srv := newPeriodServer(t, map[string]string{
+		"2026-02-01": `[
+			{"keys":["devleader"],"clicks":50,"impressions":100,"ctr":0.5,"position":1},
+			{"keys":["blazor forms"],"clicks":10,"impressions":300,"ctr":0.03,"position":6}
+		]`,
+	}, nil)
+
+	client := searchconsole.NewTestClient(srv.Client())
+	cfg := config.AnalysisConfig{Brands: map[string]config.BrandConfig{"devleader.ca": {Terms: []string{"dev leader"}}}}
+	result, _, err := querySearchAnalytics(context.Background(), client, cfg, querySearchAnalyticsInput{
+		SiteURL:    "https://www.devleader.ca/",
+		StartDate:  "2026-02-01",
+		EndDate:    "2026-02-28",
+		Dimensions: []string{"query"},
+		Segment:    "brand",
+	})
+	if err != nil {
+		t.Fatalf("querySearchAnalytics: %v", err)
+	}
+
+	var payload searchconsole.SearchAnalyticsResponse
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if payload.Segment != "brand" || payload.Rows[0].Segment != "branded" || payload.Rows[1].Segment != "non_branded" {
+		t.Errorf("payload = %+v, want devleader branded and blazor forms non-branded", payload)
+	}
+	if len(payload.Segments) != 2 || payload.Segments[0].Clicks != 50 || payload.Segments[1].Clicks != 10 {
+		t.Errorf("segments = %+v, want 50 branded and 10 non-branded clicks", payload.Segments)
+	}
+}
+
+func TestQuerySearchAnalytics_BrandSegment_InvalidInput_ReturnsErrorContentWithoutHTTPCall(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		input  querySearchAnalyticsInput
+		marker string
+	}{
+		{name: "unknown segment", input: querySearchAnalyticsInput{Dimensions: []string{"query"}, Segment: "intent"}, marker: `invalid segment \"intent\"`},
+		{name: "no query dimension", input: querySearchAnalyticsInput{Dimensions: []string{"page"}, Segment: "brand"}, marker: "requires the query dimension"},
+		{name: "no brand terms", input: querySearchAnalyticsInput{Dimensions: []string{"query"}, Segment: "brand"}, marker: "no brand terms configured"},
+		{name: "bad pattern", input: querySearchAnalyticsInput{Dimensions: []string{"query"}, Segment: "brand", BrandPatterns: []string{"("}}, marker: "invalid brand pattern"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []map[string]any
+			srv := newPeriodServer(t, nil, &requests)
+			client := searchconsole.NewTestClient(srv.Client())
+
+			tt.input.SiteURL = "devleader.ca"
+			tt.input.DateRange = "last_28_days"
+			result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, tt.input)
+			if err != nil {
+				t.Fatalf("querySearchAnalytics returned a Go error instead of error content: %v", err)
+			}
+			text := result.Content[0].(*mcp.TextContent).Text
+			if !strings.Contains(text, "querying search analytics:") || !strings.Contains(text, tt.marker) {
+				t.Errorf("result text = %q, want it to contain %q", text, tt.marker)
+			}
+			if len(requests) != 0 {
+				t.Errorf("expected 0 HTTP calls, got %d", len(requests))
+			}
+		})
+	}
+}
+
+func TestBrandSplit_PerCallTermsOverrideConfig(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, map[string]string{
+		"2026-02-08": `[
+			{"keys":["2026-02-09","acme widgets"],"clicks":5,"impressions":50,"ctr":0.1,"position":2},
+			{"keys":["2026-02-09","devleader"],"clicks":40,"impressions":80,"ctr":0.5,"position":1}
+		]`,
+	}, &requests)
+
+	client := searchconsole.NewTestClient(srv.Client())
+	cfg := config.AnalysisConfig{Brands: map[string]config.BrandConfig{"*": {Terms: []string{"devleader"}}}}
+	result, _, err := brandSplit(context.Background(), client, cfg, brandSplitInput{
+		SiteURL:     "devleader.ca",
+		StartDate:   "2026-02-08",
+		EndDate:     "2026-02-14",
+		Granularity: "day",
+		BrandTerms:  []string{"acme"},
+	})
+	if err != nil {
+		t.Fatalf("brandSplit: %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("request count = %d, want 2", len(requests))
+	}
+	if dims, _ := requests[0]["dimensions"].([]any); len(dims) != 2 || dims[0] != "date" || dims[1] != "query" {
+		t.Errorf("request dimensions = %v, want [date query]", requests[0]["dimensions"])
+	}
+
+	var payload analysis.BrandSplitResult
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if payload.Branded.Clicks.Current != 5 || payload.NonBranded.Clicks.Current != 40 {
+		t.Errorf("branded/non-branded clicks = %v/%v, want 5/40 using the per-call term",
+			payload.Branded.Clicks.Current, payload.NonBranded.Clicks.Current)
+	}
+	if payload.Granularity != "day" || len(payload.Trend) != 1 || payload.Trend[0].StartDate != "2026-02-09" {
+		t.Errorf("trend = %+v, want one day", payload.Trend)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const (
+	defaultClusterMaxNgram   = 2
+	defaultClusterMinQueries = 2
+	defaultClusterLimit      = 50
+)
+
+// clusterQueriesInput is the input schema for the cluster_queries tool.
+type clusterQueriesInput struct {
+	SiteURL               string                      `json:"site_url"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	DateRange             string                      `json:"date_range,omitempty"`
+	MaxNgram              int                         `json:"max_ngram,omitempty"`
+	MinQueries            int                         `json:"min_queries,omitempty"`
+	SortBy                string                      `json:"sort_by,omitempty"`
+	Limit                 int                         `json:"limit,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	MaxRows               int                         `json:"max_rows,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+func clusterQueries(ctx context.Context, client *searchconsole.Client, input clusterQueriesInput) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*analysis.QueryClusterResult]("clustering queries", nil, err)
+	}
+	options := analysis.QueryClusterOptions{
+		MaxNgram:   input.MaxNgram,
+		MinQueries: input.MinQueries,
+		SortBy:     input.SortBy,
+		Limit:      input.Limit,
+	}
+	if options.MaxNgram == 0 {
+		options.MaxNgram = defaultClusterMaxNgram
+	}
+	if options.MinQueries == 0 {
+		options.MinQueries = defaultClusterMinQueries
+	}
+	if options.SortBy == "" {
+		options.SortBy = analysis.ClusterSortImpressions
+	}
+	if options.Limit == 0 {
+		options.Limit = defaultClusterLimit
+	}
+	if err := options.Validate(); err != nil {
+		return marshalToolResult[*analysis.QueryClusterResult]("clustering queries", nil, err)
+	}
+
+	startDate, endDate, err := resolveDateInput(input.DateRange, input.StartDate, input.EndDate)
+	if err != nil {
+		return marshalToolResult[*analysis.QueryClusterResult]("clustering queries", nil, err)
+	}
+	resp, err := client.QuerySearchAnalytics(
+		ctx, input.SiteURL, startDate, endDate, []string{"query"}, 0, input.SearchType,
+		searchconsole.SearchAnalyticsOptions{
+			DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+			AllRows:               true,
+			MaxRows:               input.MaxRows,
+		})
+	if err != nil {
+		return marshalToolResult[*analysis.QueryClusterResult]("clustering queries", nil, err)
+	}
+	result, err := analysis.ClusterQueries(resp, options)
+	return formatToolResult("clustering queries", input.OutputFormat, nil, result, err)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestClusterQueries_FetchesAllQueryRowsAndAppliesDefaults(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, map[string]string{
+		"2026-02-01": `[
+			{"keys":["blazor forms"],"clicks":10,"impressions":200,"ctr":0.05,"position":4},
+			{"keys":["blazor form validation"],"clicks":5,"impressions":100,"ctr":0.05,"position":7}
+		]`,
+	}, &requests)
+
+	client := searchconsole.NewTestClient(srv.Client())
+	result, _, err := clusterQueries(context.Background(), client, clusterQueriesInput{
+		SiteURL:   "devleader.ca",
+		StartDate: "2026-02-01",
+		EndDate:   "2026-02-28",
+	})
+	if err != nil {
+		t.Fatalf("clusterQueries: %v", err)
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("request count = %d, want 1", len(requests))
+	}
+	if dims, _ := requests[0]["dimensions"].([]any); len(dims) != 1 || dims[0] != "query" {
+		t.Errorf("request dimensions = %v, want [query]", requests[0]["dimensions"])
+	}
+	if requests[0]["rowLimit"] != float64(25000) {
+		t.Errorf("rowLimit = %v, want the all-rows page size 25000", requests[0]["rowLimit"])
+	}
+
+	var payload analysis.QueryClusterResult
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if payload.MaxNgram != 2 || payload.MinQueries != 2 || payload.SortBy != "impressions" {
+		t.Errorf("payload options = %d/%d/%s, want defaults 2/2/impressions", payload.MaxNgram, payload.MinQueries, payload.SortBy)
+	}
+	if len(payload.Clusters) != 3 || payload.Clusters[0].Stem != "blazor" {
+		t.Errorf("clusters = %+v, want blazor, blazor form and form", payload.Clusters)
+	}
+}
+
+func TestClusterQueries_InvalidMaxNgram_ReturnsErrorContentWithoutHTTPCall(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+
+	client := searchconsole.NewTestClient(srv.Client())
+	result, _, err := clusterQueries(context.Background(), client, clusterQueriesInput{
+		SiteURL:   "devleader.ca",
+		DateRange: "last_28_days",
+		MaxNgram:  5,
+	})
+	if err != nil {
+		t.Fatalf("clusterQueries returned a Go error instead of error content: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "clustering queries:") || !strings.Contains(text, "invalid max_ngram 5") {
+		t.Errorf("result text = %q, want an invalid max_ngram error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected 0 HTTP calls, got %d", len(requests))
+	}
+}
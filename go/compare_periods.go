@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const defaultComparison = "previous_period"
+
+// comparePeriodsInput is the input schema for the compare_periods tool.
+type comparePeriodsInput struct {
+	SiteURL               string                      `json:"site_url"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	DateRange             string                      `json:"date_range,omitempty"`
+	Comparison            string                      `json:"comparison,omitempty"`
+	Dimensions            []string                    `json:"dimensions,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	RowLimit              int                         `json:"row_limit,omitempty"`
+	AllRows               *bool                       `json:"all_rows,omitempty"`
+	MaxRows               int                         `json:"max_rows,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+// periodQuery is the query shared by both sides of a period comparison.
+type periodQuery struct {
+	SiteURL    string
+	StartDate  string
+	EndDate    string
+	DateRange  string
+	Comparison string
+	Dimensions []string
+	SearchType string
+	RowLimit   int
+	Options    searchconsole.SearchAnalyticsOptions
+
+	// TopRowsOnly fetches only the first RowLimit rows of each period instead
+	// of paging through every row, at the risk of rows just past the limit in
+	// one period being reported as new or lost.
+	TopRowsOnly bool
+}
+
+func comparePeriods(ctx context.Context, client *searchconsole.Client, input comparePeriodsInput) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*analysis.PeriodComparison]("comparing periods", nil, err)
+	}
+	query := periodQuery{
+		SiteURL:    input.SiteURL,
+		StartDate:  input.StartDate,
+		EndDate:    input.EndDate,
+		DateRange:  input.DateRange,
+		Comparison: input.Comparison,
+		Dimensions: input.Dimensions,
+		SearchType: input.SearchType,
+		RowLimit:   input.RowLimit,
+		Options: searchconsole.SearchAnalyticsOptions{
+			DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+			MaxRows:               input.MaxRows,
+		},
+		TopRowsOnly: input.AllRows != nil && !*input.AllRows,
+	}
+	current, previous, comparison, err := queryPeriodPair(ctx, client, query)
+	if err != nil {
+		return marshalToolResult[*analysis.PeriodComparison]("comparing periods", nil, err)
+	}
+	currentTotals, previousTotals, err := queryPeriodTotals(ctx, client, query, current, previous)
+	if err != nil {
+		return marshalToolResult[*analysis.PeriodComparison]("comparing periods", nil, err)
+	}
+	result := analysis.ComparePeriods(current, previous, currentTotals, previousTotals, comparison)
+	return formatToolResult("comparing periods", input.OutputFormat, input.Dimensions, result, nil)
+}
+
+// queryPeriodPair runs query for its own period and for the comparison period,
+// returning both responses and the effective comparison mode. Both periods are
+// paged through every row unless query.TopRowsOnly is set. The second query
+// reuses the property the first one resolved to.
+func queryPeriodPair(
+	ctx context.Context,
+	client *searchconsole.Client,
+	query periodQuery,
+) (current, previous *searchconsole.SearchAnalyticsResponse, comparison string, err error) {
+	startDate, endDate, err := resolveDateInput(query.DateRange, query.StartDate, query.EndDate)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	comparison = query.Comparison
+	if comparison == "" {
+		comparison = defaultComparison
+	}
+	previousStart, previousEnd, err := searchconsole.ComparisonDateRange(startDate, endDate, comparison)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	query.Options.AllRows = !query.TopRowsOnly
+
+	current, err = client.QuerySearchAnalytics(
+		ctx, query.SiteURL, startDate, endDate, query.Dimensions, query.RowLimit, query.SearchType, query.Options)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	previous, err = client.QuerySearchAnalytics(
+		ctx, current.SiteURL, previousStart, previousEnd, query.Dimensions, query.RowLimit, query.SearchType, query.Options)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return current, previous, comparison, nil
+}
+
+// queryPeriodTotals returns the totals of the two periods queryPeriodPair
+// returned, from dimensionless queries that Search Console answers for the
+// whole property. Summing the dimensioned rows instead would miss rows past
+// the row limit and queries Google anonymizes. Without dimensions the period
+// responses already hold the totals.
+func queryPeriodTotals(
+	ctx context.Context,
+	client *searchconsole.Client,
+	query periodQuery,
+	current, previous *searchconsole.SearchAnalyticsResponse,
+) (currentTotals, previousTotals searchconsole.SearchAnalyticsRow, err error) {
+	if len(query.Dimensions) == 0 {
+		return analysis.Totals(current.Rows), analysis.Totals(previous.Rows), nil
+	}
+	options := searchconsole.SearchAnalyticsOptions{
+		DimensionFilterGroups: query.Options.DimensionFilterGroups,
+		AggregationType:       query.Options.AggregationType,
+		DataState:             query.Options.DataState,
+	}
+	totals := make([]searchconsole.SearchAnalyticsRow, 0, 2)
+	for _, period := range []*searchconsole.SearchAnalyticsResponse{current, previous} {
+		resp, err := client.QuerySearchAnalytics(
+			ctx, current.SiteURL, period.StartDate, period.EndDate, nil, 0, query.SearchType, options)
+		if err != nil {
+			return searchconsole.SearchAnalyticsRow{}, searchconsole.SearchAnalyticsRow{}, err
+		}
+		totals = append(totals, analysis.Totals(resp.Rows))
+	}
+	return totals[0], totals[1], nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// newPeriodServer fakes the Search Console API, answering each search
+// analytics request with the rows registered for its startDate.
+func newPeriodServer(t *testing.T, rowsByStartDate map[string]string, requests *[]map[string]any) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if requests != nil {
+			*requests = append(*requests, body)
+		}
+		rows, ok := rowsByStartDate[body["startDate"].(string)]
+		if !ok {
+			rows = "[]"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"rows":` + rows + `}`))
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(searchconsole.SetTestAPIBaseURL(srv.URL))
+	return srv
+}
+
+func TestComparePeriods_PreviousPeriod_JoinsBothQueries(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, map[string]string{
+		"2026-02-08": `[{"keys":["blazor"],"clicks":30,"impressions":300,"ctr":0.1,"position":3},{"keys":["new one"],"clicks":2,"impressions":20,"ctr":0.1,"position":9}]`,
+		"2026-02-01": `[{"keys":["blazor"],"clicks":10,"impressions":200,"ctr":0.05,"position":5}]`,
+	}, &requests)
+
+	client := searchconsole.NewTestClient(srv.Client())
+	result, _, err := comparePeriods(context.Background(), client, comparePeriodsInput{
+		SiteURL:    "devleader.ca",
+		StartDate:  "2026-02-08",
+		EndDate:    "2026-02-14",
+		Dimensions: []string{"query"},
+	})
+	if err != nil {
+		t.Fatalf("comparePeriods: %v", err)
+	}
+
+	// Both periods, then a dimensionless totals query for each.
+	if len(requests) != 4 {
+		t.Fatalf("request count = %d, want 4", len(requests))
+	}
+	if requests[1]["startDate"] != "2026-02-01" || requests[1]["endDate"] != "2026-02-07" {
+		t.Errorf("comparison request dates = %v..%v, want 2026-02-01..2026-02-07", requests[1]["startDate"], requests[1]["endDate"])
+	}
+	if requests[0]["rowLimit"] != float64(25000) {
+		t.Errorf("rowLimit = %v, want 25000 (paging through every row by default)", requests[0]["rowLimit"])
+	}
+	if requests[2]["dimensions"] != nil || requests[3]["startDate"] != "2026-02-01" {
+		t.Errorf("totals requests = %v, %v; want dimensionless queries for both periods", requests[2], requests[3])
+	}
+
+	var payload analysis.PeriodComparison
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if payload.Comparison != "previous_period" || payload.RowCount != 2 {
+		t.Errorf("comparison = %q, rowCount = %d; want previous_period and 2", payload.Comparison, payload.RowCount)
+	}
+	if payload.Rows[0].Keys[0] != "blazor" || payload.Rows[0].Clicks.Change != 20 {
+		t.Errorf("first row = %+v, want blazor with +20 clicks", payload.Rows[0])
+	}
+	if payload.Rows[1].Status != "new" {
+		t.Errorf("second row status = %q, want new", payload.Rows[1].Status)
+	}
+	if payload.Totals.Clicks.Current != 32 || payload.Totals.Clicks.Previous != 10 {
+		t.Errorf("totals clicks = %+v, want 32 vs 10", payload.Totals.Clicks)
+	}
+}
+
+func TestComparePeriods_TotalsComeFromDimensionlessQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		rows := `[{"keys":["blazor"],"clicks":10,"impressions":100,"ctr":0.1,"position":3}]`
+		if body["dimensions"] == nil {
+			// The property totals include rows past the limit and anonymized queries.
+			rows = `[{"clicks":50,"impressions":1000,"ctr":0.05,"position":7}]`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"rows":` + rows + `}`))
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(searchconsole.SetTestAPIBaseURL(srv.URL))
+
+	client := searchconsole.NewTestClient(srv.Client())
+	allRows := false
+	result, _, err := comparePeriods(context.Background(), client, comparePeriodsInput{
+		SiteURL:    "devleader.ca",
+		StartDate:  "2026-02-08",
+		EndDate:    "2026-02-14",
+		Dimensions: []string{"query"},
+		RowLimit:   1,
+		AllRows:    &allRows,
+	})
+	if err != nil {
+		t.Fatalf("comparePeriods: %v", err)
+	}
+
+	var payload analysis.PeriodComparison
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if !payload.Truncated {
+		t.Error("truncated = false, want true with all_rows false and row_limit 1")
+	}
+	if payload.Totals.Clicks.Current != 50 || payload.Totals.Impressions.Previous != 1000 {
+		t.Errorf("totals = %+v / %+v, want the dimensionless 50 clicks / 1000 impressions", payload.Totals.Clicks, payload.Totals.Impressions)
+	}
+	if *payload.Totals.Position.Current != 7 {
+		t.Errorf("total position = %v, want 7", *payload.Totals.Position.Current)
+	}
+}
+
+func TestComparePeriods_YearOverYear_QueriesSameDatesLastYear(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+
+	client := searchconsole.NewTestClient(srv.Client())
+	_, _, err := comparePeriods(context.Background(), client, comparePeriodsInput{
+		SiteURL:    "devleader.ca",
+		StartDate:  "2026-02-01",
+		EndDate:    "2026-02-28",
+		Comparison: "year_over_year",
+	})
+	if err != nil {
+		t.Fatalf("comparePeriods: %v", err)
+	}
+	if len(requests) != 2 || requests[1]["startDate"] != "2025-02-01" || requests[1]["endDate"] != "2025-02-28" {
+		t.Errorf("requests = %v, want a 2025-02-01..2025-02-28 comparison", requests)
+	}
+	if requests[1]["dimensions"] != nil {
+		t.Errorf("comparison request dimensions = %v, want none", requests[1]["dimensions"])
+	}
+}
+
+func TestComparePeriods_InvalidComparison_ReturnsErrorContentWithoutHTTPCall(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+
+	client := searchconsole.NewTestClient(srv.Client())
+	result, _, err := comparePeriods(context.Background(), client, comparePeriodsInput{
+		SiteURL:    "devleader.ca",
+		StartDate:  "2026-02-01",
+		EndDate:    "2026-02-28",
+		Comparison: "last_year",
+	})
+	if err != nil {
+		t.Fatalf("comparePeriods returned a Go error instead of error content: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "comparing periods:") || !strings.Contains(text, "invalid comparison") {
+		t.Errorf("result text = %q, want an invalid comparison error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected 0 HTTP calls, got %d", len(requests))
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const (
+	defaultCTRAnomalyMinImpressions = 100
+	defaultCTRAnomalyMaxRatio       = 0.5
+	defaultCTRAnomalyLimit          = 50
+)
+
+// findCTRAnomaliesInput is the input schema for the find_ctr_anomalies tool.
+type findCTRAnomaliesInput struct {
+	SiteURL               string                      `json:"site_url"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	DateRange             string                      `json:"date_range,omitempty"`
+	Dimension             string                      `json:"dimension,omitempty"`
+	MinImpressions        *float64                    `json:"min_impressions,omitempty"`
+	MaxCTRRatio           float64                     `json:"max_ctr_ratio,omitempty"`
+	Limit                 int                         `json:"limit,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	MaxRows               int                         `json:"max_rows,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+func findCTRAnomalies(ctx context.Context, client *searchconsole.Client, input findCTRAnomaliesInput) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*analysis.CTRAnomalyResult]("finding CTR anomalies", nil, err)
+	}
+	dimension := input.Dimension
+	if dimension == "" {
+		dimension = "query"
+	}
+	if dimension != "query" && dimension != "page" {
+		err := fmt.Errorf("invalid dimension %q: must be query or page", dimension)
+		return marshalToolResult[*analysis.CTRAnomalyResult]("finding CTR anomalies", nil, err)
+	}
+	options := analysis.CTRAnomalyOptions{
+		MinImpressions: defaultCTRAnomalyMinImpressions,
+		MaxCTRRatio:    input.MaxCTRRatio,
+		Limit:          input.Limit,
+	}
+	if input.MinImpressions != nil {
+		options.MinImpressions = *input.MinImpressions
+	}
+	if options.MaxCTRRatio == 0 {
+		options.MaxCTRRatio = defaultCTRAnomalyMaxRatio
+	}
+	if options.Limit == 0 {
+		options.Limit = defaultCTRAnomalyLimit
+	}
+	if err := options.Validate(); err != nil {
+		return marshalToolResult[*analysis.CTRAnomalyResult]("finding CTR anomalies", nil, err)
+	}
+
+	startDate, endDate, err := resolveDateInput(input.DateRange, input.StartDate, input.EndDate)
+	if err != nil {
+		return marshalToolResult[*analysis.CTRAnomalyResult]("finding CTR anomalies", nil, err)
+	}
+	queryOptions := searchconsole.SearchAnalyticsOptions{
+		DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+		AllRows:               true,
+		MaxRows:               input.MaxRows,
+	}
+	// The curve is always fitted from query rows: a page's position averages
+	// over every query it ranks for, which blurs the position-to-CTR relation.
+	queryResp, err := client.QuerySearchAnalytics(
+		ctx, input.SiteURL, startDate, endDate, []string{"query"}, 0, input.SearchType, queryOptions)
+	if err != nil {
+		return marshalToolResult[*analysis.CTRAnomalyResult]("finding CTR anomalies", nil, err)
+	}
+	resp := queryResp
+	if dimension == "page" {
+		resp, err = client.QuerySearchAnalytics(
+			ctx, queryResp.SiteURL, startDate, endDate, []string{"page"}, 0, input.SearchType, queryOptions)
+		if err != nil {
+			return marshalToolResult[*analysis.CTRAnomalyResult]("finding CTR anomalies", nil, err)
+		}
+		resp.Truncated = resp.Truncated || queryResp.Truncated
+	}
+
+	result, err := analysis.FindCTRAnomalies(resp, analysis.FitCTRCurve(queryResp.Rows), options)
+	return formatToolResult("finding CTR anomalies", input.OutputFormat, []string{dimension}, result, err)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// TestFindCTRAnomalies_PageDimension_FitsCurveFromQueryRows confirms pages are
+// judged against a curve fitted from query rows, not from the page rows.
+func TestFindCTRAnomalies_PageDimension_FitsCurveFromQueryRows(t *testing.T) {
+	var gotDimensions []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Dimensions []string `json:"dimensions"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotDimensions = append(gotDimensions, body.Dimensions[0])
+		w.Header().Set("Content-Type", "application/json")
+		if body.Dimensions[0] == "query" {
+			_, _ = w.Write([]byte(`{"rows":[{"keys":["blazor"],"clicks":300,"impressions":1000,"ctr":0.3,"position":2}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"rows":[
+			{"keys":["/weak"],"clicks":10,"impressions":1000,"ctr":0.01,"position":2},
+			{"keys":["/fine"],"clicks":280,"impressions":1000,"ctr":0.28,"position":2}
+		]}`))
+	}))
+	defer srv.Close()
+	defer searchconsole.SetTestAPIBaseURL(srv.URL)()
+
+	client := searchconsole.NewTestClient(srv.Client())
+	result, _, err := findCTRAnomalies(context.Background(), client, findCTRAnomaliesInput{
+		SiteURL:   "devleader.ca",
+		StartDate: "2026-02-01",
+		EndDate:   "2026-02-28",
+		Dimension: "page",
+	})
+	if err != nil {
+		t.Fatalf("findCTRAnomalies: %v", err)
+	}
+
+	if strings.Join(gotDimensions, ",") != "query,page" {
+		t.Errorf("requested dimensions = %v, want query then page", gotDimensions)
+	}
+	var payload analysis.CTRAnomalyResult
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if payload.Dimension != "page" || payload.AnomalyCount != 1 || payload.Anomalies[0].Key != "/weak" {
+		t.Errorf("payload = %+v, want only /weak flagged", payload)
+	}
+	if len(payload.CTRCurve.Points) != 1 || payload.CTRCurve.Points[0].CTR != 0.3 {
+		t.Errorf("ctrCurve = %+v, want the query-fitted 0.3 at position 2", payload.CTRCurve)
+	}
+}
+
+func TestFindCTRAnomalies_InvalidDimension_ReturnsErrorContentWithoutHTTPCall(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+
+	client := searchconsole.NewTestClient(srv.Client())
+	result, _, err := findCTRAnomalies(context.Background(), client, findCTRAnomaliesInput{
+		SiteURL:   "devleader.ca",
+		DateRange: "last_28_days",
+		Dimension: "country",
+	})
+	if err != nil {
+		t.Fatalf("findCTRAnomalies returned a Go error instead of error content: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "finding CTR anomalies:") || !strings.Contains(text, "must be query or page") {
+		t.Errorf("result text = %q, want an invalid dimension error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected 0 HTTP calls, got %d", len(requests))
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const (
+	defaultAnomalyBaselineWeeks = 4
+	// defaultAnomalyThreshold is the robust z-score Iglewicz and Hoaglin
+	// recommend for flagging outliers.
+	defaultAnomalyThreshold = 3.5
+	defaultAnomalyLimit     = 50
+)
+
+// anomalySplitDimensions are the dimensions detect_anomalies can split each
+// daily series by.
+var anomalySplitDimensions = []string{"device", "country", "page"}
+
+// detectAnomaliesInput is the input schema for the detect_anomalies tool.
+type detectAnomaliesInput struct {
+	SiteURL               string                      `json:"site_url"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	DateRange             string                      `json:"date_range,omitempty"`
+	Metric                string                      `json:"metric,omitempty"`
+	SplitBy               string                      `json:"split_by,omitempty"`
+	BaselineWeeks         int                         `json:"baseline_weeks,omitempty"`
+	Threshold             float64                     `json:"threshold,omitempty"`
+	MinImpressions        float64                     `json:"min_impressions,omitempty"`
+	Limit                 int                         `json:"limit,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	MaxRows               int                         `json:"max_rows,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+func detectAnomalies(ctx context.Context, client *searchconsole.Client, input detectAnomaliesInput) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*analysis.AnomalyResult]("detecting anomalies", nil, err)
+	}
+	if input.SplitBy != "" && !slices.Contains(anomalySplitDimensions, input.SplitBy) {
+		err := fmt.Errorf("invalid split_by %q: must be one of device, country, page", input.SplitBy)
+		return marshalToolResult[*analysis.AnomalyResult]("detecting anomalies", nil, err)
+	}
+	options := analysis.AnomalyOptions{
+		Metric:         input.Metric,
+		BaselineWeeks:  input.BaselineWeeks,
+		Threshold:      input.Threshold,
+		MinImpressions: input.MinImpressions,
+		Limit:          input.Limit,
+	}
+	if options.Metric == "" {
+		options.Metric = analysis.AnomalyMetricClicks
+	}
+	if options.BaselineWeeks == 0 {
+		options.BaselineWeeks = defaultAnomalyBaselineWeeks
+	}
+	if options.Threshold == 0 {
+		options.Threshold = defaultAnomalyThreshold
+	}
+	if options.Limit == 0 {
+		options.Limit = defaultAnomalyLimit
+	}
+	if err := options.Validate(); err != nil {
+		return marshalToolResult[*analysis.AnomalyResult]("detecting anomalies", nil, err)
+	}
+
+	startDate, endDate, err := resolveDateInput(input.DateRange, input.StartDate, input.EndDate)
+	if err != nil {
+		return marshalToolResult[*analysis.AnomalyResult]("detecting anomalies", nil, err)
+	}
+	start, err := time.Parse(time.DateOnly, startDate)
+	if err != nil {
+		return marshalToolResult[*analysis.AnomalyResult]("detecting anomalies", nil, fmt.Errorf("invalid start_date %q: must be YYYY-MM-DD", startDate))
+	}
+	// Fetch enough history before the requested range for its first day to
+	// have a full baseline, but none from before Search Console's retention,
+	// whose days would look like zeros.
+	options.ScoreFrom = startDate
+	baselineStart := start.AddDate(0, 0, -7*options.BaselineWeeks).Format(time.DateOnly)
+	if earliest := searchconsole.EarliestRetainedDate().Format(time.DateOnly); baselineStart < earliest {
+		baselineStart = min(earliest, startDate)
+	}
+
+	dimensions := []string{"date"}
+	if input.SplitBy != "" {
+		dimensions = append(dimensions, input.SplitBy)
+	}
+	resp, err := client.QuerySearchAnalytics(
+		ctx, input.SiteURL, baselineStart, endDate, dimensions, 0, input.SearchType,
+		searchconsole.SearchAnalyticsOptions{
+			DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+			AllRows:               true,
+			MaxRows:               input.MaxRows,
+		})
+	if err != nil {
+		return marshalToolResult[*analysis.AnomalyResult]("detecting anomalies", nil, err)
+	}
+	result, err := analysis.DetectAnomalies(resp, options)
+	return formatToolResult("detecting anomalies", input.OutputFormat, dimensions[1:], result, err)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestDetectAnomalies_FetchesBaselineHistoryBeforeRange(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+
+	client := searchconsole.NewTestClient(srv.Client())
+	result, _, err := detectAnomalies(context.Background(), client, detectAnomaliesInput{
+		SiteURL:       "devleader.ca",
+		StartDate:     "2026-03-01",
+		EndDate:       "2026-03-31",
+		SplitBy:       "device",
+		BaselineWeeks: 3,
+	})
+	if err != nil {
+		t.Fatalf("detectAnomalies: %v", err)
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("request count = %d, want 1", len(requests))
+	}
+	if requests[0]["startDate"] != "2026-02-08" || requests[0]["endDate"] != "2026-03-31" {
+		t.Errorf("request dates = %v..%v, want 2026-02-08..2026-03-31", requests[0]["startDate"], requests[0]["endDate"])
+	}
+	if dims, _ := requests[0]["dimensions"].([]any); len(dims) != 2 || dims[0] != "date" || dims[1] != "device" {
+		t.Errorf("request dimensions = %v, want [date device]", requests[0]["dimensions"])
+	}
+
+	var payload analysis.AnomalyResult
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if payload.StartDate != "2026-03-01" || payload.BaselineStartDate != "2026-02-08" || payload.Metric != "clicks" || payload.Threshold != 3.5 {
+		t.Errorf("payload = %+v, want the requested range scored for clicks at threshold 3.5", payload)
+	}
+}
+
+func TestDetectAnomalies_ClampsBaselineToRetention(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+
+	// The pinned clock puts the start of retention at 2025-01-01.
+	client := searchconsole.NewTestClient(srv.Client())
+	if _, _, err := detectAnomalies(context.Background(), client, detectAnomaliesInput{
+		SiteURL:   "devleader.ca",
+		StartDate: "2025-01-10",
+		EndDate:   "2025-03-31",
+	}); err != nil {
+		t.Fatalf("detectAnomalies: %v", err)
+	}
+	if len(requests) != 1 || requests[0]["startDate"] != "2025-01-01" {
+		t.Errorf("requests = %v, want one starting on 2025-01-01", requests)
+	}
+}
+
+func TestDetectAnomalies_InvalidSplit_ReturnsErrorContentWithoutHTTPCall(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+
+	client := searchconsole.NewTestClient(srv.Client())
+	result, _, err := detectAnomalies(context.Background(), client, detectAnomaliesInput{
+		SiteURL:   "devleader.ca",
+		DateRange: "last_28_days",
+		SplitBy:   "query",
+	})
+	if err != nil {
+		t.Fatalf("detectAnomalies returned a Go error instead of error content: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "detecting anomalies:") || !strings.Contains(text, `invalid split_by \"query\"`) {
+		t.Errorf("result text = %q, want an invalid split_by error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected 0 HTTP calls, got %d", len(requests))
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const (
+	defaultCannibalizationMinShare = 0.1
+	defaultCannibalizationLimit    = 50
+)
+
+// findCannibalizationInput is the input schema for the find_cannibalization tool.
+type findCannibalizationInput struct {
+	SiteURL               string                      `json:"site_url"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	DateRange             string                      `json:"date_range,omitempty"`
+	MinShare              float64                     `json:"min_share,omitempty"`
+	MinImpressions        float64                     `json:"min_impressions,omitempty"`
+	Limit                 int                         `json:"limit,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	MaxRows               int                         `json:"max_rows,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+func findCannibalization(ctx context.Context, client *searchconsole.Client, input findCannibalizationInput) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*analysis.CannibalizationResult]("finding cannibalization", nil, err)
+	}
+	options := analysis.CannibalizationOptions{
+		MinShare:       input.MinShare,
+		MinImpressions: input.MinImpressions,
+		Limit:          input.Limit,
+	}
+	if options.MinShare == 0 {
+		options.MinShare = defaultCannibalizationMinShare
+	}
+	if options.Limit == 0 {
+		options.Limit = defaultCannibalizationLimit
+	}
+	if err := options.Validate(); err != nil {
+		return marshalToolResult[*analysis.CannibalizationResult]("finding cannibalization", nil, err)
+	}
+
+	startDate, endDate, err := resolveDateInput(input.DateRange, input.StartDate, input.EndDate)
+	if err != nil {
+		return marshalToolResult[*analysis.CannibalizationResult]("finding cannibalization", nil, err)
+	}
+	resp, err := client.QuerySearchAnalytics(
+		ctx, input.SiteURL, startDate, endDate, []string{"query", "page"}, 0, input.SearchType,
+		searchconsole.SearchAnalyticsOptions{
+			DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+			AllRows:               true,
+			MaxRows:               input.MaxRows,
+		})
+	if err != nil {
+		return marshalToolResult[*analysis.CannibalizationResult]("finding cannibalization", nil, err)
+	}
+	result, err := analysis.FindCannibalization(resp, options)
+	return formatToolResult("finding cannibalization", input.OutputFormat, nil, result, err)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestFindCannibalization_QueriesQueryAndPageRows(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, map[string]string{
+		"2026-02-01": `[
+			{"keys":["blazor forms","/a"],"clicks":30,"impressions":600,"ctr":0.05,"position":4},
+			{"keys":["blazor forms","/b"],"clicks":10,"impressions":400,"ctr":0.025,"position":8}
+		]`,
+	}, &requests)
+
+	client := searchconsole.NewTestClient(srv.Client())
+	result, _, err := findCannibalization(context.Background(), client, findCannibalizationInput{
+		SiteURL:   "devleader.ca",
+		StartDate: "2026-02-01",
+		EndDate:   "2026-02-28",
+	})
+	if err != nil {
+		t.Fatalf("findCannibalization: %v", err)
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("request count = %d, want 1", len(requests))
+	}
+	if dims, _ := requests[0]["dimensions"].([]any); len(dims) != 2 || dims[0] != "query" || dims[1] != "page" {
+		t.Errorf("request dimensions = %v, want [query page]", requests[0]["dimensions"])
+	}
+	if requests[0]["rowLimit"] != float64(25000) {
+		t.Errorf("request rowLimit = %v, want 25000 (paging every row)", requests[0]["rowLimit"])
+	}
+
+	var payload analysis.CannibalizationResult
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if payload.MinShare != 0.1 || payload.CannibalizedCount != 1 || payload.Queries[0].Query != "blazor forms" {
+		t.Errorf("payload = %+v, want blazor forms flagged at the default 0.1 share", payload)
+	}
+}
+
+func TestFindCannibalization_InvalidShare_ReturnsErrorContentWithoutHTTPCall(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+
+	client := searchconsole.NewTestClient(srv.Client())
+	result, _, err := findCannibalization(context.Background(), client, findCannibalizationInput{
+		SiteURL:   "devleader.ca",
+		DateRange: "last_28_days",
+		MinShare:  0.75,
+	})
+	if err != nil {
+		t.Fatalf("findCannibalization returned a Go error instead of error content: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "finding cannibalization:") || !strings.Contains(text, "invalid min_share 0.75") {
+		t.Errorf("result text = %q, want an invalid min_share error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected 0 HTTP calls, got %d", len(requests))
+	}
+}
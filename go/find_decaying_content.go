@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const (
+	defaultDecayMonths          = 6
+	defaultDecayMinDecline      = 30
+	defaultDecaySustainedMonths = 3
+	defaultDecayMinPeakClicks   = 10
+	defaultDecayLimit           = 25
+	defaultDecayTopQueries      = 5
+)
+
+// findDecayingContentInput is the input schema for the find_decaying_content tool.
+type findDecayingContentInput struct {
+	SiteURL               string                      `json:"site_url"`
+	Months                int                         `json:"months,omitempty"`
+	MinDecline            float64                     `json:"min_decline,omitempty"`
+	SustainedMonths       int                         `json:"sustained_months,omitempty"`
+	MinPeakClicks         *float64                    `json:"min_peak_clicks,omitempty"`
+	Limit                 int                         `json:"limit,omitempty"`
+	TopQueries            int                         `json:"top_queries,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	MaxRows               int                         `json:"max_rows,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+// calendarMonth is the first and last day of one calendar month.
+type calendarMonth struct {
+	start, end time.Time
+}
+
+// completeMonths returns the count most recent calendar months whose every
+// day has settled data, oldest first.
+func completeMonths(count int) []calendarMonth {
+	latest := searchconsole.LatestCompleteDate()
+	// The month containing latest is complete only if latest is its last day.
+	last := time.Date(latest.Year(), latest.Month(), 1, 0, 0, 0, 0, latest.Location())
+	if latest.AddDate(0, 0, 1).Day() != 1 {
+		last = last.AddDate(0, -1, 0)
+	}
+	months := make([]calendarMonth, count)
+	for i := range months {
+		start := last.AddDate(0, i-(count-1), 0)
+		months[i] = calendarMonth{start: start, end: start.AddDate(0, 1, -1)}
+	}
+	return months
+}
+
+func findDecayingContent(ctx context.Context, client *searchconsole.Client, input findDecayingContentInput) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*analysis.DecayResult]("finding decaying content", nil, err)
+	}
+	options := analysis.DecayOptions{
+		Months:          input.Months,
+		MinDecline:      input.MinDecline,
+		SustainedMonths: input.SustainedMonths,
+		MinPeakClicks:   defaultDecayMinPeakClicks,
+		Limit:           input.Limit,
+	}
+	if options.Months == 0 {
+		options.Months = defaultDecayMonths
+	}
+	if options.MinDecline == 0 {
+		options.MinDecline = defaultDecayMinDecline
+	}
+	if options.SustainedMonths == 0 {
+		options.SustainedMonths = defaultDecaySustainedMonths
+	}
+	if input.MinPeakClicks != nil {
+		options.MinPeakClicks = *input.MinPeakClicks
+	}
+	if options.Limit == 0 {
+		options.Limit = defaultDecayLimit
+	}
+	if err := options.Validate(); err != nil {
+		return marshalToolResult[*analysis.DecayResult]("finding decaying content", nil, err)
+	}
+	topQueries := input.TopQueries
+	if topQueries == 0 {
+		topQueries = defaultDecayTopQueries
+	}
+	if topQueries < 0 {
+		err := fmt.Errorf("invalid top_queries %d: must be positive", topQueries)
+		return marshalToolResult[*analysis.DecayResult]("finding decaying content", nil, err)
+	}
+
+	queryMonth := func(siteURL string, month calendarMonth, dimensions []string) (*searchconsole.SearchAnalyticsResponse, error) {
+		return client.QuerySearchAnalytics(
+			ctx, siteURL, month.start.Format(time.DateOnly), month.end.Format(time.DateOnly), dimensions, 0, input.SearchType,
+			searchconsole.SearchAnalyticsOptions{
+				DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+				AllRows:               true,
+				MaxRows:               input.MaxRows,
+			})
+	}
+
+	// Later queries reuse the property the first one resolved to.
+	siteURL := input.SiteURL
+	months := completeMonths(options.Months)
+	byKey := make(map[string]calendarMonth, len(months))
+	monthly := make([]analysis.MonthlyResponse, len(months))
+	for i, month := range months {
+		resp, err := queryMonth(siteURL, month, []string{"page"})
+		if err != nil {
+			return marshalToolResult[*analysis.DecayResult]("finding decaying content", nil, err)
+		}
+		siteURL = resp.SiteURL
+		key := month.start.Format("2006-01")
+		byKey[key] = month
+		monthly[i] = analysis.MonthlyResponse{Month: key, Response: resp}
+	}
+	result, err := analysis.FindDecayingContent(monthly, options)
+	if err != nil || len(result.Pages) == 0 {
+		return formatToolResult("finding decaying content", input.OutputFormat, nil, result, err)
+	}
+
+	queries := make(map[string]*searchconsole.SearchAnalyticsResponse)
+	for _, key := range append(result.PeakMonths(), monthly[len(monthly)-1].Month) {
+		if queries[key] != nil {
+			continue
+		}
+		resp, err := queryMonth(siteURL, byKey[key], []string{"page", "query"})
+		if err != nil {
+			return marshalToolResult[*analysis.DecayResult]("finding decaying content", nil, err)
+		}
+		result.Truncated = result.Truncated || resp.Truncated
+		queries[key] = resp
+	}
+	err = analysis.AddQueryDrops(result, queries, topQueries)
+	return formatToolResult("finding decaying content", input.OutputFormat, nil, result, err)
+}
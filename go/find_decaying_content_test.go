@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestFindDecayingContent_QueriesCompleteMonthsAndDrillsIntoPeaks(t *testing.T) {
+	// The test clock is May 1, 2026, so the latest complete month is March.
+	var requests []map[string]any
+	srv := newPeriodServer(t, map[string]string{
+		"2025-12-01": `[{"keys":["https://devleader.ca/a"],"clicks":100,"impressions":1000,"ctr":0.1,"position":3}]`,
+		"2026-01-01": `[{"keys":["https://devleader.ca/a"],"clicks":120,"impressions":1100,"ctr":0.1,"position":3}]`,
+		"2026-02-01": `[{"keys":["https://devleader.ca/a"],"clicks":40,"impressions":900,"ctr":0.04,"position":6}]`,
+		"2026-03-01": `[{"keys":["https://devleader.ca/a"],"clicks":30,"impressions":800,"ctr":0.04,"position":7}]`,
+	}, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := findDecayingContent(context.Background(), client, findDecayingContentInput{
+		SiteURL:         "devleader.ca",
+		Months:          4,
+		SustainedMonths: 2,
+	})
+	if err != nil {
+		t.Fatalf("findDecayingContent: %v", err)
+	}
+	var payload struct {
+		Months []string `json:"months"`
+		Pages  []struct {
+			Page           string  `json:"page"`
+			PeakMonth      string  `json:"peakMonth"`
+			CurrentMonth   string  `json:"currentMonth"`
+			DeclinePercent float64 `json:"declinePercent"`
+		} `json:"pages"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if strings.Join(payload.Months, ",") != "2025-12,2026-01,2026-02,2026-03" {
+		t.Errorf("months = %v, want December through March", payload.Months)
+	}
+	if len(payload.Pages) != 1 || payload.Pages[0].PeakMonth != "2026-01" || payload.Pages[0].CurrentMonth != "2026-03" || payload.Pages[0].DeclinePercent != 75 {
+		t.Errorf("pages = %+v, want /a peaking in January", payload.Pages)
+	}
+
+	var ranges []string
+	for _, request := range requests {
+		ranges = append(ranges, request["startDate"].(string)+".."+request["endDate"].(string)+" "+jsonString(request["dimensions"]))
+	}
+	want := []string{
+		`2025-12-01..2025-12-31 ["page"]`,
+		`2026-01-01..2026-01-31 ["page"]`,
+		`2026-02-01..2026-02-28 ["page"]`,
+		`2026-03-01..2026-03-31 ["page"]`,
+		`2026-01-01..2026-01-31 ["page","query"]`,
+		`2026-03-01..2026-03-31 ["page","query"]`,
+	}
+	if strings.Join(ranges, "|") != strings.Join(want, "|") {
+		t.Errorf("requests = %q, want %q", ranges, want)
+	}
+}
+
+func TestFindDecayingContent_InvalidOptions_ReturnsErrorContent(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := findDecayingContent(context.Background(), client, findDecayingContentInput{SiteURL: "devleader.ca", Months: 20})
+	if err != nil {
+		t.Fatalf("findDecayingContent: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "finding decaying content: invalid months 20") {
+		t.Errorf("result = %s, want an invalid months error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("made %d requests, want 0", len(requests))
+	}
+}
+
+func jsonString(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
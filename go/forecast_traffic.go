@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const (
+	// defaultForecastHistory is the history forecast_traffic fits when no
+	// dates are passed: long enough for the weekly pattern and the trend to
+	// settle, short enough to reflect the site as it is now.
+	defaultForecastHistory     = "last_180_days"
+	defaultForecastHorizonDays = 90
+	defaultForecastConfidence  = 95
+)
+
+// forecastTrafficInput is the input schema for the forecast_traffic tool.
+type forecastTrafficInput struct {
+	SiteURL               string                      `json:"site_url"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	DateRange             string                      `json:"date_range,omitempty"`
+	HorizonDays           int                         `json:"horizon_days,omitempty"`
+	Confidence            float64                     `json:"confidence,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+func forecastTraffic(ctx context.Context, client *searchconsole.Client, input forecastTrafficInput) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*analysis.ForecastResult]("forecasting traffic", nil, err)
+	}
+	options := analysis.ForecastOptions{
+		HorizonDays: input.HorizonDays,
+		Confidence:  input.Confidence,
+	}
+	if options.HorizonDays == 0 {
+		options.HorizonDays = defaultForecastHorizonDays
+	}
+	if options.Confidence == 0 {
+		options.Confidence = defaultForecastConfidence
+	}
+	if err := options.Validate(); err != nil {
+		return marshalToolResult[*analysis.ForecastResult]("forecasting traffic", nil, err)
+	}
+
+	dateRange := input.DateRange
+	if dateRange == "" && input.StartDate == "" && input.EndDate == "" {
+		dateRange = defaultForecastHistory
+	}
+	startDate, endDate, err := resolveDateInput(dateRange, input.StartDate, input.EndDate)
+	if err != nil {
+		return marshalToolResult[*analysis.ForecastResult]("forecasting traffic", nil, err)
+	}
+	// Days after the latest complete date are still being reported, and
+	// their partial numbers would drag the fit down.
+	if latest := searchconsole.LatestCompleteDate().Format(time.DateOnly); endDate > latest {
+		endDate = latest
+	}
+	resp, err := client.QuerySearchAnalytics(
+		ctx, input.SiteURL, startDate, endDate, []string{"date"}, 0, input.SearchType,
+		searchconsole.SearchAnalyticsOptions{
+			DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+			AllRows:               true,
+		})
+	if err != nil {
+		return marshalToolResult[*analysis.ForecastResult]("forecasting traffic", nil, err)
+	}
+	// Days without a row had no traffic; the model needs every day. Filling
+	// stops at the last day with data, so no zeros are invented at the end.
+	if err := analysis.FillDateGaps(resp); err != nil {
+		return marshalToolResult[*analysis.ForecastResult]("forecasting traffic", nil, err)
+	}
+	result, err := analysis.ForecastTraffic(resp, options)
+	return formatToolResult("forecasting traffic", input.OutputFormat, nil, result, err)
+}
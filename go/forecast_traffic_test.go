@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestForecastTraffic_FillsMissingDaysAndForecastsFromEndDate(t *testing.T) {
+	// Six weeks of date rows, with 2026-03-10 missing.
+	var rows []string
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for day := range 42 {
+		date := start.AddDate(0, 0, day).Format(time.DateOnly)
+		if date == "2026-03-10" {
+			continue
+		}
+		rows = append(rows, fmt.Sprintf(`{"keys":[%q],"clicks":%d,"impressions":%d,"ctr":0.1,"position":4}`, date, 10+day%7, 100+10*(day%7)))
+	}
+	var requests []map[string]any
+	srv := newPeriodServer(t, map[string]string{"2026-03-01": "[" + strings.Join(rows, ",") + "]"}, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := forecastTraffic(context.Background(), client, forecastTrafficInput{
+		SiteURL:     "devleader.ca",
+		StartDate:   "2026-03-01",
+		EndDate:     "2026-04-11",
+		HorizonDays: 14,
+	})
+	if err != nil {
+		t.Fatalf("forecastTraffic: %v", err)
+	}
+	var payload struct {
+		TrainingEnd   string  `json:"trainingEnd"`
+		ForecastStart string  `json:"forecastStart"`
+		ForecastEnd   string  `json:"forecastEnd"`
+		Confidence    float64 `json:"confidence"`
+		Forecasts     []struct {
+			Metric      string `json:"metric"`
+			Diagnostics struct {
+				Observations int `json:"observations"`
+			} `json:"diagnostics"`
+			Points []json.RawMessage `json:"points"`
+		} `json:"forecasts"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if payload.TrainingEnd != "2026-04-11" || payload.ForecastStart != "2026-04-12" || payload.ForecastEnd != "2026-04-25" || payload.Confidence != 95 {
+		t.Errorf("payload = %+v, want 14 days from 2026-04-12 at 95%%", payload)
+	}
+	if len(payload.Forecasts) != 2 || payload.Forecasts[0].Metric != "clicks" || payload.Forecasts[1].Metric != "impressions" {
+		t.Fatalf("forecasts = %+v, want clicks and impressions", payload.Forecasts)
+	}
+	if payload.Forecasts[0].Diagnostics.Observations != 42 || len(payload.Forecasts[0].Points) != 14 {
+		t.Errorf("clicks = %+v, want 42 observations and 14 points", payload.Forecasts[0])
+	}
+
+	if len(requests) != 1 || jsonString(requests[0]["dimensions"]) != `["date"]` {
+		t.Errorf("requests = %v, want one date query", requests)
+	}
+}
+
+func TestForecastTraffic_DefaultsToLast180Days(t *testing.T) {
+	// The test clock is May 1, 2026, so the latest complete date is April 29.
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	if _, _, err := forecastTraffic(context.Background(), client, forecastTrafficInput{SiteURL: "devleader.ca"}); err != nil {
+		t.Fatalf("forecastTraffic: %v", err)
+	}
+	if len(requests) != 1 || requests[0]["startDate"] != "2025-11-01" || requests[0]["endDate"] != "2026-04-29" {
+		t.Errorf("requests = %v, want 2025-11-01..2026-04-29", requests)
+	}
+}
+
+func TestForecastTraffic_ClampsEndDateToLatestCompleteDate(t *testing.T) {
+	// The test clock is May 1, 2026, so the latest complete date is April 29.
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	if _, _, err := forecastTraffic(context.Background(), client, forecastTrafficInput{
+		SiteURL:   "devleader.ca",
+		StartDate: "2026-02-01",
+		EndDate:   "2026-05-01",
+	}); err != nil {
+		t.Fatalf("forecastTraffic: %v", err)
+	}
+	if len(requests) != 1 || requests[0]["endDate"] != "2026-04-29" {
+		t.Errorf("requests = %v, want end date 2026-04-29", requests)
+	}
+}
+
+func TestForecastTraffic_InvalidOptions_ReturnsErrorContent(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := forecastTraffic(context.Background(), client, forecastTrafficInput{SiteURL: "devleader.ca", Confidence: 75})
+	if err != nil {
+		t.Fatalf("forecastTraffic: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "forecasting traffic: invalid confidence 75") {
+		t.Errorf("result = %s, want an invalid confidence error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("request count = %d, want 0", len(requests))
+	}
+}
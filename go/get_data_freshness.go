@@ -0,0 +1,19 @@
+package main
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// getDataFreshnessInput is the input schema for the get_data_freshness tool.
+type getDataFreshnessInput struct {
+	SiteURL    string `json:"site_url"`
+	SearchType string `json:"search_type,omitempty"`
+}
+
+func getDataFreshness(ctx context.Context, client *searchconsole.Client, input getDataFreshnessInput) (*mcp.CallToolResult, any, error) {
+	result, err := client.GetDataFreshness(ctx, input.SiteURL, input.SearchType)
+	return marshalToolResult("getting data freshness", result, err)
+}
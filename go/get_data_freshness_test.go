@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestGetDataFreshness_InvalidSearchType_ReturnsErrorContent(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := getDataFreshness(context.Background(), client, getDataFreshnessInput{SiteURL: "devleader.ca", SearchType: "shopping"})
+	if err != nil {
+		t.Fatalf("getDataFreshness: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "getting data freshness: invalid search_type") {
+		t.Errorf("result = %s, want an invalid search_type error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("made %d requests, want 0", len(requests))
+	}
+}
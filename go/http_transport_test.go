@@ -81,8 +81,8 @@ func TestHTTPTransport_ServesRealSession(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ListTools: %v", err)
 	}
-	if len(tools.Tools) != 4 {
-		t.Errorf("tools = %d, want 4", len(tools.Tools))
+	if len(tools.Tools) != 21 {
+		t.Errorf("tools = %d, want 21", len(tools.Tools))
 	}
 
 	result, err := session.CallTool(ctx, &mcp.CallToolParams{
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/config"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const defaultIntentTopQueries = 5
+
+// intentRulesInput is the intent_rules argument of the tools that classify
+// queries by search intent; it has the shape of an intents entry of the
+// analysis config.
+type intentRulesInput struct {
+	Informational   []string `json:"informational,omitempty"`
+	Navigational    []string `json:"navigational,omitempty"`
+	Commercial      []string `json:"commercial,omitempty"`
+	Transactional   []string `json:"transactional,omitempty"`
+	QuestionWords   []string `json:"question_words,omitempty"`
+	ReplaceDefaults bool     `json:"replace_defaults,omitempty"`
+}
+
+// intentRollupInput is the input schema for the intent_rollup tool.
+type intentRollupInput struct {
+	SiteURL               string                      `json:"site_url"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	DateRange             string                      `json:"date_range,omitempty"`
+	IntentRules           *intentRulesInput           `json:"intent_rules,omitempty"`
+	BrandTerms            []string                    `json:"brand_terms,omitempty"`
+	BrandPatterns         []string                    `json:"brand_patterns,omitempty"`
+	TopQueries            *int                        `json:"top_queries,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	MaxRows               int                         `json:"max_rows,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+func intentRollup(
+	ctx context.Context,
+	client *searchconsole.Client,
+	analysisConfig config.AnalysisConfig,
+	input intentRollupInput,
+) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*analysis.IntentRollupResult]("rolling up intents", nil, err)
+	}
+	classifier, err := intentClassifierFor(analysisConfig, input.SiteURL, input.IntentRules, input.BrandTerms, input.BrandPatterns)
+	if err != nil {
+		return marshalToolResult[*analysis.IntentRollupResult]("rolling up intents", nil, err)
+	}
+	topQueries := defaultIntentTopQueries
+	if input.TopQueries != nil {
+		topQueries = *input.TopQueries
+	}
+	if topQueries < 0 {
+		err := fmt.Errorf("invalid top_queries %d: must not be negative", topQueries)
+		return marshalToolResult[*analysis.IntentRollupResult]("rolling up intents", nil, err)
+	}
+
+	startDate, endDate, err := resolveDateInput(input.DateRange, input.StartDate, input.EndDate)
+	if err != nil {
+		return marshalToolResult[*analysis.IntentRollupResult]("rolling up intents", nil, err)
+	}
+	resp, err := client.QuerySearchAnalytics(
+		ctx, input.SiteURL, startDate, endDate, []string{"query"}, 0, input.SearchType,
+		searchconsole.SearchAnalyticsOptions{
+			DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+			AllRows:               true,
+			MaxRows:               input.MaxRows,
+		})
+	if err != nil {
+		return marshalToolResult[*analysis.IntentRollupResult]("rolling up intents", nil, err)
+	}
+	result, err := analysis.RollupIntents(resp, classifier, topQueries)
+	return formatToolResult("rolling up intents", input.OutputFormat, nil, result, err)
+}
+
+// intentClassifierFor returns the intent classifier for a tool call on
+// siteURL. Rules passed with the call override the analysis config's intents
+// entry for the site; either extends the built-in rules unless it sets
+// replace_defaults. Queries naming the brand are navigational: brand terms
+// and patterns passed with the call override the config's brands entry, and
+// without either, brands are not considered.
+func intentClassifierFor(
+	analysisConfig config.AnalysisConfig,
+	siteURL string,
+	rules *intentRulesInput,
+	brandTerms, brandPatterns []string,
+) (*analysis.IntentClassifier, error) {
+	if rules == nil {
+		if entry, ok := propertyConfigFor(analysisConfig.Intents, siteURL); ok {
+			rules = (*intentRulesInput)(&entry)
+		}
+	}
+	intentRules := analysis.DefaultIntentRules()
+	if rules != nil {
+		custom := analysis.IntentRules{
+			Informational: rules.Informational,
+			Navigational:  rules.Navigational,
+			Commercial:    rules.Commercial,
+			Transactional: rules.Transactional,
+			QuestionWords: rules.QuestionWords,
+		}
+		if rules.ReplaceDefaults {
+			intentRules = custom
+		} else {
+			intentRules = intentRules.Extend(custom)
+		}
+	}
+
+	var brand *analysis.BrandMatcher
+	if len(brandTerms) > 0 || len(brandPatterns) > 0 {
+		matcher, err := analysis.NewBrandMatcher(brandTerms, brandPatterns)
+		if err != nil {
+			return nil, err
+		}
+		brand = matcher
+	} else if entry, ok := propertyConfigFor(analysisConfig.Brands, siteURL); ok {
+		matcher, err := analysis.NewBrandMatcher(entry.Terms, entry.Patterns)
+		if err != nil {
+			return nil, err
+		}
+		brand = matcher
+	}
+	return analysis.NewIntentClassifier(intentRules, brand)
+}
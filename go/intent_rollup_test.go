@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/config"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const intentRows = `[
+	{"keys":["devleader blazor"],"clicks":50,"impressions":100,"ctr":0.5,"position":1},
+	{"keys":["blazor course"],"clicks":10,"impressions":300,"ctr":0.03,"position":6},
+	{"keys":["how to use blazor"],"clicks":5,"impressions":200,"ctr":0.025,"position":8}
+]`
+
+func TestQuerySearchAnalytics_ClassifyIntent_UsesConfiguredRulesAndBrands(t *testing.T) {
+	srv := newPeriodServer(t, map[string]string{"2026-02-01": intentRows}, nil)
+	client := searchconsole.NewTestClient(srv.Client())
+	cfg := config.AnalysisConfig{
+		Brands:  map[string]config.BrandConfig{"devleader.ca": {Terms: []string{"dev leader"}}},
+		Intents: map[string]config.IntentConfig{"*": {Commercial: []string{"course"}}},
+	}
+
+	result, _, err := querySearchAnalytics(context.Background(), client, cfg, querySearchAnalyticsInput{
+		SiteURL:         "devleader.ca",
+		StartDate:       "2026-02-01",
+		EndDate:         "2026-02-28",
+		Dimensions:      []string{"query"},
+		ClassifyIntent:  true,
+		NamedDimensions: true,
+	})
+	if err != nil {
+		t.Fatalf("querySearchAnalytics: %v", err)
+	}
+	var payload struct {
+		Intents []searchconsole.SegmentTotals `json:"intents"`
+		Rows    []searchconsole.NamedRow      `json:"rows"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	var intents []string
+	for _, r := range payload.Rows {
+		intents = append(intents, r.Intent)
+	}
+	if strings.Join(intents, ",") != "navigational,commercial,informational" {
+		t.Errorf("row intents = %v, want navigational, commercial, informational", intents)
+	}
+	if len(payload.Intents) != 3 || payload.Intents[0].Segment != "informational" || payload.Intents[0].Clicks != 5 {
+		t.Errorf("intents = %+v", payload.Intents)
+	}
+}
+
+func TestIntentRollup_PerCallRulesOverrideConfig(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, map[string]string{"2026-02-01": intentRows}, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+	cfg := config.AnalysisConfig{Intents: map[string]config.IntentConfig{"*": {Commercial: []string{"course"}}}}
+
+	result, _, err := intentRollup(context.Background(), client, cfg, intentRollupInput{
+		SiteURL:     "devleader.ca",
+		StartDate:   "2026-02-01",
+		EndDate:     "2026-02-28",
+		IntentRules: &intentRulesInput{Transactional: []string{"course"}},
+		BrandTerms:  []string{"devleader"},
+	})
+	if err != nil {
+		t.Fatalf("intentRollup: %v", err)
+	}
+	var payload analysis.IntentRollupResult
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	clicks := map[string]float64{}
+	for _, group := range payload.Intents {
+		clicks[group.Intent] = group.Clicks
+	}
+	if clicks["navigational"] != 50 || clicks["transactional"] != 10 || clicks["commercial"] != 0 || clicks["informational"] != 5 {
+		t.Errorf("clicks by intent = %v", clicks)
+	}
+	if len(requests) != 1 || requests[0]["rowLimit"] != float64(25000) || jsonString(requests[0]["dimensions"]) != `["query"]` {
+		t.Errorf("requests = %v, want every query row", requests)
+	}
+}
+
+func TestIntentRollup_InvalidInput_ReturnsErrorContentWithoutHTTPCall(t *testing.T) {
+	negative := -1
+	for _, tt := range []struct {
+		name   string
+		input  intentRollupInput
+		marker string
+	}{
+		{name: "top queries", input: intentRollupInput{TopQueries: &negative}, marker: "invalid top_queries -1"},
+		{name: "empty rules", input: intentRollupInput{IntentRules: &intentRulesInput{ReplaceDefaults: true}}, marker: "at least one intent keyword"},
+		{name: "bad brand pattern", input: intentRollupInput{BrandPatterns: []string{"("}}, marker: "invalid brand pattern"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []map[string]any
+			srv := newPeriodServer(t, nil, &requests)
+			client := searchconsole.NewTestClient(srv.Client())
+
+			tt.input.SiteURL = "devleader.ca"
+			tt.input.DateRange = "last_28_days"
+			result, _, err := intentRollup(context.Background(), client, config.AnalysisConfig{}, tt.input)
+			if err != nil {
+				t.Fatalf("intentRollup returned a Go error instead of error content: %v", err)
+			}
+			text := result.Content[0].(*mcp.TextContent).Text
+			if !strings.Contains(text, "rolling up intents:") || !strings.Contains(text, tt.marker) {
+				t.Errorf("result text = %q, want it to contain %q", text, tt.marker)
+			}
+			if len(requests) != 0 {
+				t.Errorf("expected 0 HTTP calls, got %d", len(requests))
+			}
+		})
+	}
+}
+
+func TestQuerySearchAnalytics_ClassifyIntentWithoutQuery_ReturnsErrorContent(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, querySearchAnalyticsInput{
+		SiteURL:        "devleader.ca",
+		DateRange:      "last_28_days",
+		Dimensions:     []string{"page"},
+		ClassifyIntent: true,
+	})
+	if err != nil {
+		t.Fatalf("querySearchAnalytics: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "classify_intent requires the query dimension") {
+		t.Errorf("result = %s, want a missing query dimension error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("request count = %d, want 0", len(requests))
+	}
+}
@@ -0,0 +1,333 @@
+package analysis
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// Metrics DetectAnomalies can score.
+const (
+	AnomalyMetricClicks      = "clicks"
+	AnomalyMetricImpressions = "impressions"
+	AnomalyMetricCTR         = "ctr"
+	AnomalyMetricPosition    = "position"
+)
+
+// AnomalyMetrics lists every metric DetectAnomalies accepts.
+var AnomalyMetrics = []string{AnomalyMetricClicks, AnomalyMetricImpressions, AnomalyMetricCTR, AnomalyMetricPosition}
+
+// madScale converts a median absolute deviation into an estimate of the
+// standard deviation of normally distributed data.
+const madScale = 1.4826
+
+// minAnomalyScale is the smallest spread DetectAnomalies assumes for each
+// metric, so a perfectly flat baseline does not turn a one-click wobble into
+// an enormous z-score.
+var minAnomalyScale = map[string]float64{
+	AnomalyMetricClicks:      1,
+	AnomalyMetricImpressions: 1,
+	AnomalyMetricCTR:         0.001,
+	AnomalyMetricPosition:    0.1,
+}
+
+// Anomaly directions.
+const (
+	DirectionSpike = "spike"
+	DirectionDrop  = "drop"
+)
+
+// AnomalyOptions controls DetectAnomalies. Each day is scored against the
+// BaselineWeeks weeks before it; days before ScoreFrom (YYYY-MM-DD) only serve
+// as baseline. A day is flagged when its robust z-score reaches Threshold in
+// either direction. Series with fewer than MinImpressions in total are
+// skipped, and at most Limit anomalies are returned.
+type AnomalyOptions struct {
+	Metric         string
+	BaselineWeeks  int
+	Threshold      float64
+	MinImpressions float64
+	ScoreFrom      string
+	Limit          int
+}
+
+// Validate reports whether o can be used, so callers can reject it before
+// fetching any rows.
+func (o AnomalyOptions) Validate() error {
+	if !slices.Contains(AnomalyMetrics, o.Metric) {
+		return fmt.Errorf("invalid metric %q: must be one of clicks, impressions, ctr, position", o.Metric)
+	}
+	if o.BaselineWeeks < 2 || o.BaselineWeeks > 12 {
+		return fmt.Errorf("invalid baseline_weeks %d: must be between 2 and 12", o.BaselineWeeks)
+	}
+	if o.Threshold <= 0 {
+		return fmt.Errorf("invalid threshold %v: must be positive", o.Threshold)
+	}
+	if o.MinImpressions < 0 {
+		return fmt.Errorf("invalid min_impressions %v: must not be negative", o.MinImpressions)
+	}
+	if o.Limit <= 0 {
+		return fmt.Errorf("invalid limit %d: must be positive", o.Limit)
+	}
+	return nil
+}
+
+// Anomaly is one day that broke from its series' weekday-adjusted trend.
+// Expected is the baseline for that day, Deviation is Value minus Expected,
+// and ZScore is Deviation over the baseline's robust spread. For position, a
+// drop means the value fell, which is an improvement in ranking.
+type Anomaly struct {
+	Date      string   `json:"date"`
+	Keys      []string `json:"keys,omitempty"`
+	Value     float64  `json:"value"`
+	Expected  float64  `json:"expected"`
+	Deviation float64  `json:"deviation"`
+	ZScore    float64  `json:"zScore"`
+	Direction string   `json:"direction"`
+}
+
+// AnomalyResult is the result of DetectAnomalies. StartDate and EndDate are
+// the days scored, ending on the last date with data; BaselineStartDate is
+// the first day of history used.
+// SeriesCount counts the series scored, and AnomalyCount the anomalies found,
+// of which at most the requested limit are listed, by |zScore|.
+type AnomalyResult struct {
+	SiteURL           string                      `json:"siteUrl"`
+	StartDate         string                      `json:"startDate"`
+	EndDate           string                      `json:"endDate"`
+	BaselineStartDate string                      `json:"baselineStartDate"`
+	SplitBy           string                      `json:"splitBy,omitempty"`
+	SearchType        string                      `json:"searchType"`
+	Metric            string                      `json:"metric"`
+	BaselineWeeks     int                         `json:"baselineWeeks"`
+	Threshold         float64                     `json:"threshold"`
+	SeriesCount       int                         `json:"seriesCount"`
+	AnomalyCount      int                         `json:"anomalyCount"`
+	Truncated         bool                        `json:"truncated"`
+	Warnings          []searchconsole.DataWarning `json:"warnings,omitempty"`
+	Anomalies         []Anomaly                   `json:"anomalies"`
+	QueriedAt         time.Time                   `json:"queriedAt"`
+}
+
+// DetectAnomalies scores each day of each series in resp, whose first
+// dimension must be date and whose optional second dimension splits the
+// series, against a weekday-adjusted rolling baseline.
+//
+// For each day, the baseline is the BaselineWeeks*7 days before it. The
+// expected value is the median of the baseline days falling on the same
+// weekday, which absorbs weekly cycles. The spread is the median absolute
+// deviation of every baseline day from its own weekday median, scaled to a
+// standard deviation. Days with fewer than two same-weekday baseline values
+// are not scored. Search Console omits days without impressions, so missing
+// days count as zero clicks and impressions, and as no value for CTR and
+// position. Days before the first or after the last date with any data have
+// no value at all, since Search Console may not have them yet or any longer,
+// and a truncated response is an error, since the rows cut off would look
+// like drops.
+func DetectAnomalies(resp *searchconsole.SearchAnalyticsResponse, options AnomalyOptions) (*AnomalyResult, error) {
+	if len(resp.Dimensions) == 0 || len(resp.Dimensions) > 2 || resp.Dimensions[0] != "date" {
+		return nil, errors.New("anomaly detection requires the date dimension first, optionally followed by one split dimension")
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	if resp.Truncated {
+		return nil, errors.New("cannot detect anomalies in a truncated response: rows beyond the row limit would look like drops")
+	}
+	first, err := time.Parse(time.DateOnly, resp.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date %q: %w", resp.StartDate, err)
+	}
+	last, err := time.Parse(time.DateOnly, resp.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date %q: %w", resp.EndDate, err)
+	}
+	scoreFrom := first
+	if options.ScoreFrom != "" {
+		if scoreFrom, err = time.Parse(time.DateOnly, options.ScoreFrom); err != nil {
+			return nil, fmt.Errorf("invalid score-from date %q: %w", options.ScoreFrom, err)
+		}
+	}
+	days := int(last.Sub(first).Hours()/24) + 1
+
+	type series struct {
+		keys        []string
+		rows        map[int]searchconsole.SearchAnalyticsRow
+		impressions float64
+	}
+	var order []string
+	seriesByKey := make(map[string]*series)
+	// The days with any data; the rest of the range has no values.
+	firstData, lastData := days, -1
+	for _, row := range resp.Rows {
+		if len(row.Keys) != len(resp.Dimensions) {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, row.Keys[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid date key %q: %w", row.Keys[0], err)
+		}
+		key := joinKeys(row.Keys[1:])
+		s, ok := seriesByKey[key]
+		if !ok {
+			s = &series{keys: row.Keys[1:], rows: make(map[int]searchconsole.SearchAnalyticsRow)}
+			seriesByKey[key] = s
+			order = append(order, key)
+		}
+		day := int(date.Sub(first).Hours() / 24)
+		s.rows[day] = row
+		s.impressions += row.Impressions
+		firstData, lastData = min(firstData, day), max(lastData, day)
+	}
+
+	var anomalies []Anomaly
+	scored := 0
+	for _, key := range order {
+		s := seriesByKey[key]
+		if s.impressions < options.MinImpressions {
+			continue
+		}
+		scored++
+		values := make([]float64, days)
+		for i := range values {
+			values[i] = math.NaN()
+			if i >= firstData && i <= lastData {
+				values[i] = metricValue(s.rows, i, options.Metric)
+			}
+		}
+		for i := range days {
+			day := first.AddDate(0, 0, i)
+			if day.Before(scoreFrom) || math.IsNaN(values[i]) {
+				continue
+			}
+			expected, spread, ok := weekdayBaseline(values, i, options.BaselineWeeks)
+			if !ok {
+				continue
+			}
+			deviation := values[i] - expected
+			z := deviation / max(spread, minAnomalyScale[options.Metric])
+			if math.Abs(z) < options.Threshold {
+				continue
+			}
+			direction := DirectionSpike
+			if deviation < 0 {
+				direction = DirectionDrop
+			}
+			var keys []string
+			if len(s.keys) > 0 {
+				keys = s.keys
+			}
+			anomalies = append(anomalies, Anomaly{
+				Date:      day.Format(time.DateOnly),
+				Keys:      keys,
+				Value:     values[i],
+				Expected:  expected,
+				Deviation: deviation,
+				ZScore:    z,
+				Direction: direction,
+			})
+		}
+	}
+	slices.SortStableFunc(anomalies, func(a, b Anomaly) int { return cmp.Compare(math.Abs(b.ZScore), math.Abs(a.ZScore)) })
+
+	splitBy := ""
+	if len(resp.Dimensions) == 2 {
+		splitBy = resp.Dimensions[1]
+	}
+	baselineStart, end := resp.StartDate, resp.EndDate
+	if lastData >= 0 {
+		baselineStart = first.AddDate(0, 0, firstData).Format(time.DateOnly)
+		end = first.AddDate(0, 0, lastData).Format(time.DateOnly)
+	}
+	return &AnomalyResult{
+		SiteURL:           resp.SiteURL,
+		StartDate:         scoreFrom.Format(time.DateOnly),
+		EndDate:           end,
+		BaselineStartDate: baselineStart,
+		SplitBy:           splitBy,
+		SearchType:        resp.SearchType,
+		Metric:            options.Metric,
+		BaselineWeeks:     options.BaselineWeeks,
+		Threshold:         options.Threshold,
+		SeriesCount:       scored,
+		AnomalyCount:      len(anomalies),
+		Truncated:         resp.Truncated,
+		Warnings:          mergeWarnings(resp),
+		Anomalies:         firstN(anomalies, options.Limit),
+		QueriedAt:         resp.QueriedAt,
+	}, nil
+}
+
+// metricValue returns metric for day i of a series, or NaN when the series
+// has no value that day.
+func metricValue(rows map[int]searchconsole.SearchAnalyticsRow, i int, metric string) float64 {
+	row, ok := rows[i]
+	switch metric {
+	case AnomalyMetricClicks:
+		return row.Clicks
+	case AnomalyMetricImpressions:
+		return row.Impressions
+	}
+	if !ok {
+		return math.NaN()
+	}
+	if metric == AnomalyMetricCTR {
+		return row.CTR
+	}
+	return row.Position
+}
+
+// weekdayBaseline returns the expected value and robust spread for day i
+// from the weeks*7 days before it, or false when there is too little history.
+func weekdayBaseline(values []float64, i, weeks int) (expected, spread float64, ok bool) {
+	if i < weeks*7 {
+		return 0, 0, false
+	}
+	// The median of each weekday in the window; offset 0 is day i's weekday.
+	var weekdayMedians [7]float64
+	for offset := range 7 {
+		var same []float64
+		for d := i - 7 + offset; d >= i-weeks*7; d -= 7 {
+			if !math.IsNaN(values[d]) {
+				same = append(same, values[d])
+			}
+		}
+		if len(same) < 2 {
+			if offset == 0 {
+				return 0, 0, false
+			}
+			weekdayMedians[offset] = math.NaN()
+			continue
+		}
+		weekdayMedians[offset] = median(same)
+	}
+
+	var residuals []float64
+	for d := i - weeks*7; d < i; d++ {
+		m := weekdayMedians[(d-i+weeks*7)%7]
+		if !math.IsNaN(values[d]) && !math.IsNaN(m) {
+			residuals = append(residuals, math.Abs(values[d]-m))
+		}
+	}
+	return weekdayMedians[0], madScale * median(residuals), true
+}
+
+// median returns the median of values, or 0 for none. It does not modify
+// values.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
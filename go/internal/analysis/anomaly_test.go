@@ -0,0 +1,192 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// weeklySeries returns days of date+device rows starting on Monday
+// 2026-01-05, with weekday clicks around 100, weekend clicks around 40, and a
+// little deterministic noise. override replaces the clicks of given days.
+func weeklySeries(device string, days int, override map[int]float64) []searchconsole.SearchAnalyticsRow {
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	rows := make([]searchconsole.SearchAnalyticsRow, 0, days)
+	for i := range days {
+		clicks := 100.0
+		if i%7 >= 5 {
+			clicks = 40
+		}
+		clicks += float64(i%3 - 1)
+		if v, ok := override[i]; ok {
+			clicks = v
+		}
+		rows = append(rows, row([]string{start.AddDate(0, 0, i).Format(time.DateOnly), device}, clicks, clicks*10, 5))
+	}
+	return rows
+}
+
+func anomalyOptions() analysis.AnomalyOptions {
+	return analysis.AnomalyOptions{
+		Metric:        "clicks",
+		BaselineWeeks: 4,
+		Threshold:     3.5,
+		ScoreFrom:     "2026-02-02",
+		Limit:         10,
+	}
+}
+
+func TestDetectAnomalies_FlagsDropButNotWeekendDips(t *testing.T) {
+	t.Parallel()
+
+	rows := append(weeklySeries("DESKTOP", 42, nil), weeklySeries("MOBILE", 42, map[int]float64{36: 30})...)
+	resp := &searchconsole.SearchAnalyticsResponse{
+		StartDate:  "2026-01-05",
+		EndDate:    "2026-02-15",
+		Dimensions: []string{"date", "device"},
+		Rows:       rows,
+	}
+
+	got, err := analysis.DetectAnomalies(resp, anomalyOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.SeriesCount != 2 || got.SplitBy != "device" {
+		t.Errorf("seriesCount/splitBy = %d/%q, want 2/device", got.SeriesCount, got.SplitBy)
+	}
+	if got.StartDate != "2026-02-02" || got.BaselineStartDate != "2026-01-05" {
+		t.Errorf("dates = %s (baseline %s), want 2026-02-02 (baseline 2026-01-05)", got.StartDate, got.BaselineStartDate)
+	}
+	if got.AnomalyCount != 1 {
+		t.Fatalf("anomalies = %+v, want only the MOBILE drop", got.Anomalies)
+	}
+	a := got.Anomalies[0]
+	if a.Date != "2026-02-10" || len(a.Keys) != 1 || a.Keys[0] != "MOBILE" || a.Direction != "drop" {
+		t.Errorf("anomaly = %+v, want a MOBILE drop on 2026-02-10", a)
+	}
+	if a.Value != 30 || a.Expected < 99 || a.Expected > 101 || a.ZScore > -10 {
+		t.Errorf("anomaly = %+v, want value 30 against about 100 with a large negative zScore", a)
+	}
+}
+
+func TestDetectAnomalies_MissingDaysCountAsZeroClicks(t *testing.T) {
+	t.Parallel()
+
+	rows := weeklySeries("", 42, nil)
+	// Search Console omits days without impressions entirely.
+	rows = append(rows[:38], rows[39:]...)
+	for i := range rows {
+		rows[i].Keys = rows[i].Keys[:1]
+	}
+	resp := &searchconsole.SearchAnalyticsResponse{
+		StartDate:  "2026-01-05",
+		EndDate:    "2026-02-15",
+		Dimensions: []string{"date"},
+		Rows:       rows,
+	}
+
+	got, err := analysis.DetectAnomalies(resp, anomalyOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.AnomalyCount != 1 || got.Anomalies[0].Date != "2026-02-12" || got.Anomalies[0].Value != 0 || got.Anomalies[0].Keys != nil {
+		t.Errorf("anomalies = %+v, want a zero-click drop on 2026-02-12 with no keys", got.Anomalies)
+	}
+
+	ctr := anomalyOptions()
+	ctr.Metric = "ctr"
+	got, err = analysis.DetectAnomalies(resp, ctr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.AnomalyCount != 0 {
+		t.Errorf("ctr anomalies = %+v, want none: a missing day has no CTR", got.Anomalies)
+	}
+}
+
+func TestDetectAnomalies_DaysOutsideTheDataAreNotZeros(t *testing.T) {
+	t.Parallel()
+
+	// The range starts three weeks before the first row, as when it predates
+	// retention, and ends three days after the last, as when Search Console
+	// has not reported them yet.
+	rows := weeklySeries("", 39, nil)
+	for i := range rows {
+		rows[i].Keys = rows[i].Keys[:1]
+	}
+	options := anomalyOptions()
+	options.ScoreFrom = "2026-01-12"
+	got, err := analysis.DetectAnomalies(&searchconsole.SearchAnalyticsResponse{
+		StartDate: "2025-12-15", EndDate: "2026-02-15", Dimensions: []string{"date"}, Rows: rows,
+	}, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.AnomalyCount != 0 {
+		t.Errorf("anomalies = %+v, want none: days without data are neither spikes nor drops", got.Anomalies)
+	}
+	if got.BaselineStartDate != "2026-01-05" || got.EndDate != "2026-02-12" {
+		t.Errorf("dates = %s..%s, want 2026-01-05..2026-02-12, the days with data", got.BaselineStartDate, got.EndDate)
+	}
+}
+
+func TestDetectAnomalies_TruncatedResponse_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	_, err := analysis.DetectAnomalies(&searchconsole.SearchAnalyticsResponse{
+		StartDate: "2026-01-05", EndDate: "2026-02-15", Dimensions: []string{"date"}, Truncated: true,
+	}, anomalyOptions())
+	if err == nil || !strings.Contains(err.Error(), "truncated response") {
+		t.Errorf("error = %v, want a truncated response error", err)
+	}
+}
+
+func TestDetectAnomalies_TooLittleHistory_ScoresNothing(t *testing.T) {
+	t.Parallel()
+
+	rows := weeklySeries("", 14, map[int]float64{13: 0})
+	for i := range rows {
+		rows[i].Keys = rows[i].Keys[:1]
+	}
+	options := anomalyOptions()
+	options.ScoreFrom = ""
+	got, err := analysis.DetectAnomalies(&searchconsole.SearchAnalyticsResponse{
+		StartDate: "2026-01-05", EndDate: "2026-01-18", Dimensions: []string{"date"}, Rows: rows,
+	}, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.AnomalyCount != 0 {
+		t.Errorf("anomalies = %+v, want none without %d weeks of baseline", got.Anomalies, options.BaselineWeeks)
+	}
+}
+
+func TestDetectAnomalies_InvalidInput_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	dated := &searchconsole.SearchAnalyticsResponse{StartDate: "2026-01-05", EndDate: "2026-02-15", Dimensions: []string{"date"}}
+	for _, tt := range []struct {
+		name   string
+		resp   *searchconsole.SearchAnalyticsResponse
+		modify func(*analysis.AnomalyOptions)
+		marker string
+	}{
+		{name: "no date dimension", resp: &searchconsole.SearchAnalyticsResponse{Dimensions: []string{"query"}}, modify: func(*analysis.AnomalyOptions) {}, marker: "requires the date dimension first"},
+		{name: "metric", resp: dated, modify: func(o *analysis.AnomalyOptions) { o.Metric = "revenue" }, marker: `invalid metric "revenue"`},
+		{name: "baseline", resp: dated, modify: func(o *analysis.AnomalyOptions) { o.BaselineWeeks = 1 }, marker: "invalid baseline_weeks 1"},
+		{name: "threshold", resp: dated, modify: func(o *analysis.AnomalyOptions) { o.Threshold = -1 }, marker: "invalid threshold"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			options := anomalyOptions()
+			tt.modify(&options)
+			_, err := analysis.DetectAnomalies(tt.resp, options)
+			if err == nil || !strings.Contains(err.Error(), tt.marker) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.marker)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// Brand segments, as set on SearchAnalyticsRow.Segment.
+const (
+	SegmentBranded    = "branded"
+	SegmentNonBranded = "non_branded"
+)
+
+// Trend granularities for BrandSplit.
+const (
+	GranularityDay  = "day"
+	GranularityWeek = "week"
+)
+
+// BrandMatcher decides whether a query mentions a brand.
+type BrandMatcher struct {
+	terms    []string
+	patterns []*regexp.Regexp
+}
+
+// NewBrandMatcher returns a matcher for the given terms and RE2 patterns. A
+// term matches case-insensitively as whole words, with or without the spaces
+// between them, so "dev leader" matches "devleader tutorials" and
+// "devleader" matches "dev leader", but "go" does not match "google".
+// Patterns match case-insensitively. At least one term or pattern is required.
+func NewBrandMatcher(terms, patterns []string) (*BrandMatcher, error) {
+	m := &BrandMatcher{}
+	for _, term := range terms {
+		compact := strings.Join(queryTokens(term), "")
+		if compact == "" {
+			continue
+		}
+		m.terms = append(m.terms, compact)
+	}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid brand pattern %q: %w", pattern, err)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	if len(m.terms) == 0 && len(m.patterns) == 0 {
+		return nil, errors.New("at least one brand term or pattern is required")
+	}
+	return m, nil
+}
+
+// IsBranded reports whether query matches any of m's terms or patterns.
+func (m *BrandMatcher) IsBranded(query string) bool {
+	tokens := queryTokens(query)
+	for _, term := range m.terms {
+		if containsCompact(tokens, term) {
+			return true
+		}
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(query) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsCompact reports whether a run of consecutive tokens, joined without
+// spaces, equals compact.
+func containsCompact(tokens []string, compact string) bool {
+	for i := range tokens {
+		joined := ""
+		for _, token := range tokens[i:] {
+			joined += token
+			if len(joined) >= len(compact) {
+				break
+			}
+		}
+		if joined == compact {
+			return true
+		}
+	}
+	return false
+}
+
+// SegmentByBrand marks every row of resp, which must include the query
+// dimension, as branded or non-branded, and sets resp.Segments to the totals
+// of each segment.
+func SegmentByBrand(resp *searchconsole.SearchAnalyticsResponse, matcher *BrandMatcher) error {
+	queryIndex := slices.Index(resp.Dimensions, "query")
+	if queryIndex < 0 {
+		return errors.New("brand segmentation requires the query dimension")
+	}
+
+	var branded, nonBranded []searchconsole.SearchAnalyticsRow
+	for i, row := range resp.Rows {
+		if queryIndex >= len(row.Keys) {
+			continue
+		}
+		if matcher.IsBranded(row.Keys[queryIndex]) {
+			resp.Rows[i].Segment = SegmentBranded
+			branded = append(branded, row)
+		} else {
+			resp.Rows[i].Segment = SegmentNonBranded
+			nonBranded = append(nonBranded, row)
+		}
+	}
+
+	resp.Segment = "brand"
+	resp.Segments = []searchconsole.SegmentTotals{
+		segmentTotals(SegmentBranded, branded),
+		segmentTotals(SegmentNonBranded, nonBranded),
+	}
+	return nil
+}
+
+func segmentTotals(segment string, rows []searchconsole.SearchAnalyticsRow) searchconsole.SegmentTotals {
+	total := Totals(rows)
+	return searchconsole.SegmentTotals{
+		Segment:     segment,
+		RowCount:    len(rows),
+		Clicks:      total.Clicks,
+		Impressions: total.Impressions,
+		CTR:         total.CTR,
+		Position:    total.Position,
+	}
+}
+
+// BrandTrendPoint is one day or week of a brand split, starting on StartDate.
+// BrandedClickShare is the branded fraction (0-1) of the period's clicks.
+type BrandTrendPoint struct {
+	StartDate         string                           `json:"startDate"`
+	Branded           searchconsole.SearchAnalyticsRow `json:"branded"`
+	NonBranded        searchconsole.SearchAnalyticsRow `json:"nonBranded"`
+	BrandedClickShare float64                          `json:"brandedClickShare"`
+}
+
+// BrandSplitResult is the result of BrandSplit. Branded and NonBranded compare
+// each segment's totals across the two periods, BrandedClickShare compares the
+// branded fraction of clicks, and Trend breaks the current period down by
+// Granularity.
+type BrandSplitResult struct {
+	SiteURL           string                      `json:"siteUrl"`
+	Comparison        string                      `json:"comparison"`
+	CurrentPeriod     Period                      `json:"currentPeriod"`
+	PreviousPeriod    Period                      `json:"previousPeriod"`
+	SearchType        string                      `json:"searchType"`
+	Branded           RowComparison               `json:"branded"`
+	NonBranded        RowComparison               `json:"nonBranded"`
+	BrandedClickShare MetricComparison            `json:"brandedClickShare"`
+	Granularity       string                      `json:"granularity"`
+	Trend             []BrandTrendPoint           `json:"trend"`
+	Truncated         bool                        `json:"truncated"`
+	Warnings          []searchconsole.DataWarning `json:"warnings,omitempty"`
+	QueriedAt         time.Time                   `json:"queriedAt"`
+}
+
+// BrandSplit splits current and previous, which must have the dimensions date
+// and query in that order, into branded and non-branded queries, and reports
+// each segment's totals in both periods plus a trend of the current period by
+// granularity ("day", or "week" for weeks starting on Monday).
+func BrandSplit(
+	current, previous *searchconsole.SearchAnalyticsResponse,
+	comparison string,
+	matcher *BrandMatcher,
+	granularity string,
+) (*BrandSplitResult, error) {
+	if !slices.Equal(current.Dimensions, []string{"date", "query"}) || !slices.Equal(previous.Dimensions, []string{"date", "query"}) {
+		return nil, errors.New("brand split requires the dimensions date and query, in that order")
+	}
+	if granularity != GranularityDay && granularity != GranularityWeek {
+		return nil, fmt.Errorf("invalid granularity %q: must be day or week", granularity)
+	}
+
+	currentBranded, currentNonBranded := splitByBrand(current.Rows, matcher)
+	previousBranded, previousNonBranded := splitByBrand(previous.Rows, matcher)
+	currentShare := clickShare(Totals(currentBranded), Totals(currentNonBranded))
+	previousShare := clickShare(Totals(previousBranded), Totals(previousNonBranded))
+
+	trend, err := brandTrend(current.Rows, matcher, granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BrandSplitResult{
+		SiteURL:           current.SiteURL,
+		Comparison:        comparison,
+		CurrentPeriod:     Period{StartDate: current.StartDate, EndDate: current.EndDate},
+		PreviousPeriod:    Period{StartDate: previous.StartDate, EndDate: previous.EndDate},
+		SearchType:        current.SearchType,
+		Branded:           compareRow(nil, Totals(currentBranded), Totals(previousBranded), true, true),
+		NonBranded:        compareRow(nil, Totals(currentNonBranded), Totals(previousNonBranded), true, true),
+		BrandedClickShare: compareMetric(currentShare, previousShare),
+		Granularity:       granularity,
+		Trend:             trend,
+		Truncated:         current.Truncated || previous.Truncated,
+		Warnings:          mergeWarnings(current, previous),
+		QueriedAt:         current.QueriedAt,
+	}, nil
+}
+
+// splitByBrand partitions date+query rows by whether their query is branded.
+func splitByBrand(rows []searchconsole.SearchAnalyticsRow, matcher *BrandMatcher) (branded, nonBranded []searchconsole.SearchAnalyticsRow) {
+	for _, row := range rows {
+		if len(row.Keys) != 2 {
+			continue
+		}
+		if matcher.IsBranded(row.Keys[1]) {
+			branded = append(branded, row)
+		} else {
+			nonBranded = append(nonBranded, row)
+		}
+	}
+	return branded, nonBranded
+}
+
+func brandTrend(rows []searchconsole.SearchAnalyticsRow, matcher *BrandMatcher, granularity string) ([]BrandTrendPoint, error) {
+	type bucket struct {
+		branded, nonBranded []searchconsole.SearchAnalyticsRow
+	}
+	buckets := make(map[string]*bucket)
+	for _, row := range rows {
+		if len(row.Keys) != 2 {
+			continue
+		}
+		date, err := time.Parse(time.DateOnly, row.Keys[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid date key %q: %w", row.Keys[0], err)
+		}
+		if granularity == GranularityWeek {
+			// Go's Weekday starts on Sunday; weeks here start on Monday.
+			date = date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+		}
+		key := date.Format(time.DateOnly)
+		b, ok := buckets[key]
+		if !ok {
+			b = &bucket{}
+			buckets[key] = b
+		}
+		if matcher.IsBranded(row.Keys[1]) {
+			b.branded = append(b.branded, row)
+		} else {
+			b.nonBranded = append(b.nonBranded, row)
+		}
+	}
+
+	starts := make([]string, 0, len(buckets))
+	for start := range buckets {
+		starts = append(starts, start)
+	}
+	slices.Sort(starts)
+	trend := make([]BrandTrendPoint, len(starts))
+	for i, start := range starts {
+		branded, nonBranded := Totals(buckets[start].branded), Totals(buckets[start].nonBranded)
+		trend[i] = BrandTrendPoint{
+			StartDate:         start,
+			Branded:           branded,
+			NonBranded:        nonBranded,
+			BrandedClickShare: clickShare(branded, nonBranded),
+		}
+	}
+	return trend, nil
+}
+
+// clickShare returns branded's fraction of the combined clicks, or 0 when
+// there were none.
+func clickShare(branded, nonBranded searchconsole.SearchAnalyticsRow) float64 {
+	total := branded.Clicks + nonBranded.Clicks
+	if total == 0 {
+		return 0
+	}
+	return branded.Clicks / total
+}
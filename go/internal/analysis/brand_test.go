@@ -0,0 +1,160 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestBrandMatcher_TermsIgnoreCaseAndSpacesPatternsIgnoreCase(t *testing.T) {
+	t.Parallel()
+
+	m, err := analysis.NewBrandMatcher([]string{"Dev Leader", " "}, []string{`^nick\s+cosentino`})
+	if err != nil {
+		t.Fatalf("NewBrandMatcher: %v", err)
+	}
+	for query, want := range map[string]bool{
+		"dev leader blazor":        true,
+		"devleader":                true,
+		"DEVLEADER youtube":        true,
+		"Nick Cosentino":           true,
+		"about nick cosentino":     false,
+		"blazor render modes":      false,
+		"developer leadership tip": false,
+	} {
+		if got := m.IsBranded(query); got != want {
+			t.Errorf("IsBranded(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestBrandMatcher_TermsMatchWholeWordsOnly(t *testing.T) {
+	t.Parallel()
+
+	m, err := analysis.NewBrandMatcher([]string{"ai", "go", "devleader"}, nil)
+	if err != nil {
+		t.Fatalf("NewBrandMatcher: %v", err)
+	}
+	for query, want := range map[string]bool{
+		"email marketing":       false,
+		"google analytics":      false,
+		"mango recipes":         false,
+		"ai tools":              true,
+		"learn go":              true,
+		"go-to guide":           true,
+		"dev leader blazor":     true,
+		"devleaders":            false,
+		"the devleader.ca blog": true,
+	} {
+		if got := m.IsBranded(query); got != want {
+			t.Errorf("IsBranded(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestNewBrandMatcher_InvalidInput_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	if _, err := analysis.NewBrandMatcher([]string{"  "}, nil); err == nil || !strings.Contains(err.Error(), "at least one brand term") {
+		t.Errorf("blank terms: error = %v", err)
+	}
+	if _, err := analysis.NewBrandMatcher(nil, []string{"(unclosed"}); err == nil || !strings.Contains(err.Error(), `invalid brand pattern "(unclosed"`) {
+		t.Errorf("bad pattern: error = %v", err)
+	}
+}
+
+func TestSegmentByBrand_MarksRowsAndTotalsEachSegment(t *testing.T) {
+	t.Parallel()
+
+	m, _ := analysis.NewBrandMatcher([]string{"devleader"}, nil)
+	resp := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"page", "query"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"/", "devleader"}, 50, 100, 1),
+			row([]string{"/blazor", "blazor forms"}, 10, 300, 6),
+			row([]string{"/blazor", "dev leader blazor"}, 10, 100, 3),
+		},
+	}
+
+	if err := analysis.SegmentByBrand(resp, m); err != nil {
+		t.Fatalf("SegmentByBrand: %v", err)
+	}
+	if resp.Segment != "brand" {
+		t.Errorf("Segment = %q, want brand", resp.Segment)
+	}
+	if resp.Rows[0].Segment != "branded" || resp.Rows[1].Segment != "non_branded" || resp.Rows[2].Segment != "branded" {
+		t.Errorf("row segments = %q, %q, %q", resp.Rows[0].Segment, resp.Rows[1].Segment, resp.Rows[2].Segment)
+	}
+	branded := resp.Segments[0]
+	if branded.Segment != "branded" || branded.RowCount != 2 || branded.Clicks != 60 || branded.Impressions != 200 || branded.Position != 2 {
+		t.Errorf("branded totals = %+v, want 2 rows, 60 clicks, 200 impressions, position 2", branded)
+	}
+	if resp.Segments[1].Segment != "non_branded" || resp.Segments[1].Clicks != 10 {
+		t.Errorf("non-branded totals = %+v", resp.Segments[1])
+	}
+
+	if err := analysis.SegmentByBrand(&searchconsole.SearchAnalyticsResponse{Dimensions: []string{"page"}}, m); err == nil {
+		t.Error("SegmentByBrand without the query dimension returned nil error")
+	}
+}
+
+func TestBrandSplit_ComparesSegmentsAndBuildsWeeklyTrend(t *testing.T) {
+	t.Parallel()
+
+	m, _ := analysis.NewBrandMatcher([]string{"devleader"}, nil)
+	current := &searchconsole.SearchAnalyticsResponse{
+		StartDate:  "2026-03-01",
+		EndDate:    "2026-03-14",
+		Dimensions: []string{"date", "query"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			// Sunday 2026-03-01 belongs to the week starting Monday 2026-02-23.
+			row([]string{"2026-03-01", "devleader"}, 10, 20, 1),
+			row([]string{"2026-03-02", "blazor"}, 30, 300, 5),
+			row([]string{"2026-03-08", "devleader"}, 10, 20, 1),
+			row([]string{"2026-03-09", "blazor"}, 50, 500, 4),
+		},
+	}
+	previous := &searchconsole.SearchAnalyticsResponse{
+		StartDate:  "2026-02-15",
+		EndDate:    "2026-02-28",
+		Dimensions: []string{"date", "query"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"2026-02-16", "devleader"}, 20, 40, 1),
+			row([]string{"2026-02-17", "blazor"}, 20, 200, 6),
+		},
+	}
+
+	got, err := analysis.BrandSplit(current, previous, "previous_period", m, "week")
+	if err != nil {
+		t.Fatalf("BrandSplit: %v", err)
+	}
+
+	if got.NonBranded.Clicks.Current != 80 || got.NonBranded.Clicks.Previous != 20 || *got.NonBranded.Clicks.PercentChange != 300 {
+		t.Errorf("non-branded clicks = %+v, want 80 vs 20 (+300%%)", got.NonBranded.Clicks)
+	}
+	if got.Branded.Clicks.Current != 20 || got.Branded.Clicks.Previous != 20 {
+		t.Errorf("branded clicks = %+v, want 20 vs 20", got.Branded.Clicks)
+	}
+	if got.BrandedClickShare.Current != 0.2 || got.BrandedClickShare.Previous != 0.5 {
+		t.Errorf("branded click share = %+v, want 0.2 vs 0.5", got.BrandedClickShare)
+	}
+
+	if len(got.Trend) != 3 {
+		t.Fatalf("trend = %+v, want 3 weeks", got.Trend)
+	}
+	for i, want := range []string{"2026-02-23", "2026-03-02", "2026-03-09"} {
+		if got.Trend[i].StartDate != want {
+			t.Errorf("trend[%d].StartDate = %s, want %s", i, got.Trend[i].StartDate, want)
+		}
+	}
+	week := got.Trend[1]
+	if week.Branded.Clicks != 10 || week.NonBranded.Clicks != 30 || week.BrandedClickShare != 0.25 {
+		t.Errorf("week of 2026-03-02 = %+v, want 10 branded and 30 non-branded clicks", week)
+	}
+
+	if _, err := analysis.BrandSplit(current, previous, "previous_period", m, "month"); err == nil {
+		t.Error("BrandSplit with granularity month returned nil error")
+	}
+}
@@ -0,0 +1,176 @@
+package analysis
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// CompetingPage is one page ranking for a cannibalized query. Shares are
+// fractions (0-1) of the query's totals across all of its pages.
+type CompetingPage struct {
+	Page            string  `json:"page"`
+	Clicks          float64 `json:"clicks"`
+	Impressions     float64 `json:"impressions"`
+	CTR             float64 `json:"ctr"`
+	Position        float64 `json:"position"`
+	ImpressionShare float64 `json:"impressionShare"`
+	ClickShare      float64 `json:"clickShare"`
+	Competing       bool    `json:"competing"`
+}
+
+// CannibalizedQuery is a query for which several pages each take a meaningful
+// share of impressions. Its metrics are totals across every page, and Pages
+// lists every page that ranked for it, by impressions.
+type CannibalizedQuery struct {
+	Query          string          `json:"query"`
+	Clicks         float64         `json:"clicks"`
+	Impressions    float64         `json:"impressions"`
+	CTR            float64         `json:"ctr"`
+	Position       float64         `json:"position"`
+	CompetingPages int             `json:"competingPages"`
+	Pages          []CompetingPage `json:"pages"`
+}
+
+// CannibalizationResult is the result of FindCannibalization. QueriesAnalyzed
+// counts the distinct queries that met the impression threshold;
+// CannibalizedCount counts those flagged, of which at most the requested limit
+// are listed in Queries.
+type CannibalizationResult struct {
+	SiteURL           string                      `json:"siteUrl"`
+	StartDate         string                      `json:"startDate"`
+	EndDate           string                      `json:"endDate"`
+	SearchType        string                      `json:"searchType"`
+	MinShare          float64                     `json:"minShare"`
+	MinImpressions    float64                     `json:"minImpressions"`
+	QueriesAnalyzed   int                         `json:"queriesAnalyzed"`
+	CannibalizedCount int                         `json:"cannibalizedCount"`
+	Truncated         bool                        `json:"truncated"`
+	Warnings          []searchconsole.DataWarning `json:"warnings,omitempty"`
+	Queries           []CannibalizedQuery         `json:"queries"`
+	QueriedAt         time.Time                   `json:"queriedAt"`
+}
+
+// CannibalizationOptions controls which queries FindCannibalization flags.
+// A page competes for a query when it takes at least MinShare (0-0.5] of the
+// query's impressions; queries with fewer than MinImpressions in total are
+// skipped; at most Limit flagged queries are returned.
+type CannibalizationOptions struct {
+	MinShare       float64
+	MinImpressions float64
+	Limit          int
+}
+
+// Validate reports whether o can be used, so callers can reject it before
+// fetching any rows.
+func (o CannibalizationOptions) Validate() error {
+	// Two pages cannot both take more than half the impressions.
+	if o.MinShare <= 0 || o.MinShare > 0.5 {
+		return fmt.Errorf("invalid min_share %v: must be greater than 0 and at most 0.5", o.MinShare)
+	}
+	if o.MinImpressions < 0 {
+		return fmt.Errorf("invalid min_impressions %v: must not be negative", o.MinImpressions)
+	}
+	if o.Limit <= 0 {
+		return fmt.Errorf("invalid limit %d: must be positive", o.Limit)
+	}
+	return nil
+}
+
+// FindCannibalization groups resp's rows, which must have the dimensions
+// query and page in that order, by query, and flags the queries for which two
+// or more pages compete. Flagged queries are ordered by impressions, largest
+// first.
+func FindCannibalization(
+	resp *searchconsole.SearchAnalyticsResponse,
+	options CannibalizationOptions,
+) (*CannibalizationResult, error) {
+	if !slices.Equal(resp.Dimensions, []string{"query", "page"}) {
+		return nil, errors.New("cannibalization analysis requires the dimensions query and page, in that order")
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	var order []string
+	rowsByQuery := make(map[string][]searchconsole.SearchAnalyticsRow)
+	for _, row := range resp.Rows {
+		if len(row.Keys) != 2 {
+			continue
+		}
+		query := row.Keys[0]
+		if _, ok := rowsByQuery[query]; !ok {
+			order = append(order, query)
+		}
+		rowsByQuery[query] = append(rowsByQuery[query], row)
+	}
+
+	var flagged []CannibalizedQuery
+	analyzed := 0
+	for _, query := range order {
+		rows := rowsByQuery[query]
+		total := Totals(rows)
+		if total.Impressions == 0 || total.Impressions < options.MinImpressions {
+			continue
+		}
+		analyzed++
+		if len(rows) < 2 {
+			continue
+		}
+
+		pages := make([]CompetingPage, len(rows))
+		competing := 0
+		for i, row := range rows {
+			page := CompetingPage{
+				Page:            row.Keys[1],
+				Clicks:          row.Clicks,
+				Impressions:     row.Impressions,
+				CTR:             row.CTR,
+				Position:        row.Position,
+				ImpressionShare: row.Impressions / total.Impressions,
+			}
+			if total.Clicks > 0 {
+				page.ClickShare = row.Clicks / total.Clicks
+			}
+			page.Competing = page.ImpressionShare >= options.MinShare
+			if page.Competing {
+				competing++
+			}
+			pages[i] = page
+		}
+		if competing < 2 {
+			continue
+		}
+		slices.SortStableFunc(pages, func(a, b CompetingPage) int { return cmp.Compare(b.Impressions, a.Impressions) })
+
+		flagged = append(flagged, CannibalizedQuery{
+			Query:          query,
+			Clicks:         total.Clicks,
+			Impressions:    total.Impressions,
+			CTR:            total.CTR,
+			Position:       total.Position,
+			CompetingPages: competing,
+			Pages:          pages,
+		})
+	}
+	slices.SortStableFunc(flagged, func(a, b CannibalizedQuery) int { return cmp.Compare(b.Impressions, a.Impressions) })
+
+	return &CannibalizationResult{
+		SiteURL:           resp.SiteURL,
+		StartDate:         resp.StartDate,
+		EndDate:           resp.EndDate,
+		SearchType:        resp.SearchType,
+		MinShare:          options.MinShare,
+		MinImpressions:    options.MinImpressions,
+		QueriesAnalyzed:   analyzed,
+		CannibalizedCount: len(flagged),
+		Truncated:         resp.Truncated,
+		Warnings:          mergeWarnings(resp),
+		Queries:           firstN(flagged, options.Limit),
+		QueriedAt:         resp.QueriedAt,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestFindCannibalization_FlagsQueriesWithSeveralCompetingPages(t *testing.T) {
+	t.Parallel()
+
+	resp := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"query", "page"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"blazor forms", "/blazor-forms"}, 30, 600, 4),
+			row([]string{"blazor forms", "/blazor-validation"}, 10, 300, 8),
+			row([]string{"blazor forms", "/misc"}, 0, 100, 40),
+			// One page dominates; the second takes under 10%.
+			row([]string{"dependency injection", "/di"}, 90, 950, 2),
+			row([]string{"dependency injection", "/di-old"}, 1, 50, 30),
+			// Only one page.
+			row([]string{"autofac", "/autofac"}, 20, 200, 3),
+			// Competing, but larger in total.
+			row([]string{"csharp records", "/records"}, 40, 800, 3),
+			row([]string{"csharp records", "/records-vs-classes"}, 35, 700, 3.5),
+		},
+	}
+
+	got, err := analysis.FindCannibalization(resp, analysis.CannibalizationOptions{MinShare: 0.1, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.QueriesAnalyzed != 4 || got.CannibalizedCount != 2 {
+		t.Errorf("analyzed/cannibalized = %d/%d, want 4/2", got.QueriesAnalyzed, got.CannibalizedCount)
+	}
+	if len(got.Queries) != 2 || got.Queries[0].Query != "csharp records" || got.Queries[1].Query != "blazor forms" {
+		t.Fatalf("queries = %+v, want csharp records then blazor forms", got.Queries)
+	}
+
+	blazor := got.Queries[1]
+	if blazor.Impressions != 1000 || blazor.Clicks != 40 || blazor.CompetingPages != 3 || len(blazor.Pages) != 3 {
+		t.Errorf("blazor totals = %+v, want 1000 impressions, 40 clicks, 3 pages competing", blazor)
+	}
+	// Impression-weighted: (4*600 + 8*300 + 40*100) / 1000.
+	if blazor.Position != 8.8 {
+		t.Errorf("blazor position = %v, want 8.8", blazor.Position)
+	}
+	first := blazor.Pages[0]
+	if first.Page != "/blazor-forms" || first.ImpressionShare != 0.6 || first.ClickShare != 0.75 || !first.Competing {
+		t.Errorf("first page = %+v, want /blazor-forms with 0.6 impression share and 0.75 click share", first)
+	}
+	if !blazor.Pages[2].Competing {
+		t.Errorf("/misc takes exactly 10%%, so it competes at min_share 0.1; got %+v", blazor.Pages[2])
+	}
+}
+
+func TestFindCannibalization_MinImpressionsAndLimit(t *testing.T) {
+	t.Parallel()
+
+	resp := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"query", "page"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"big", "/a"}, 5, 500, 4),
+			row([]string{"big", "/b"}, 5, 500, 5),
+			row([]string{"medium", "/a"}, 5, 100, 4),
+			row([]string{"medium", "/b"}, 5, 100, 5),
+			row([]string{"tiny", "/a"}, 0, 5, 4),
+			row([]string{"tiny", "/b"}, 0, 5, 5),
+		},
+	}
+
+	got, err := analysis.FindCannibalization(resp, analysis.CannibalizationOptions{MinShare: 0.2, MinImpressions: 50, Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.QueriesAnalyzed != 2 || got.CannibalizedCount != 2 {
+		t.Errorf("analyzed/cannibalized = %d/%d, want 2/2", got.QueriesAnalyzed, got.CannibalizedCount)
+	}
+	if len(got.Queries) != 1 || got.Queries[0].Query != "big" {
+		t.Errorf("queries = %+v, want only big", got.Queries)
+	}
+}
+
+func TestFindCannibalization_InvalidInput_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	valid := analysis.CannibalizationOptions{MinShare: 0.1, Limit: 10}
+	queryPage := &searchconsole.SearchAnalyticsResponse{Dimensions: []string{"query", "page"}}
+
+	for _, tt := range []struct {
+		name    string
+		resp    *searchconsole.SearchAnalyticsResponse
+		options analysis.CannibalizationOptions
+		marker  string
+	}{
+		{name: "wrong dimensions", resp: &searchconsole.SearchAnalyticsResponse{Dimensions: []string{"page", "query"}}, options: valid, marker: "requires the dimensions query and page"},
+		{name: "share too large", resp: queryPage, options: analysis.CannibalizationOptions{MinShare: 0.6, Limit: 10}, marker: "invalid min_share 0.6"},
+		{name: "zero share", resp: queryPage, options: analysis.CannibalizationOptions{Limit: 10}, marker: "invalid min_share 0"},
+		{name: "negative impressions", resp: queryPage, options: analysis.CannibalizationOptions{MinShare: 0.1, MinImpressions: -5, Limit: 10}, marker: "invalid min_impressions"},
+		{name: "zero limit", resp: queryPage, options: analysis.CannibalizationOptions{MinShare: 0.1}, marker: "invalid limit 0"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := analysis.FindCannibalization(tt.resp, tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.marker) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.marker)
+			}
+		})
+	}
+}
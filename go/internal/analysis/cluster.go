@@ -0,0 +1,280 @@
+package analysis
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// Cluster sort orders.
+const (
+	ClusterSortClicks      = "clicks"
+	ClusterSortImpressions = "impressions"
+)
+
+// maxClusterNgram is the longest n-gram ClusterQueries accepts.
+const maxClusterNgram = 3
+
+// topQueriesPerCluster is how many example queries each cluster lists.
+const topQueriesPerCluster = 5
+
+// stopWords are dropped from queries before clustering. They are the English
+// function words that carry no topic on their own.
+var stopWords = map[string]bool{
+	"a": true, "about": true, "an": true, "and": true, "are": true, "as": true,
+	"at": true, "be": true, "by": true, "can": true, "do": true,
+	"does": true, "for": true, "from": true, "how": true, "i": true, "if": true,
+	"in": true, "into": true, "is": true, "it": true, "its": true, "me": true,
+	"my": true, "of": true, "on": true, "or": true, "our": true, "should": true,
+	"so": true, "that": true, "the": true, "their": true, "this": true,
+	"to": true, "vs": true, "was": true, "what": true, "when": true,
+	"where": true, "which": true, "who": true, "why": true, "will": true,
+	"with": true, "you": true, "your": true,
+}
+
+// QueryCluster is a topic: every query containing one n-gram of stemmed
+// tokens. Label is the most-searched spelling of the n-gram, and the metrics
+// are totals across its queries, with an impression-weighted position.
+type QueryCluster struct {
+	Label       string   `json:"label"`
+	Stem        string   `json:"stem"`
+	N           int      `json:"n"`
+	QueryCount  int      `json:"queryCount"`
+	Clicks      float64  `json:"clicks"`
+	Impressions float64  `json:"impressions"`
+	CTR         float64  `json:"ctr"`
+	Position    float64  `json:"position"`
+	TopQueries  []string `json:"topQueries"`
+}
+
+// QueryClusterResult is the result of ClusterQueries. A query belongs to
+// every cluster whose n-gram it contains, so cluster totals overlap and do not
+// add up to Totals.
+type QueryClusterResult struct {
+	SiteURL         string                           `json:"siteUrl"`
+	StartDate       string                           `json:"startDate"`
+	EndDate         string                           `json:"endDate"`
+	SearchType      string                           `json:"searchType"`
+	MaxNgram        int                              `json:"maxNgram"`
+	MinQueries      int                              `json:"minQueries"`
+	SortBy          string                           `json:"sortBy"`
+	QueriesAnalyzed int                              `json:"queriesAnalyzed"`
+	ClusterCount    int                              `json:"clusterCount"`
+	Totals          searchconsole.SearchAnalyticsRow `json:"totals"`
+	Truncated       bool                             `json:"truncated"`
+	Warnings        []searchconsole.DataWarning      `json:"warnings,omitempty"`
+	Clusters        []QueryCluster                   `json:"clusters"`
+	QueriedAt       time.Time                        `json:"queriedAt"`
+}
+
+// QueryClusterOptions controls ClusterQueries. Clusters are built from
+// n-grams of 1 to MaxNgram tokens; clusters with fewer than MinQueries
+// queries are dropped; at most Limit clusters, ordered by SortBy, are
+// returned.
+type QueryClusterOptions struct {
+	MaxNgram   int
+	MinQueries int
+	SortBy     string
+	Limit      int
+}
+
+// Validate reports whether o can be used, so callers can reject it before
+// fetching any rows.
+func (o QueryClusterOptions) Validate() error {
+	if o.MaxNgram < 1 || o.MaxNgram > maxClusterNgram {
+		return fmt.Errorf("invalid max_ngram %d: must be between 1 and %d", o.MaxNgram, maxClusterNgram)
+	}
+	if o.MinQueries < 1 {
+		return fmt.Errorf("invalid min_queries %d: must be positive", o.MinQueries)
+	}
+	if o.SortBy != ClusterSortClicks && o.SortBy != ClusterSortImpressions {
+		return fmt.Errorf("invalid sort_by %q: must be %s or %s", o.SortBy, ClusterSortClicks, ClusterSortImpressions)
+	}
+	if o.Limit <= 0 {
+		return fmt.Errorf("invalid limit %d: must be positive", o.Limit)
+	}
+	return nil
+}
+
+// queryCluster accumulates one cluster's rows and the impressions behind each
+// spelling of its n-gram.
+type queryCluster struct {
+	n         int
+	rows      []searchconsole.SearchAnalyticsRow
+	spellings map[string]float64
+}
+
+// ClusterQueries groups resp's rows, which must have the single dimension
+// query, into clusters of queries sharing an n-gram once stop words are
+// removed and tokens are stemmed, so "blazor component" and "blazor
+// components" land in the same cluster.
+func ClusterQueries(resp *searchconsole.SearchAnalyticsResponse, options QueryClusterOptions) (*QueryClusterResult, error) {
+	if !slices.Equal(resp.Dimensions, []string{"query"}) {
+		return nil, errors.New("query clustering requires the single dimension query")
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	clusters := make(map[string]*queryCluster)
+	analyzed := 0
+	for _, row := range resp.Rows {
+		if len(row.Keys) != 1 {
+			continue
+		}
+		tokens := tokenizeQuery(row.Keys[0])
+		if len(tokens) == 0 {
+			continue
+		}
+		analyzed++
+
+		stems := make([]string, len(tokens))
+		for i, token := range tokens {
+			stems[i] = stemToken(token)
+		}
+		seen := make(map[string]bool)
+		for n := 1; n <= options.MaxNgram; n++ {
+			for i := 0; i+n <= len(stems); i++ {
+				key := strings.Join(stems[i:i+n], " ")
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				cluster, ok := clusters[key]
+				if !ok {
+					cluster = &queryCluster{n: n, spellings: make(map[string]float64)}
+					clusters[key] = cluster
+				}
+				cluster.rows = append(cluster.rows, row)
+				cluster.spellings[strings.Join(tokens[i:i+n], " ")] += row.Impressions
+			}
+		}
+	}
+
+	metric := func(row searchconsole.SearchAnalyticsRow) float64 {
+		if options.SortBy == ClusterSortClicks {
+			return row.Clicks
+		}
+		return row.Impressions
+	}
+
+	var result []QueryCluster
+	for key, cluster := range clusters {
+		if len(cluster.rows) < options.MinQueries {
+			continue
+		}
+		total := Totals(cluster.rows)
+		rows := slices.Clone(cluster.rows)
+		slices.SortStableFunc(rows, func(a, b searchconsole.SearchAnalyticsRow) int { return cmp.Compare(metric(b), metric(a)) })
+		topQueries := make([]string, 0, topQueriesPerCluster)
+		for _, row := range firstN(rows, topQueriesPerCluster) {
+			topQueries = append(topQueries, row.Keys[0])
+		}
+		result = append(result, QueryCluster{
+			Label:       clusterLabel(cluster.spellings),
+			Stem:        key,
+			N:           cluster.n,
+			QueryCount:  len(cluster.rows),
+			Clicks:      total.Clicks,
+			Impressions: total.Impressions,
+			CTR:         total.CTR,
+			Position:    total.Position,
+			TopQueries:  topQueries,
+		})
+	}
+	slices.SortFunc(result, func(a, b QueryCluster) int {
+		av, bv := a.Impressions, b.Impressions
+		if options.SortBy == ClusterSortClicks {
+			av, bv = a.Clicks, b.Clicks
+		}
+		return cmp.Or(cmp.Compare(bv, av), cmp.Compare(b.QueryCount, a.QueryCount), cmp.Compare(a.Stem, b.Stem))
+	})
+
+	return &QueryClusterResult{
+		SiteURL:         resp.SiteURL,
+		StartDate:       resp.StartDate,
+		EndDate:         resp.EndDate,
+		SearchType:      resp.SearchType,
+		MaxNgram:        options.MaxNgram,
+		MinQueries:      options.MinQueries,
+		SortBy:          options.SortBy,
+		QueriesAnalyzed: analyzed,
+		ClusterCount:    len(result),
+		Totals:          Totals(resp.Rows),
+		Truncated:       resp.Truncated,
+		Warnings:        mergeWarnings(resp),
+		Clusters:        firstN(result, options.Limit),
+		QueriedAt:       resp.QueriedAt,
+	}, nil
+}
+
+// tokenizeQuery lowercases query, splits it into words, and drops stop words.
+// '#' and '+' stay part of a word so "c#" and "c++" survive.
+func tokenizeQuery(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '#' && r != '+'
+	})
+	tokens := words[:0]
+	for _, word := range words {
+		if !stopWords[word] {
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens
+}
+
+// stemToken strips common English inflections, so plurals and verb forms of
+// a word share a stem. It is deliberately light: stems only need to be
+// consistent, not dictionary words, and short words and words containing
+// digits or symbols are left alone. Plurals are stripped first so that
+// "settings" and "setting" both reach "set".
+func stemToken(token string) string {
+	if len(token) <= 3 || strings.ContainsFunc(token, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		return token
+	}
+	switch {
+	case strings.HasSuffix(token, "ies") && len(token) > 4:
+		token = token[:len(token)-3] + "y"
+	case strings.HasSuffix(token, "sses"), strings.HasSuffix(token, "xes"),
+		strings.HasSuffix(token, "ches"), strings.HasSuffix(token, "shes"):
+		token = token[:len(token)-2]
+	case strings.HasSuffix(token, "s") && !strings.HasSuffix(token, "ss") &&
+		!strings.HasSuffix(token, "us") && !strings.HasSuffix(token, "is"):
+		token = token[:len(token)-1]
+	}
+	switch {
+	case strings.HasSuffix(token, "ing") && len(token) > 5:
+		return undouble(token[:len(token)-3])
+	case strings.HasSuffix(token, "ed") && len(token) > 4:
+		return undouble(token[:len(token)-2])
+	}
+	return token
+}
+
+// undouble drops the repeated final consonant left by stripping a suffix, as
+// in "running" -> "runn" -> "run".
+func undouble(stem string) string {
+	n := len(stem)
+	if n >= 2 && stem[n-1] == stem[n-2] && !strings.ContainsRune("aeioulsz", rune(stem[n-1])) {
+		return stem[:n-1]
+	}
+	return stem
+}
+
+// clusterLabel returns the spelling with the most impressions, breaking ties
+// alphabetically.
+func clusterLabel(spellings map[string]float64) string {
+	label, best := "", -1.0
+	for spelling, impressions := range spellings {
+		if impressions > best || (impressions == best && spelling < label) {
+			label, best = spelling, impressions
+		}
+	}
+	return label
+}
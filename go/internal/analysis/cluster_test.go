@@ -0,0 +1,107 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestClusterQueries_GroupsStemmedNgramsWithoutStopWords(t *testing.T) {
+	t.Parallel()
+
+	resp := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"query"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"blazor components"}, 30, 600, 4),
+			row([]string{"how to use blazor component"}, 10, 400, 9),
+			row([]string{"Blazor Components tutorial"}, 5, 200, 6),
+			row([]string{"c# records"}, 20, 300, 3),
+			// Nothing but stop words.
+			row([]string{"the"}, 1, 10, 50),
+		},
+	}
+
+	got, err := analysis.ClusterQueries(resp, analysis.QueryClusterOptions{
+		MaxNgram: 2, MinQueries: 2, SortBy: analysis.ClusterSortImpressions, Limit: 10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.QueriesAnalyzed != 4 || got.ClusterCount != 3 || got.Totals.Impressions != 1510 {
+		t.Errorf("analyzed/clusters/impressions = %d/%d/%v, want 4/3/1510",
+			got.QueriesAnalyzed, got.ClusterCount, got.Totals.Impressions)
+	}
+	wantStems := []string{"blazor", "blazor component", "component"}
+	wantLabels := []string{"blazor", "blazor components", "components"}
+	for i, cluster := range got.Clusters {
+		if cluster.Stem != wantStems[i] || cluster.Label != wantLabels[i] {
+			t.Errorf("cluster[%d] = %q (%q), want %q (%q)", i, cluster.Stem, cluster.Label, wantStems[i], wantLabels[i])
+		}
+		if cluster.QueryCount != 3 || cluster.Clicks != 45 || cluster.Impressions != 1200 {
+			t.Errorf("cluster %q totals = %+v, want 3 queries, 45 clicks, 1200 impressions", cluster.Stem, cluster)
+		}
+		if !approxEqual(cluster.Position, 6) || !approxEqual(cluster.CTR, 45.0/1200) {
+			t.Errorf("cluster %q position/ctr = %v/%v, want impression-weighted 6 and 0.0375", cluster.Stem, cluster.Position, cluster.CTR)
+		}
+	}
+	if top := got.Clusters[0].TopQueries; len(top) != 3 || top[0] != "blazor components" || top[2] != "Blazor Components tutorial" {
+		t.Errorf("top queries = %v, want them ordered by impressions", top)
+	}
+}
+
+func TestClusterQueries_StemsInflectionsAndSortsByClicks(t *testing.T) {
+	t.Parallel()
+
+	resp := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"query"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"vscode settings"}, 1, 500, 5),
+			row([]string{"setting up vscode"}, 2, 100, 7),
+			row([]string{"running tests"}, 40, 100, 2),
+			row([]string{"run test"}, 30, 100, 3),
+		},
+	}
+
+	got, err := analysis.ClusterQueries(resp, analysis.QueryClusterOptions{
+		MaxNgram: 1, MinQueries: 2, SortBy: analysis.ClusterSortClicks, Limit: 2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.ClusterCount != 4 || len(got.Clusters) != 2 {
+		t.Fatalf("clusters = %d (%d listed), want 4 (2 listed)", got.ClusterCount, len(got.Clusters))
+	}
+	if got.Clusters[0].Stem != "run" || got.Clusters[1].Stem != "test" || got.Clusters[0].Clicks != 70 {
+		t.Errorf("clusters = %+v, want run and test first by clicks", got.Clusters)
+	}
+}
+
+func TestClusterQueries_InvalidInput_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	valid := analysis.QueryClusterOptions{MaxNgram: 2, MinQueries: 2, SortBy: "impressions", Limit: 10}
+	for _, tt := range []struct {
+		name    string
+		dims    []string
+		options func(analysis.QueryClusterOptions) analysis.QueryClusterOptions
+		marker  string
+	}{
+		{name: "page rows", dims: []string{"page"}, marker: "requires the single dimension query"},
+		{name: "max_ngram", dims: []string{"query"}, options: func(o analysis.QueryClusterOptions) analysis.QueryClusterOptions { o.MaxNgram = 4; return o }, marker: "invalid max_ngram 4"},
+		{name: "min_queries", dims: []string{"query"}, options: func(o analysis.QueryClusterOptions) analysis.QueryClusterOptions { o.MinQueries = -1; return o }, marker: "invalid min_queries"},
+		{name: "sort_by", dims: []string{"query"}, options: func(o analysis.QueryClusterOptions) analysis.QueryClusterOptions { o.SortBy = "ctr"; return o }, marker: `invalid sort_by "ctr"`},
+	} {
+		options := valid
+		if tt.options != nil {
+			options = tt.options(valid)
+		}
+		_, err := analysis.ClusterQueries(&searchconsole.SearchAnalyticsResponse{Dimensions: tt.dims}, options)
+		if err == nil || !strings.Contains(err.Error(), tt.marker) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.marker)
+		}
+	}
+}
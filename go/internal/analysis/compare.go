@@ -0,0 +1,178 @@
+package analysis
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// Row presence values for RowComparison.Status.
+const (
+	StatusBoth = "both"
+	StatusNew  = "new"
+	StatusLost = "lost"
+)
+
+// MetricComparison is one additive metric in both periods. PercentChange is
+// nil when the previous value is zero.
+type MetricComparison struct {
+	Current       float64  `json:"current"`
+	Previous      float64  `json:"previous"`
+	Change        float64  `json:"change"`
+	PercentChange *float64 `json:"percentChange"`
+}
+
+// PositionComparison is average position in both periods. A period in which
+// the row did not appear has no position, so every field is nil unless the
+// row appeared in the periods it depends on. A negative Change is an
+// improvement, since lower positions rank higher.
+type PositionComparison struct {
+	Current       *float64 `json:"current"`
+	Previous      *float64 `json:"previous"`
+	Change        *float64 `json:"change"`
+	PercentChange *float64 `json:"percentChange"`
+}
+
+// RowComparison joins the rows sharing the same dimension keys across two
+// periods. Status is "both", "new" (current period only), or "lost"
+// (previous period only).
+type RowComparison struct {
+	Keys        []string           `json:"keys,omitempty"`
+	Status      string             `json:"status"`
+	Clicks      MetricComparison   `json:"clicks"`
+	Impressions MetricComparison   `json:"impressions"`
+	CTR         MetricComparison   `json:"ctr"`
+	Position    PositionComparison `json:"position"`
+}
+
+// Period is a concrete date range.
+type Period struct {
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+}
+
+// PeriodComparison is the result of comparing two search analytics queries
+// that differ only in their date range. Totals are each period's totals as a
+// whole, unaffected by truncation. Truncated is true when either side hit its
+// row limit, in which case rows just past the limit may be reported as new or
+// lost. Warnings holds the data warnings of both periods.
+type PeriodComparison struct {
+	SiteURL        string                      `json:"siteUrl"`
+	Comparison     string                      `json:"comparison"`
+	CurrentPeriod  Period                      `json:"currentPeriod"`
+	PreviousPeriod Period                      `json:"previousPeriod"`
+	Dimensions     []string                    `json:"dimensions,omitempty"`
+	SearchType     string                      `json:"searchType"`
+	Totals         RowComparison               `json:"totals"`
+	RowCount       int                         `json:"rowCount"`
+	Truncated      bool                        `json:"truncated"`
+	Warnings       []searchconsole.DataWarning `json:"warnings,omitempty"`
+	Rows           []RowComparison             `json:"rows"`
+	QueriedAt      time.Time                   `json:"queriedAt"`
+}
+
+// ComparePeriods joins current and previous by dimension keys, including rows
+// present in only one of them, and orders the result by the size of the click
+// change, largest first. currentTotals and previousTotals are the periods'
+// totals, which a sum of possibly truncated rows would understate.
+func ComparePeriods(
+	current, previous *searchconsole.SearchAnalyticsResponse,
+	currentTotals, previousTotals searchconsole.SearchAnalyticsRow,
+	comparison string,
+) *PeriodComparison {
+	rows := CompareRows(current.Rows, previous.Rows)
+	return &PeriodComparison{
+		SiteURL:        current.SiteURL,
+		Comparison:     comparison,
+		CurrentPeriod:  Period{StartDate: current.StartDate, EndDate: current.EndDate},
+		PreviousPeriod: Period{StartDate: previous.StartDate, EndDate: previous.EndDate},
+		Dimensions:     current.Dimensions,
+		SearchType:     current.SearchType,
+		Totals:         compareRow(nil, currentTotals, previousTotals, true, true),
+		RowCount:       len(rows),
+		Truncated:      current.Truncated || previous.Truncated,
+		Warnings:       mergeWarnings(current, previous),
+		Rows:           rows,
+		QueriedAt:      current.QueriedAt,
+	}
+}
+
+// CompareRows joins current and previous rows by dimension keys. The result
+// holds one entry per distinct key, ordered by absolute click change
+// (largest first), then by current impressions.
+func CompareRows(current, previous []searchconsole.SearchAnalyticsRow) []RowComparison {
+	previousByKey := make(map[string]searchconsole.SearchAnalyticsRow, len(previous))
+	for _, row := range previous {
+		previousByKey[joinKeys(row.Keys)] = row
+	}
+
+	out := make([]RowComparison, 0, max(len(current), len(previous)))
+	seen := make(map[string]bool, len(current))
+	for _, row := range current {
+		key := joinKeys(row.Keys)
+		seen[key] = true
+		prev, inPrevious := previousByKey[key]
+		out = append(out, compareRow(row.Keys, row, prev, true, inPrevious))
+	}
+	for _, row := range previous {
+		if !seen[joinKeys(row.Keys)] {
+			out = append(out, compareRow(row.Keys, searchconsole.SearchAnalyticsRow{}, row, false, true))
+		}
+	}
+
+	slices.SortStableFunc(out, func(a, b RowComparison) int {
+		if c := cmp.Compare(math.Abs(b.Clicks.Change), math.Abs(a.Clicks.Change)); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Impressions.Current, a.Impressions.Current)
+	})
+	return out
+}
+
+func compareRow(
+	keys []string,
+	current, previous searchconsole.SearchAnalyticsRow,
+	inCurrent, inPrevious bool,
+) RowComparison {
+	status := StatusBoth
+	switch {
+	case inCurrent && !inPrevious:
+		status = StatusNew
+	case !inCurrent && inPrevious:
+		status = StatusLost
+	}
+
+	position := PositionComparison{}
+	if inCurrent {
+		position.Current = &current.Position
+	}
+	if inPrevious {
+		position.Previous = &previous.Position
+	}
+	if inCurrent && inPrevious {
+		change := current.Position - previous.Position
+		position.Change = &change
+		position.PercentChange = percentChange(previous.Position, current.Position)
+	}
+
+	return RowComparison{
+		Keys:        keys,
+		Status:      status,
+		Clicks:      compareMetric(current.Clicks, previous.Clicks),
+		Impressions: compareMetric(current.Impressions, previous.Impressions),
+		CTR:         compareMetric(current.CTR, previous.CTR),
+		Position:    position,
+	}
+}
+
+func compareMetric(current, previous float64) MetricComparison {
+	return MetricComparison{
+		Current:       current,
+		Previous:      previous,
+		Change:        current - previous,
+		PercentChange: percentChange(previous, current),
+	}
+}
@@ -0,0 +1,135 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func row(keys []string, clicks, impressions, position float64) searchconsole.SearchAnalyticsRow {
+	r := searchconsole.SearchAnalyticsRow{Keys: keys, Clicks: clicks, Impressions: impressions, Position: position}
+	if impressions > 0 {
+		r.CTR = clicks / impressions
+	}
+	return r
+}
+
+func TestCompareRows_JoinsByKeysIncludingOneSidedRows(t *testing.T) {
+	t.Parallel()
+
+	current := []searchconsole.SearchAnalyticsRow{
+		row([]string{"blazor", "/a"}, 30, 300, 3),
+		row([]string{"fresh", "/b"}, 5, 50, 8),
+	}
+	previous := []searchconsole.SearchAnalyticsRow{
+		row([]string{"blazor", "/a"}, 10, 200, 5),
+		row([]string{"gone", "/c"}, 12, 100, 4),
+	}
+
+	got := analysis.CompareRows(current, previous)
+	if len(got) != 3 {
+		t.Fatalf("row count = %d, want 3", len(got))
+	}
+
+	byKey := map[string]analysis.RowComparison{}
+	for _, r := range got {
+		byKey[r.Keys[0]] = r
+	}
+
+	both := byKey["blazor"]
+	if both.Status != analysis.StatusBoth || both.Clicks.Change != 20 || *both.Clicks.PercentChange != 200 {
+		t.Errorf("blazor = %+v, want status both, +20 clicks, +200%%", both)
+	}
+	if both.Position.Change == nil || *both.Position.Change != -2 {
+		t.Errorf("blazor position change = %v, want -2", both.Position.Change)
+	}
+
+	fresh := byKey["fresh"]
+	if fresh.Status != analysis.StatusNew || fresh.Clicks.PercentChange != nil {
+		t.Errorf("fresh = %+v, want status new with nil percent change", fresh)
+	}
+	if fresh.Position.Previous != nil || fresh.Position.Change != nil || *fresh.Position.Current != 8 {
+		t.Errorf("fresh position = %+v, want only current set", fresh.Position)
+	}
+
+	gone := byKey["gone"]
+	if gone.Status != analysis.StatusLost || gone.Clicks.Change != -12 || *gone.Clicks.PercentChange != -100 {
+		t.Errorf("gone = %+v, want status lost, -12 clicks, -100%%", gone)
+	}
+	if gone.Position.Current != nil || *gone.Position.Previous != 4 {
+		t.Errorf("gone position = %+v, want only previous set", gone.Position)
+	}
+
+	// Ordered by absolute click change: blazor (+20), gone (-12), fresh (+5).
+	if got[0].Keys[0] != "blazor" || got[1].Keys[0] != "gone" || got[2].Keys[0] != "fresh" {
+		t.Errorf("order = %v, %v, %v; want blazor, gone, fresh", got[0].Keys, got[1].Keys, got[2].Keys)
+	}
+}
+
+func TestComparePeriods_TotalsUseImpressionWeightedPosition(t *testing.T) {
+	t.Parallel()
+
+	current := &searchconsole.SearchAnalyticsResponse{
+		SiteURL: "sc-domain:devleader.ca", StartDate: "2026-02-01", EndDate: "2026-02-28",
+		Rows: []searchconsole.SearchAnalyticsRow{row([]string{"a"}, 10, 100, 2), row([]string{"b"}, 0, 300, 10)},
+	}
+	previous := &searchconsole.SearchAnalyticsResponse{
+		StartDate: "2026-01-04", EndDate: "2026-01-31", Truncated: true,
+		Rows: []searchconsole.SearchAnalyticsRow{row([]string{"a"}, 5, 100, 4)},
+	}
+
+	got := analysis.ComparePeriods(
+		current, previous, analysis.Totals(current.Rows), analysis.Totals(previous.Rows), "previous_period")
+
+	if got.Totals.Clicks.Current != 10 || got.Totals.Impressions.Current != 400 {
+		t.Errorf("current totals = %+v / %+v, want 10 clicks / 400 impressions", got.Totals.Clicks, got.Totals.Impressions)
+	}
+	if *got.Totals.Position.Current != 8 {
+		t.Errorf("current total position = %v, want 8 (impression-weighted)", *got.Totals.Position.Current)
+	}
+	if got.Totals.CTR.Current != 0.025 {
+		t.Errorf("current total CTR = %v, want 0.025", got.Totals.CTR.Current)
+	}
+	if !got.Truncated {
+		t.Error("Truncated = false, want true when either side was truncated")
+	}
+	if got.PreviousPeriod.StartDate != "2026-01-04" || got.Comparison != "previous_period" {
+		t.Errorf("periods = %+v / %q, want previous period echoed", got.PreviousPeriod, got.Comparison)
+	}
+}
+
+func TestComparePeriods_UsesGivenTotals(t *testing.T) {
+	t.Parallel()
+
+	current := &searchconsole.SearchAnalyticsResponse{
+		Rows: []searchconsole.SearchAnalyticsRow{row([]string{"a"}, 10, 100, 2)},
+	}
+	previous := &searchconsole.SearchAnalyticsResponse{
+		Rows: []searchconsole.SearchAnalyticsRow{row([]string{"a"}, 5, 100, 4)},
+	}
+
+	got := analysis.ComparePeriods(current, previous, row(nil, 40, 800, 6), row(nil, 20, 400, 5), "previous_period")
+
+	if got.Totals.Clicks.Current != 40 || got.Totals.Clicks.Previous != 20 {
+		t.Errorf("totals clicks = %+v, want 40 vs 20 from the given totals", got.Totals.Clicks)
+	}
+	if got.Rows[0].Clicks.Change != 5 {
+		t.Errorf("row click change = %v, want 5", got.Rows[0].Clicks.Change)
+	}
+}
+
+func TestComparePeriods_MergesWarningsOfBothPeriods(t *testing.T) {
+	t.Parallel()
+
+	fresh := searchconsole.DataWarning{Code: searchconsole.WarningFreshData, Message: "fresh"}
+	retention := searchconsole.DataWarning{Code: searchconsole.WarningOutsideRetention, Message: "old"}
+	current := &searchconsole.SearchAnalyticsResponse{Warnings: []searchconsole.DataWarning{fresh}}
+	previous := &searchconsole.SearchAnalyticsResponse{Warnings: []searchconsole.DataWarning{retention, fresh}}
+
+	got := analysis.ComparePeriods(current, previous, searchconsole.SearchAnalyticsRow{}, searchconsole.SearchAnalyticsRow{}, "year_over_year")
+
+	if len(got.Warnings) != 2 || got.Warnings[0] != fresh || got.Warnings[1] != retention {
+		t.Errorf("warnings = %+v, want fresh then retention, each once", got.Warnings)
+	}
+}
@@ -0,0 +1,148 @@
+package analysis
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// ctrAnomalyZScore is how many standard deviations below its expected clicks
+// a row must fall, treating each impression as an independent click chance,
+// for the shortfall to be flagged rather than put down to chance.
+const ctrAnomalyZScore = -2
+
+// CTRAnomalyOptions selects CTR anomalies. A row is flagged when it has at
+// least MinImpressions, its CTR is at most MaxCTRRatio (0-1) of the curve's
+// expected CTR at its position, and the shortfall is statistically
+// significant; at most Limit anomalies are returned.
+type CTRAnomalyOptions struct {
+	MinImpressions float64
+	MaxCTRRatio    float64
+	Limit          int
+}
+
+// Validate reports whether o can be used, so callers can reject it before
+// fetching any rows.
+func (o CTRAnomalyOptions) Validate() error {
+	if o.MinImpressions < 0 {
+		return fmt.Errorf("invalid min_impressions %v: must not be negative", o.MinImpressions)
+	}
+	if o.MaxCTRRatio <= 0 || o.MaxCTRRatio >= 1 {
+		return fmt.Errorf("invalid max_ctr_ratio %v: must be greater than 0 and less than 1", o.MaxCTRRatio)
+	}
+	if o.Limit <= 0 {
+		return fmt.Errorf("invalid limit %d: must be positive", o.Limit)
+	}
+	return nil
+}
+
+// CTRAnomaly is a row whose CTR falls well below what the property usually
+// gets at its position. CTRRatio is CTR over ExpectedCTR, MissingClicks is
+// ExpectedClicks minus Clicks, and ZScore is how many standard deviations
+// Clicks falls below ExpectedClicks.
+type CTRAnomaly struct {
+	Key            string  `json:"key"`
+	Clicks         float64 `json:"clicks"`
+	Impressions    float64 `json:"impressions"`
+	CTR            float64 `json:"ctr"`
+	Position       float64 `json:"position"`
+	ExpectedCTR    float64 `json:"expectedCtr"`
+	CTRRatio       float64 `json:"ctrRatio"`
+	ExpectedClicks float64 `json:"expectedClicks"`
+	MissingClicks  float64 `json:"missingClicks"`
+	ZScore         float64 `json:"zScore"`
+}
+
+// CTRAnomalyResult is the result of FindCTRAnomalies. RowsAnalyzed counts the
+// rows that met the impression threshold and had a non-zero expected CTR;
+// AnomalyCount counts those flagged, of which at most the requested limit are
+// listed in Anomalies.
+type CTRAnomalyResult struct {
+	SiteURL        string                      `json:"siteUrl"`
+	StartDate      string                      `json:"startDate"`
+	EndDate        string                      `json:"endDate"`
+	Dimension      string                      `json:"dimension"`
+	SearchType     string                      `json:"searchType"`
+	MinImpressions float64                     `json:"minImpressions"`
+	MaxCTRRatio    float64                     `json:"maxCtrRatio"`
+	CTRCurve       CTRCurve                    `json:"ctrCurve"`
+	RowsAnalyzed   int                         `json:"rowsAnalyzed"`
+	AnomalyCount   int                         `json:"anomalyCount"`
+	Truncated      bool                        `json:"truncated"`
+	Warnings       []searchconsole.DataWarning `json:"warnings,omitempty"`
+	Anomalies      []CTRAnomaly                `json:"anomalies"`
+	QueriedAt      time.Time                   `json:"queriedAt"`
+}
+
+// FindCTRAnomalies compares each row of resp, which must have exactly one
+// dimension, against curve and flags the rows whose CTR falls well below it.
+// Anomalies are ordered by missing clicks, largest first.
+func FindCTRAnomalies(
+	resp *searchconsole.SearchAnalyticsResponse,
+	curve CTRCurve,
+	options CTRAnomalyOptions,
+) (*CTRAnomalyResult, error) {
+	if len(resp.Dimensions) != 1 {
+		return nil, fmt.Errorf("CTR anomaly analysis requires exactly one dimension, got %d", len(resp.Dimensions))
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	var anomalies []CTRAnomaly
+	analyzed := 0
+	for _, row := range resp.Rows {
+		if len(row.Keys) != 1 || row.Impressions == 0 || row.Impressions < options.MinImpressions {
+			continue
+		}
+		expectedCTR := curve.ExpectedCTR(row.Position)
+		if expectedCTR <= 0 {
+			continue
+		}
+		analyzed++
+
+		expectedClicks := row.Impressions * expectedCTR
+		ratio := row.CTR / expectedCTR
+		z := 0.0
+		if expectedCTR < 1 {
+			z = (row.Clicks - expectedClicks) / math.Sqrt(row.Impressions*expectedCTR*(1-expectedCTR))
+		}
+		if ratio > options.MaxCTRRatio || z > ctrAnomalyZScore {
+			continue
+		}
+		anomalies = append(anomalies, CTRAnomaly{
+			Key:            row.Keys[0],
+			Clicks:         row.Clicks,
+			Impressions:    row.Impressions,
+			CTR:            row.CTR,
+			Position:       row.Position,
+			ExpectedCTR:    expectedCTR,
+			CTRRatio:       ratio,
+			ExpectedClicks: expectedClicks,
+			MissingClicks:  expectedClicks - row.Clicks,
+			ZScore:         z,
+		})
+	}
+	slices.SortStableFunc(anomalies, func(a, b CTRAnomaly) int { return cmp.Compare(b.MissingClicks, a.MissingClicks) })
+
+	return &CTRAnomalyResult{
+		SiteURL:        resp.SiteURL,
+		StartDate:      resp.StartDate,
+		EndDate:        resp.EndDate,
+		Dimension:      resp.Dimensions[0],
+		SearchType:     resp.SearchType,
+		MinImpressions: options.MinImpressions,
+		MaxCTRRatio:    options.MaxCTRRatio,
+		CTRCurve:       curve,
+		RowsAnalyzed:   analyzed,
+		AnomalyCount:   len(anomalies),
+		Truncated:      resp.Truncated,
+		Warnings:       mergeWarnings(resp),
+		Anomalies:      firstN(anomalies, options.Limit),
+		QueriedAt:      resp.QueriedAt,
+	}, nil
+}
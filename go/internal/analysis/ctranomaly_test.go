@@ -0,0 +1,75 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestFindCTRAnomalies_FlagsSignificantShortfallsOnly(t *testing.T) {
+	t.Parallel()
+
+	// A flat curve expecting 20% CTR at every position.
+	curve := analysis.CTRCurve{Points: []analysis.CTRCurvePoint{{Position: 1, CTR: 0.2}}}
+	resp := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"query"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"slightly low"}, 150, 1000, 4),
+			row([]string{"also weak"}, 10, 500, 2),
+			row([]string{"weak"}, 20, 1000, 3),
+			// Zero clicks, but too few impressions for that to be significant.
+			row([]string{"small sample"}, 0, 10, 5),
+			row([]string{"tiny"}, 0, 2, 5),
+		},
+	}
+
+	got, err := analysis.FindCTRAnomalies(resp, curve, analysis.CTRAnomalyOptions{MinImpressions: 5, MaxCTRRatio: 0.5, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.RowsAnalyzed != 4 || got.AnomalyCount != 2 {
+		t.Errorf("analyzed/anomalies = %d/%d, want 4/2", got.RowsAnalyzed, got.AnomalyCount)
+	}
+	if len(got.Anomalies) != 2 || got.Anomalies[0].Key != "weak" || got.Anomalies[1].Key != "also weak" {
+		t.Fatalf("anomalies = %+v, want weak then also weak", got.Anomalies)
+	}
+	weak := got.Anomalies[0]
+	if !approxEqual(weak.ExpectedCTR, 0.2) || !approxEqual(weak.CTRRatio, 0.1) ||
+		!approxEqual(weak.ExpectedClicks, 200) || !approxEqual(weak.MissingClicks, 180) {
+		t.Errorf("weak = %+v, want expected CTR 0.2, ratio 0.1, 200 expected and 180 missing clicks", weak)
+	}
+	if weak.ZScore > -14 || weak.ZScore < -15 {
+		t.Errorf("weak zScore = %v, want about -14.2", weak.ZScore)
+	}
+	if got.Dimension != "query" || len(got.CTRCurve.Points) != 1 {
+		t.Errorf("dimension/curve = %q/%+v, want query and the curve passed in", got.Dimension, got.CTRCurve)
+	}
+}
+
+func TestFindCTRAnomalies_InvalidInput_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	valid := analysis.CTRAnomalyOptions{MaxCTRRatio: 0.5, Limit: 10}
+	for _, tt := range []struct {
+		name       string
+		dimensions []string
+		options    analysis.CTRAnomalyOptions
+		marker     string
+	}{
+		{name: "two dimensions", dimensions: []string{"query", "page"}, options: valid, marker: "requires exactly one dimension"},
+		{name: "ratio of one", dimensions: []string{"page"}, options: analysis.CTRAnomalyOptions{MaxCTRRatio: 1, Limit: 10}, marker: "invalid max_ctr_ratio 1"},
+		{name: "negative impressions", dimensions: []string{"page"}, options: analysis.CTRAnomalyOptions{MinImpressions: -1, MaxCTRRatio: 0.5, Limit: 10}, marker: "invalid min_impressions"},
+		{name: "zero limit", dimensions: []string{"page"}, options: analysis.CTRAnomalyOptions{MaxCTRRatio: 0.5}, marker: "invalid limit 0"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &searchconsole.SearchAnalyticsResponse{Dimensions: tt.dimensions}
+			_, err := analysis.FindCTRAnomalies(resp, analysis.CTRCurve{}, tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.marker) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.marker)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package analysis
+
+import (
+	"math"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// maxCurvePosition is the last CTR curve bucket. Rows ranking lower are
+// counted in it, since beyond it so few clicks happen that finer buckets are
+// noise.
+const maxCurvePosition = 30
+
+// CTRCurvePoint is the CTR a property gets at one rounded position.
+// ObservedCTR is clicks over impressions for the rows in the bucket; CTR is
+// the fitted value after smoothing buckets so CTR never rises as position
+// worsens.
+type CTRCurvePoint struct {
+	Position    int     `json:"position"`
+	Clicks      float64 `json:"clicks"`
+	Impressions float64 `json:"impressions"`
+	ObservedCTR float64 `json:"observedCtr"`
+	CTR         float64 `json:"ctr"`
+}
+
+// CTRCurve is an expected-CTR-by-position curve fitted from a property's own
+// rows. Points holds only positions that had impressions, in position order.
+type CTRCurve struct {
+	Points []CTRCurvePoint `json:"points"`
+}
+
+// FitCTRCurve buckets rows by rounded position and fits a non-increasing
+// curve through the buckets' CTRs with impression-weighted isotonic
+// regression (pool adjacent violators), so a handful of lucky clicks at a low
+// position cannot make it look better than a higher one.
+func FitCTRCurve(rows []searchconsole.SearchAnalyticsRow) CTRCurve {
+	var buckets [maxCurvePosition + 1]CTRCurvePoint
+	for _, row := range rows {
+		if row.Impressions <= 0 {
+			continue
+		}
+		p := min(max(int(math.Round(row.Position)), 1), maxCurvePosition)
+		buckets[p].Clicks += row.Clicks
+		buckets[p].Impressions += row.Impressions
+	}
+
+	var points []CTRCurvePoint
+	for p := 1; p <= maxCurvePosition; p++ {
+		b := buckets[p]
+		if b.Impressions == 0 {
+			continue
+		}
+		b.Position = p
+		b.ObservedCTR = b.Clicks / b.Impressions
+		points = append(points, b)
+	}
+
+	// Each block pools consecutive points until the curve is non-increasing;
+	// a block's CTR is its pooled clicks over pooled impressions.
+	type block struct {
+		clicks, impressions float64
+		size                int
+	}
+	ctr := func(b block) float64 { return b.clicks / b.impressions }
+	var blocks []block
+	for _, p := range points {
+		blocks = append(blocks, block{clicks: p.Clicks, impressions: p.Impressions, size: 1})
+		for len(blocks) > 1 && ctr(blocks[len(blocks)-2]) < ctr(blocks[len(blocks)-1]) {
+			last := blocks[len(blocks)-1]
+			blocks = blocks[:len(blocks)-1]
+			prev := &blocks[len(blocks)-1]
+			prev.clicks += last.clicks
+			prev.impressions += last.impressions
+			prev.size += last.size
+		}
+	}
+	i := 0
+	for _, b := range blocks {
+		for range b.size {
+			points[i].CTR = ctr(b)
+			i++
+		}
+	}
+
+	return CTRCurve{Points: points}
+}
+
+// ExpectedCTR returns the curve's CTR at position, interpolating linearly
+// between fitted points and holding the end values flat beyond them. It
+// returns 0 for an empty curve.
+func (c CTRCurve) ExpectedCTR(position float64) float64 {
+	if len(c.Points) == 0 {
+		return 0
+	}
+	first, last := c.Points[0], c.Points[len(c.Points)-1]
+	if position <= float64(first.Position) {
+		return first.CTR
+	}
+	if position >= float64(last.Position) {
+		return last.CTR
+	}
+	for i := 1; i < len(c.Points); i++ {
+		hi := c.Points[i]
+		if position > float64(hi.Position) {
+			continue
+		}
+		lo := c.Points[i-1]
+		t := (position - float64(lo.Position)) / float64(hi.Position-lo.Position)
+		return lo.CTR + t*(hi.CTR-lo.CTR)
+	}
+	return last.CTR
+}
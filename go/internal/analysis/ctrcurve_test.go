@@ -0,0 +1,79 @@
+package analysis_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestFitCTRCurve_PoolsBucketsThatViolateMonotonicity(t *testing.T) {
+	t.Parallel()
+
+	curve := analysis.FitCTRCurve([]searchconsole.SearchAnalyticsRow{
+		row([]string{"a"}, 30, 100, 1.2),
+		row([]string{"b"}, 10, 100, 2),
+		// Position 3 does better than 2: the two are pooled to 0.15.
+		row([]string{"c"}, 20, 100, 3.4),
+		row([]string{"d"}, 1, 100, 5),
+		// Ranks past the last bucket and is folded into it.
+		row([]string{"e"}, 0, 50, 62),
+		row([]string{"f"}, 0, 0, 7),
+	})
+
+	want := []struct {
+		position    int
+		observed    float64
+		fitted      float64
+		impressions float64
+	}{
+		{1, 0.3, 0.3, 100},
+		{2, 0.1, 0.15, 100},
+		{3, 0.2, 0.15, 100},
+		{5, 0.01, 0.01, 100},
+		{30, 0, 0, 50},
+	}
+	if len(curve.Points) != len(want) {
+		t.Fatalf("points = %+v, want %d points", curve.Points, len(want))
+	}
+	for i, w := range want {
+		p := curve.Points[i]
+		if p.Position != w.position || !approxEqual(p.ObservedCTR, w.observed) || !approxEqual(p.CTR, w.fitted) || p.Impressions != w.impressions {
+			t.Errorf("point %d = %+v, want position %d observed %v fitted %v impressions %v",
+				i, p, w.position, w.observed, w.fitted, w.impressions)
+		}
+	}
+}
+
+func TestCTRCurve_ExpectedCTR_InterpolatesAndClamps(t *testing.T) {
+	t.Parallel()
+
+	curve := analysis.CTRCurve{Points: []analysis.CTRCurvePoint{
+		{Position: 2, CTR: 0.2},
+		{Position: 4, CTR: 0.1},
+	}}
+
+	for _, tt := range []struct {
+		position float64
+		want     float64
+	}{
+		{1, 0.2},
+		{2, 0.2},
+		{3, 0.15},
+		{3.5, 0.125},
+		{4, 0.1},
+		{15, 0.1},
+	} {
+		if got := curve.ExpectedCTR(tt.position); !approxEqual(got, tt.want) {
+			t.Errorf("ExpectedCTR(%v) = %v, want %v", tt.position, got, tt.want)
+		}
+	}
+	if got := (analysis.CTRCurve{}).ExpectedCTR(3); got != 0 {
+		t.Errorf("empty curve ExpectedCTR = %v, want 0", got)
+	}
+}
@@ -0,0 +1,104 @@
+package analysis
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// maxFilledDays bounds the date range FillDateGaps expands, so a mistyped
+// range cannot produce millions of rows.
+const maxFilledDays = 1000
+
+// FillDateGaps inserts a zero row, marked Filled, for every date from resp's
+// start date through the last date any row has that has no row, once for
+// each combination of the other dimensions seen in the rows. Dates after the
+// last one with data are never filled, since Search Console may simply not
+// have reported them yet. resp must include the date dimension and must not
+// be truncated, since a row cut off by the row limit would otherwise be
+// reported as a zero. Rows are reordered by date, keeping each date's rows in
+// their original order with filled rows after them. RowCount is updated;
+// segments and totals are unchanged, since filled rows add nothing to them.
+func FillDateGaps(resp *searchconsole.SearchAnalyticsResponse) error {
+	dateIndex := slices.Index(resp.Dimensions, "date")
+	if dateIndex < 0 {
+		return errors.New("filling date gaps requires the date dimension")
+	}
+	if resp.Truncated {
+		return errors.New("cannot fill date gaps in a truncated response: rows beyond the row limit would look like zeros")
+	}
+	start, err := time.Parse(time.DateOnly, resp.StartDate)
+	if err != nil {
+		return fmt.Errorf("invalid start date %q: %w", resp.StartDate, err)
+	}
+	end, err := time.Parse(time.DateOnly, resp.EndDate)
+	if err != nil {
+		return fmt.Errorf("invalid end date %q: %w", resp.EndDate, err)
+	}
+	if days := int(end.Sub(start).Hours()/24) + 1; days > maxFilledDays {
+		return fmt.Errorf("cannot fill %d days: at most %d are supported", days, maxFilledDays)
+	}
+
+	// Every combination of the other dimensions, in order of first
+	// appearance, with the dates it already has, and the last date with data.
+	type combination struct {
+		template searchconsole.SearchAnalyticsRow
+		dates    map[string]bool
+	}
+	var order []string
+	combinations := make(map[string]*combination)
+	last := ""
+	for _, row := range resp.Rows {
+		if len(row.Keys) != len(resp.Dimensions) {
+			continue
+		}
+		last = max(last, row.Keys[dateIndex])
+		others := slices.Delete(slices.Clone(row.Keys), dateIndex, dateIndex+1)
+		key := joinKeys(others)
+		c, ok := combinations[key]
+		if !ok {
+			c = &combination{template: row, dates: make(map[string]bool)}
+			combinations[key] = c
+			order = append(order, key)
+		}
+		c.dates[row.Keys[dateIndex]] = true
+	}
+	if len(order) == 0 {
+		return nil
+	}
+	if lastData, err := time.Parse(time.DateOnly, last); err == nil && lastData.Before(end) {
+		end = lastData
+	}
+
+	rows := resp.Rows
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		for _, key := range order {
+			c := combinations[key]
+			if c.dates[date] {
+				continue
+			}
+			keys := slices.Clone(c.template.Keys)
+			keys[dateIndex] = date
+			rows = append(rows, searchconsole.SearchAnalyticsRow{Keys: keys, Segment: c.template.Segment, Intent: c.template.Intent, Filled: true})
+		}
+	}
+	slices.SortStableFunc(rows, func(a, b searchconsole.SearchAnalyticsRow) int {
+		return cmp.Compare(dateKey(a, dateIndex), dateKey(b, dateIndex))
+	})
+	resp.Rows = rows
+	resp.RowCount = len(rows)
+	return nil
+}
+
+// dateKey returns row's date, or "" for a row without a full set of keys.
+func dateKey(row searchconsole.SearchAnalyticsRow, dateIndex int) string {
+	if dateIndex >= len(row.Keys) {
+		return ""
+	}
+	return row.Keys[dateIndex]
+}
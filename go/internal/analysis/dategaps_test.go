@@ -0,0 +1,114 @@
+package analysis_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestFillDateGaps_InsertsZeroRowsPerCombination(t *testing.T) {
+	t.Parallel()
+
+	resp := &searchconsole.SearchAnalyticsResponse{
+		StartDate:  "2026-03-01",
+		EndDate:    "2026-03-03",
+		Dimensions: []string{"query", "date"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"blazor", "2026-03-02"}, 4, 40, 3),
+			row([]string{"blazor", "2026-03-01"}, 5, 50, 3),
+			row([]string{"aspire", "2026-03-03"}, 1, 10, 9),
+		},
+	}
+	resp.RowCount = len(resp.Rows)
+
+	if err := analysis.FillDateGaps(resp); err != nil {
+		t.Fatalf("FillDateGaps: %v", err)
+	}
+	var got []string
+	for _, r := range resp.Rows {
+		entry := strings.Join(r.Keys, "/")
+		if r.Filled {
+			entry += " filled"
+			if r.Clicks != 0 || r.Impressions != 0 || r.CTR != 0 || r.Position != 0 {
+				t.Errorf("filled row %v has metrics %+v", r.Keys, r)
+			}
+		}
+		got = append(got, entry)
+	}
+	want := []string{
+		"blazor/2026-03-01",
+		"aspire/2026-03-01 filled",
+		"blazor/2026-03-02",
+		"aspire/2026-03-02 filled",
+		"aspire/2026-03-03",
+		"blazor/2026-03-03 filled",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+	if resp.RowCount != 6 {
+		t.Errorf("RowCount = %d, want 6", resp.RowCount)
+	}
+}
+
+func TestFillDateGaps_StopsAtLastDateWithData(t *testing.T) {
+	t.Parallel()
+
+	resp := &searchconsole.SearchAnalyticsResponse{
+		StartDate:  "2026-02-27",
+		EndDate:    "2026-03-05",
+		Dimensions: []string{"date"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"2026-02-28"}, 2, 20, 4),
+			row([]string{"2026-03-02"}, 1, 10, 4),
+		},
+	}
+	if err := analysis.FillDateGaps(resp); err != nil {
+		t.Fatalf("FillDateGaps: %v", err)
+	}
+	var dates []string
+	for _, r := range resp.Rows {
+		dates = append(dates, r.Keys[0])
+	}
+	// Leading gaps are filled; nothing after 2026-03-02, the last date with
+	// data, is.
+	if want := []string{"2026-02-27", "2026-02-28", "2026-03-01", "2026-03-02"}; !slices.Equal(dates, want) {
+		t.Errorf("dates = %q, want %q", dates, want)
+	}
+
+	empty := &searchconsole.SearchAnalyticsResponse{StartDate: "2026-02-27", EndDate: "2026-03-01", Dimensions: []string{"date"}}
+	if err := analysis.FillDateGaps(empty); err != nil || len(empty.Rows) != 0 {
+		t.Errorf("FillDateGaps with no rows = %v, %d rows, want nothing filled", err, len(empty.Rows))
+	}
+}
+
+func TestFillDateGaps_TruncatedResponse_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	resp := &searchconsole.SearchAnalyticsResponse{
+		StartDate:  "2026-03-01",
+		EndDate:    "2026-03-03",
+		Dimensions: []string{"date", "query"},
+		Truncated:  true,
+		Rows:       []searchconsole.SearchAnalyticsRow{row([]string{"2026-03-01", "blazor"}, 1, 10, 4)},
+	}
+	err := analysis.FillDateGaps(resp)
+	if err == nil || !strings.Contains(err.Error(), "truncated") {
+		t.Errorf("error = %v, want a truncated response error", err)
+	}
+	if len(resp.Rows) != 1 {
+		t.Errorf("rows = %+v, want them untouched", resp.Rows)
+	}
+}
+
+func TestFillDateGaps_WithoutDateDimension_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	err := analysis.FillDateGaps(&searchconsole.SearchAnalyticsResponse{Dimensions: []string{"query"}})
+	if err == nil || !strings.Contains(err.Error(), "requires the date dimension") {
+		t.Errorf("error = %v, want a date dimension error", err)
+	}
+}
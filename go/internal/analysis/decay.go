@@ -0,0 +1,262 @@
+package analysis
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// MaxDecayMonths is the longest lookback FindDecayingContent accepts. Search
+// Console keeps 16 months of data, but the 16th complete month back starts
+// before the oldest retained day, so its partial clicks would look like a
+// decline.
+const MaxDecayMonths = 15
+
+// MonthlyResponse is one calendar month of search analytics, keyed by its
+// "YYYY-MM" month.
+type MonthlyResponse struct {
+	Month    string
+	Response *searchconsole.SearchAnalyticsResponse
+}
+
+// QueryDrop is how one query's clicks on a page changed between the page's
+// peak month and the current month.
+type QueryDrop struct {
+	Query         string  `json:"query"`
+	PeakClicks    float64 `json:"peakClicks"`
+	CurrentClicks float64 `json:"currentClicks"`
+	ClicksChange  float64 `json:"clicksChange"`
+}
+
+// DecayingPage is a page whose clicks stayed well below their peak for the
+// last months of the lookback. MonthlyClicks lines up with the result's
+// Months; DeclinePercent compares the current month with the peak month.
+type DecayingPage struct {
+	Page               string      `json:"page"`
+	PeakMonth          string      `json:"peakMonth"`
+	PeakClicks         float64     `json:"peakClicks"`
+	CurrentMonth       string      `json:"currentMonth"`
+	CurrentClicks      float64     `json:"currentClicks"`
+	DeclinePercent     float64     `json:"declinePercent"`
+	LostClicks         float64     `json:"lostClicks"`
+	PeakImpressions    float64     `json:"peakImpressions"`
+	CurrentImpressions float64     `json:"currentImpressions"`
+	MonthlyClicks      []float64   `json:"monthlyClicks"`
+	TopQueryDrops      []QueryDrop `json:"topQueryDrops"`
+}
+
+// DecayResult is the result of FindDecayingContent.
+type DecayResult struct {
+	SiteURL         string                      `json:"siteUrl"`
+	StartDate       string                      `json:"startDate"`
+	EndDate         string                      `json:"endDate"`
+	SearchType      string                      `json:"searchType"`
+	Months          []string                    `json:"months"`
+	MinDecline      float64                     `json:"minDecline"`
+	SustainedMonths int                         `json:"sustainedMonths"`
+	MinPeakClicks   float64                     `json:"minPeakClicks"`
+	PagesAnalyzed   int                         `json:"pagesAnalyzed"`
+	DecayingCount   int                         `json:"decayingCount"`
+	Truncated       bool                        `json:"truncated"`
+	Warnings        []searchconsole.DataWarning `json:"warnings,omitempty"`
+	Pages           []DecayingPage              `json:"pages"`
+	QueriedAt       time.Time                   `json:"queriedAt"`
+}
+
+// DecayOptions controls FindDecayingContent. A page is decaying when each of
+// its last SustainedMonths months has at least MinDecline percent fewer
+// clicks than its peak in the Months-month lookback, and that peak had at
+// least MinPeakClicks clicks. At most Limit pages, ordered by lost clicks,
+// are returned.
+type DecayOptions struct {
+	Months          int
+	MinDecline      float64
+	SustainedMonths int
+	MinPeakClicks   float64
+	Limit           int
+}
+
+// Validate reports whether o can be used, so callers can reject it before
+// fetching any rows.
+func (o DecayOptions) Validate() error {
+	switch {
+	case o.SustainedMonths < 1:
+		return fmt.Errorf("invalid sustained_months %d: must be positive", o.SustainedMonths)
+	case o.Months <= o.SustainedMonths || o.Months > MaxDecayMonths:
+		return fmt.Errorf("invalid months %d: must be more than sustained_months (%d) and at most %d",
+			o.Months, o.SustainedMonths, MaxDecayMonths)
+	case o.MinDecline <= 0 || o.MinDecline > 100:
+		return fmt.Errorf("invalid min_decline %g: must be above 0 and at most 100", o.MinDecline)
+	case o.MinPeakClicks < 0:
+		return fmt.Errorf("invalid min_peak_clicks %g: must not be negative", o.MinPeakClicks)
+	case o.Limit <= 0:
+		return fmt.Errorf("invalid limit %d: must be positive", o.Limit)
+	}
+	return nil
+}
+
+// FindDecayingContent flags pages with a sustained decline in clicks across
+// monthly, which holds one response per month, oldest first, each with the
+// single dimension page. A page missing from a month had no clicks in it.
+// TopQueryDrops is left empty for AddQueryDrops to fill in.
+func FindDecayingContent(monthly []MonthlyResponse, options DecayOptions) (*DecayResult, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	if len(monthly) != options.Months {
+		return nil, fmt.Errorf("expected %d months of data, got %d", options.Months, len(monthly))
+	}
+	months := make([]string, len(monthly))
+	type pageSeries struct{ clicks, impressions []float64 }
+	pages := make(map[string]*pageSeries)
+	truncated := false
+	responses := make([]*searchconsole.SearchAnalyticsResponse, len(monthly))
+	for i, m := range monthly {
+		if !slices.Equal(m.Response.Dimensions, []string{"page"}) {
+			return nil, errors.New("content decay requires monthly responses with the single dimension page")
+		}
+		months[i] = m.Month
+		truncated = truncated || m.Response.Truncated
+		responses[i] = m.Response
+		for _, row := range m.Response.Rows {
+			if len(row.Keys) != 1 {
+				continue
+			}
+			series, ok := pages[row.Keys[0]]
+			if !ok {
+				series = &pageSeries{clicks: make([]float64, len(monthly)), impressions: make([]float64, len(monthly))}
+				pages[row.Keys[0]] = series
+			}
+			series.clicks[i] += row.Clicks
+			series.impressions[i] += row.Impressions
+		}
+	}
+
+	current := len(monthly) - 1
+	decayStart := len(monthly) - options.SustainedMonths
+	var decaying []DecayingPage
+	for page, series := range pages {
+		// The peak is the best month before the sustained window; the
+		// earliest month wins ties.
+		peak := 0
+		for i := 1; i < decayStart; i++ {
+			if series.clicks[i] > series.clicks[peak] {
+				peak = i
+			}
+		}
+		peakClicks := series.clicks[peak]
+		if peakClicks == 0 || peakClicks < options.MinPeakClicks {
+			continue
+		}
+		ceiling := peakClicks * (1 - options.MinDecline/100)
+		if slices.ContainsFunc(series.clicks[decayStart:], func(clicks float64) bool { return clicks > ceiling }) {
+			continue
+		}
+		decaying = append(decaying, DecayingPage{
+			Page:               page,
+			PeakMonth:          months[peak],
+			PeakClicks:         peakClicks,
+			CurrentMonth:       months[current],
+			CurrentClicks:      series.clicks[current],
+			DeclinePercent:     math.Round((peakClicks-series.clicks[current])/peakClicks*1000) / 10,
+			LostClicks:         peakClicks - series.clicks[current],
+			PeakImpressions:    series.impressions[peak],
+			CurrentImpressions: series.impressions[current],
+			MonthlyClicks:      series.clicks,
+			TopQueryDrops:      []QueryDrop{},
+		})
+	}
+	slices.SortFunc(decaying, func(a, b DecayingPage) int {
+		return cmp.Or(cmp.Compare(b.LostClicks, a.LostClicks), cmp.Compare(a.Page, b.Page))
+	})
+
+	first, last := monthly[0].Response, monthly[current].Response
+	return &DecayResult{
+		SiteURL:         last.SiteURL,
+		StartDate:       first.StartDate,
+		EndDate:         last.EndDate,
+		SearchType:      last.SearchType,
+		Months:          months,
+		MinDecline:      options.MinDecline,
+		SustainedMonths: options.SustainedMonths,
+		MinPeakClicks:   options.MinPeakClicks,
+		PagesAnalyzed:   len(pages),
+		DecayingCount:   len(decaying),
+		Truncated:       truncated,
+		Warnings:        mergeWarnings(responses...),
+		Pages:           firstN(decaying, options.Limit),
+		QueriedAt:       last.QueriedAt,
+	}, nil
+}
+
+// PeakMonths returns the distinct peak months of result's pages, in order.
+func (r *DecayResult) PeakMonths() []string {
+	var months []string
+	for _, page := range r.Pages {
+		if !slices.Contains(months, page.PeakMonth) {
+			months = append(months, page.PeakMonth)
+		}
+	}
+	slices.Sort(months)
+	return months
+}
+
+// AddQueryDrops fills in each page's TopQueryDrops with its limit queries
+// whose clicks fell the most from its peak month to the current month.
+// byMonth maps each month to a response with the dimensions page and query,
+// and must cover the current month and every page's peak month.
+func AddQueryDrops(result *DecayResult, byMonth map[string]*searchconsole.SearchAnalyticsResponse, limit int) error {
+	// clicks[month][page][query]
+	clicks := make(map[string]map[string]map[string]float64, len(byMonth))
+	for month, resp := range byMonth {
+		if !slices.Equal(resp.Dimensions, []string{"page", "query"}) {
+			return errors.New("query drops require responses with the dimensions page and query")
+		}
+		clicks[month] = make(map[string]map[string]float64)
+		for _, row := range resp.Rows {
+			if len(row.Keys) != 2 {
+				continue
+			}
+			byQuery, ok := clicks[month][row.Keys[0]]
+			if !ok {
+				byQuery = make(map[string]float64)
+				clicks[month][row.Keys[0]] = byQuery
+			}
+			byQuery[row.Keys[1]] += row.Clicks
+		}
+	}
+
+	for i, page := range result.Pages {
+		peak, ok := clicks[page.PeakMonth]
+		if !ok {
+			return fmt.Errorf("no query data for peak month %s", page.PeakMonth)
+		}
+		current, ok := clicks[page.CurrentMonth]
+		if !ok {
+			return fmt.Errorf("no query data for current month %s", page.CurrentMonth)
+		}
+		var drops []QueryDrop
+		for query, peakClicks := range peak[page.Page] {
+			currentClicks := current[page.Page][query]
+			if currentClicks >= peakClicks {
+				continue
+			}
+			drops = append(drops, QueryDrop{
+				Query:         query,
+				PeakClicks:    peakClicks,
+				CurrentClicks: currentClicks,
+				ClicksChange:  currentClicks - peakClicks,
+			})
+		}
+		slices.SortFunc(drops, func(a, b QueryDrop) int {
+			return cmp.Or(cmp.Compare(a.ClicksChange, b.ClicksChange), cmp.Compare(a.Query, b.Query))
+		})
+		result.Pages[i].TopQueryDrops = append([]QueryDrop{}, firstN(drops, limit)...)
+	}
+	return nil
+}
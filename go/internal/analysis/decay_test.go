@@ -0,0 +1,120 @@
+package analysis_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// monthlyPages builds one page-dimension response per month from each page's
+// clicks, skipping months in which a page has no clicks.
+func monthlyPages(clicksByPage map[string][]float64, months int) []analysis.MonthlyResponse {
+	monthly := make([]analysis.MonthlyResponse, months)
+	for i := range monthly {
+		resp := &searchconsole.SearchAnalyticsResponse{
+			StartDate:  fmt.Sprintf("2025-%02d-01", i+1),
+			EndDate:    fmt.Sprintf("2025-%02d-28", i+1),
+			Dimensions: []string{"page"},
+		}
+		for page, clicks := range clicksByPage {
+			if clicks[i] > 0 {
+				resp.Rows = append(resp.Rows, row([]string{page}, clicks[i], clicks[i]*10, 5))
+			}
+		}
+		monthly[i] = analysis.MonthlyResponse{Month: fmt.Sprintf("2025-%02d", i+1), Response: resp}
+	}
+	return monthly
+}
+
+func TestFindDecayingContent_FlagsSustainedDeclinesOnly(t *testing.T) {
+	t.Parallel()
+
+	monthly := monthlyPages(map[string][]float64{
+		"/decaying": {100, 120, 90, 60, 50, 40},
+		"/one-dip":  {100, 100, 100, 100, 100, 20},
+		"/tiny":     {5, 5, 5, 1, 1, 1},
+		"/vanished": {50, 40, 30, 0, 0, 0},
+		"/growing":  {10, 20, 30, 40, 50, 60},
+	}, 6)
+	options := analysis.DecayOptions{Months: 6, MinDecline: 30, SustainedMonths: 3, MinPeakClicks: 10, Limit: 10}
+
+	got, err := analysis.FindDecayingContent(monthly, options)
+	if err != nil {
+		t.Fatalf("FindDecayingContent: %v", err)
+	}
+	if got.PagesAnalyzed != 5 || got.DecayingCount != 2 || len(got.Pages) != 2 {
+		t.Fatalf("result = %+v, want /decaying and /vanished", got)
+	}
+	first := got.Pages[0]
+	if first.Page != "/decaying" || first.PeakMonth != "2025-02" || first.PeakClicks != 120 ||
+		first.CurrentMonth != "2025-06" || first.CurrentClicks != 40 || first.LostClicks != 80 ||
+		!approxEqual(first.DeclinePercent, 66.7) || first.PeakImpressions != 1200 || first.CurrentImpressions != 400 {
+		t.Errorf("pages[0] = %+v", first)
+	}
+	if len(first.MonthlyClicks) != 6 || first.MonthlyClicks[3] != 60 {
+		t.Errorf("monthlyClicks = %v", first.MonthlyClicks)
+	}
+	if second := got.Pages[1]; second.Page != "/vanished" || second.PeakMonth != "2025-01" || second.DeclinePercent != 100 {
+		t.Errorf("pages[1] = %+v", second)
+	}
+	if got.StartDate != "2025-01-01" || got.EndDate != "2025-06-28" || strings.Join(got.Months, ",") != "2025-01,2025-02,2025-03,2025-04,2025-05,2025-06" {
+		t.Errorf("range = %s..%s, months = %v", got.StartDate, got.EndDate, got.Months)
+	}
+	if peaks := got.PeakMonths(); strings.Join(peaks, ",") != "2025-01,2025-02" {
+		t.Errorf("PeakMonths = %v", peaks)
+	}
+}
+
+func TestAddQueryDrops_RanksQueriesByLostClicks(t *testing.T) {
+	t.Parallel()
+
+	result := &analysis.DecayResult{Pages: []analysis.DecayingPage{
+		{Page: "/a", PeakMonth: "2025-02", CurrentMonth: "2025-06"},
+	}}
+	queries := func(rows ...searchconsole.SearchAnalyticsRow) *searchconsole.SearchAnalyticsResponse {
+		return &searchconsole.SearchAnalyticsResponse{Dimensions: []string{"page", "query"}, Rows: rows}
+	}
+	err := analysis.AddQueryDrops(result, map[string]*searchconsole.SearchAnalyticsResponse{
+		"2025-02": queries(
+			row([]string{"/a", "blazor forms"}, 50, 500, 3),
+			row([]string{"/a", "blazor validation"}, 30, 300, 4),
+			row([]string{"/a", "blazor grid"}, 5, 50, 9),
+			row([]string{"/b", "other"}, 99, 990, 1),
+		),
+		"2025-06": queries(
+			row([]string{"/a", "blazor forms"}, 10, 300, 6),
+			row([]string{"/a", "blazor grid"}, 8, 80, 7),
+		),
+	}, 5)
+	if err != nil {
+		t.Fatalf("AddQueryDrops: %v", err)
+	}
+	drops := result.Pages[0].TopQueryDrops
+	if len(drops) != 2 || drops[0].Query != "blazor forms" || drops[0].ClicksChange != -40 ||
+		drops[1].Query != "blazor validation" || drops[1].CurrentClicks != 0 {
+		t.Errorf("drops = %+v, want blazor forms then blazor validation", drops)
+	}
+}
+
+func TestDecayOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := analysis.DecayOptions{Months: 6, MinDecline: 30, SustainedMonths: 3, Limit: 10}
+	for want, options := range map[string]analysis.DecayOptions{
+		"invalid months 3":           {Months: 3, MinDecline: 30, SustainedMonths: 3, Limit: 10},
+		"invalid months 16":          {Months: 16, MinDecline: 30, SustainedMonths: 3, Limit: 10},
+		"invalid min_decline 120":    {Months: 6, MinDecline: 120, SustainedMonths: 3, Limit: 10},
+		"invalid sustained_months 0": {Months: 6, MinDecline: 30, Limit: 10},
+		"invalid min_peak_clicks -1": {Months: 6, MinDecline: 30, SustainedMonths: 3, MinPeakClicks: -1, Limit: 10},
+	} {
+		if err := options.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate(%+v) = %v, want %q", options, err, want)
+		}
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate(valid) = %v", err)
+	}
+}
@@ -0,0 +1,386 @@
+package analysis
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// Metrics ForecastTraffic projects.
+const (
+	ForecastMetricClicks      = "clicks"
+	ForecastMetricImpressions = "impressions"
+)
+
+// ForecastModel names the model ForecastTraffic fits.
+const ForecastModel = "additive_holt_winters"
+
+const (
+	// forecastSeasonLength is the weekly seasonality of daily search traffic.
+	forecastSeasonLength = 7
+
+	// MinForecastHistoryDays is the shortest history ForecastTraffic fits: two
+	// seasons to initialise the model and two more to fit it.
+	MinForecastHistoryDays = 4 * forecastSeasonLength
+
+	// MaxForecastHorizonDays is the furthest ahead ForecastTraffic projects.
+	MaxForecastHorizonDays = 365
+)
+
+// forecastZScores maps each supported confidence level, in percent, to its
+// two-sided standard normal quantile.
+var forecastZScores = map[float64]float64{
+	80: 1.2816,
+	90: 1.6449,
+	95: 1.9600,
+	99: 2.5758,
+}
+
+// Smoothing parameter grids searched when fitting. The trend grid stays
+// small, since a strongly smoothed trend extrapolates wildly over long
+// horizons.
+var (
+	forecastLevelGrid    = smoothingGrid(0.05, 0.95, 0.05)
+	forecastTrendGrid    = []float64{0, 0.01, 0.02, 0.05, 0.1, 0.2}
+	forecastSeasonalGrid = smoothingGrid(0.05, 0.95, 0.05)
+)
+
+func smoothingGrid(from, to, step float64) []float64 {
+	var grid []float64
+	for i := 0; from+float64(i)*step <= to+1e-9; i++ {
+		grid = append(grid, math.Round((from+float64(i)*step)*100)/100)
+	}
+	return grid
+}
+
+// ForecastPoint is the forecast for one day, with its prediction interval.
+// Values are never negative.
+type ForecastPoint struct {
+	Date  string  `json:"date"`
+	Value float64 `json:"value"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// ForecastDiagnostics describes how well the model fits. Alpha, Beta, and
+// Gamma are the fitted level, trend, and seasonal smoothing parameters. RMSE,
+// MAE, and MAPE measure the one-step-ahead errors over the history; MAPE, in
+// percent, skips days with no traffic and is nil when every day had none.
+// The Holdout fields come from refitting without the last HoldoutDays days
+// and forecasting them: HoldoutMAPE is that forecast's error and
+// HoldoutCoverage the share of those days that fell inside its intervals.
+// HoldoutDays is 0 when the history is too short to hold any days back.
+type ForecastDiagnostics struct {
+	Model           string   `json:"model"`
+	SeasonLength    int      `json:"seasonLength"`
+	Alpha           float64  `json:"alpha"`
+	Beta            float64  `json:"beta"`
+	Gamma           float64  `json:"gamma"`
+	Observations    int      `json:"observations"`
+	RMSE            float64  `json:"rmse"`
+	MAE             float64  `json:"mae"`
+	MAPE            *float64 `json:"mape"`
+	HoldoutDays     int      `json:"holdoutDays"`
+	HoldoutMAPE     *float64 `json:"holdoutMape"`
+	HoldoutCoverage *float64 `json:"holdoutCoverage"`
+}
+
+// MetricForecast is the forecast of one metric. HistoryTotal sums the metric
+// over the history and ForecastTotal sums the point forecasts.
+type MetricForecast struct {
+	Metric        string              `json:"metric"`
+	HistoryTotal  float64             `json:"historyTotal"`
+	ForecastTotal float64             `json:"forecastTotal"`
+	Diagnostics   ForecastDiagnostics `json:"diagnostics"`
+	Points        []ForecastPoint     `json:"points"`
+}
+
+// ForecastResult is the result of ForecastTraffic. StartDate and EndDate
+// bound the history queried; TrainingStart and TrainingEnd bound the days the
+// model was fitted to, which end earlier when the last days had no data.
+// ForecastStart and ForecastEnd bound the forecast.
+type ForecastResult struct {
+	SiteURL       string                      `json:"siteUrl"`
+	StartDate     string                      `json:"startDate"`
+	EndDate       string                      `json:"endDate"`
+	TrainingStart string                      `json:"trainingStart"`
+	TrainingEnd   string                      `json:"trainingEnd"`
+	TrainingDays  int                         `json:"trainingDays"`
+	SearchType    string                      `json:"searchType"`
+	ForecastStart string                      `json:"forecastStart"`
+	ForecastEnd   string                      `json:"forecastEnd"`
+	HorizonDays   int                         `json:"horizonDays"`
+	Confidence    float64                     `json:"confidence"`
+	Truncated     bool                        `json:"truncated"`
+	Warnings      []searchconsole.DataWarning `json:"warnings,omitempty"`
+	Forecasts     []MetricForecast            `json:"forecasts"`
+	QueriedAt     time.Time                   `json:"queriedAt"`
+}
+
+// ForecastOptions controls ForecastTraffic: how many days to project and the
+// confidence level of the prediction intervals, in percent.
+type ForecastOptions struct {
+	HorizonDays int
+	Confidence  float64
+}
+
+// Validate reports whether o can be used, so callers can reject it before
+// fetching any rows.
+func (o ForecastOptions) Validate() error {
+	if o.HorizonDays < 1 || o.HorizonDays > MaxForecastHorizonDays {
+		return fmt.Errorf("invalid horizon_days %d: must be between 1 and %d", o.HorizonDays, MaxForecastHorizonDays)
+	}
+	if _, ok := forecastZScores[o.Confidence]; !ok {
+		return fmt.Errorf("invalid confidence %g: must be 80, 90, 95, or 99", o.Confidence)
+	}
+	return nil
+}
+
+// ForecastTraffic fits an additive Holt-Winters model with weekly
+// seasonality to the daily clicks and impressions in resp, which must have
+// the single dimension date and a row for every day, as FillDateGaps leaves
+// it, and projects each forward from the day after the last one fitted.
+// Trailing filled rows are dropped first: zeros for days Search Console has
+// not reported yet would drag the level and trend down.
+func ForecastTraffic(resp *searchconsole.SearchAnalyticsResponse, options ForecastOptions) (*ForecastResult, error) {
+	if !slices.Equal(resp.Dimensions, []string{"date"}) {
+		return nil, errors.New("forecasting requires the single dimension date")
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	rows := slices.Clone(resp.Rows)
+	slices.SortFunc(rows, func(a, b searchconsole.SearchAnalyticsRow) int { return cmp.Compare(a.Keys[0], b.Keys[0]) })
+	for len(rows) > 0 && rows[len(rows)-1].Filled {
+		rows = rows[:len(rows)-1]
+	}
+	if len(rows) < MinForecastHistoryDays {
+		return nil, fmt.Errorf("forecasting needs at least %d days of history with data, got %d", MinForecastHistoryDays, len(rows))
+	}
+	end, err := time.Parse(time.DateOnly, rows[len(rows)-1].Keys[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", rows[len(rows)-1].Keys[0], err)
+	}
+
+	z := forecastZScores[options.Confidence]
+	result := &ForecastResult{
+		SiteURL:       resp.SiteURL,
+		StartDate:     resp.StartDate,
+		EndDate:       resp.EndDate,
+		TrainingStart: rows[0].Keys[0],
+		TrainingEnd:   rows[len(rows)-1].Keys[0],
+		TrainingDays:  len(rows),
+		SearchType:    resp.SearchType,
+		ForecastStart: end.AddDate(0, 0, 1).Format(time.DateOnly),
+		ForecastEnd:   end.AddDate(0, 0, options.HorizonDays).Format(time.DateOnly),
+		HorizonDays:   options.HorizonDays,
+		Confidence:    options.Confidence,
+		Truncated:     resp.Truncated,
+		Warnings:      mergeWarnings(resp),
+		QueriedAt:     resp.QueriedAt,
+	}
+	for _, metric := range []string{ForecastMetricClicks, ForecastMetricImpressions} {
+		series := make([]float64, len(rows))
+		for i, row := range rows {
+			series[i] = row.Clicks
+			if metric == ForecastMetricImpressions {
+				series[i] = row.Impressions
+			}
+		}
+		result.Forecasts = append(result.Forecasts, forecastSeries(metric, series, end, options.HorizonDays, z))
+	}
+	return result, nil
+}
+
+// forecastSeries fits series, a daily history ending on end, and forecasts
+// horizon days past it.
+func forecastSeries(metric string, series []float64, end time.Time, horizon int, z float64) MetricForecast {
+	model := fitHoltWinters(series)
+	values, lower, upper := model.forecast(horizon, z)
+
+	forecast := MetricForecast{
+		Metric: metric,
+		Diagnostics: ForecastDiagnostics{
+			Model:        ForecastModel,
+			SeasonLength: forecastSeasonLength,
+			Alpha:        model.alpha,
+			Beta:         model.beta,
+			Gamma:        model.gamma,
+			Observations: len(series),
+			RMSE:         model.rmse(),
+			MAE:          model.mae(),
+			MAPE:         meanAbsolutePercentError(model.actuals, model.predictions),
+		},
+		Points: make([]ForecastPoint, horizon),
+	}
+	for _, value := range series {
+		forecast.HistoryTotal += value
+	}
+	for h := range horizon {
+		forecast.Points[h] = ForecastPoint{
+			Date:  end.AddDate(0, 0, h+1).Format(time.DateOnly),
+			Value: values[h],
+			Lower: lower[h],
+			Upper: upper[h],
+		}
+		forecast.ForecastTotal += values[h]
+	}
+
+	// Backtest: hold back the end of the history, up to a quarter of it or
+	// the horizon, keeping enough to fit.
+	holdout := min(horizon, len(series)/4, len(series)-MinForecastHistoryDays)
+	if holdout > 0 {
+		training, actual := series[:len(series)-holdout], series[len(series)-holdout:]
+		predicted, lower, upper := fitHoltWinters(training).forecast(holdout, z)
+		inside := 0
+		for i, value := range actual {
+			if value >= lower[i] && value <= upper[i] {
+				inside++
+			}
+		}
+		coverage := float64(inside) / float64(holdout)
+		forecast.Diagnostics.HoldoutDays = holdout
+		forecast.Diagnostics.HoldoutMAPE = meanAbsolutePercentError(actual, predicted)
+		forecast.Diagnostics.HoldoutCoverage = &coverage
+	}
+	return forecast
+}
+
+// holtWinters is a fitted additive Holt-Winters model: its parameters, its
+// state after the last observation, and its one-step-ahead predictions of
+// every observation after the first season.
+type holtWinters struct {
+	alpha, beta, gamma float64
+	level, trend       float64
+	seasonal           []float64
+	actuals            []float64
+	predictions        []float64
+	sse                float64
+}
+
+// fitHoltWinters searches the smoothing parameter grids for the model with
+// the smallest one-step-ahead squared error.
+func fitHoltWinters(series []float64) holtWinters {
+	var best holtWinters
+	first := true
+	for _, alpha := range forecastLevelGrid {
+		for _, beta := range forecastTrendGrid {
+			for _, gamma := range forecastSeasonalGrid {
+				model := runHoltWinters(series, alpha, beta, gamma)
+				if first || model.sse < best.sse {
+					best, first = model, false
+				}
+			}
+		}
+	}
+	return best
+}
+
+// runHoltWinters runs the model over series with fixed parameters. The level
+// and trend start from the means of the first two seasons and the seasonal
+// components from the first season's deviations from its mean.
+func runHoltWinters(series []float64, alpha, beta, gamma float64) holtWinters {
+	m := forecastSeasonLength
+	firstMean, secondMean := mean(series[:m]), mean(series[m:2*m])
+	model := holtWinters{
+		alpha:    alpha,
+		beta:     beta,
+		gamma:    gamma,
+		level:    firstMean,
+		trend:    (secondMean - firstMean) / float64(m),
+		seasonal: make([]float64, len(series)),
+	}
+	for i := range m {
+		model.seasonal[i] = series[i] - firstMean
+	}
+	for t := m; t < len(series); t++ {
+		y, season := series[t], model.seasonal[t-m]
+		predicted := model.level + model.trend + season
+		model.actuals = append(model.actuals, y)
+		model.predictions = append(model.predictions, predicted)
+		model.sse += (y - predicted) * (y - predicted)
+
+		level := alpha*(y-season) + (1-alpha)*(model.level+model.trend)
+		model.trend = beta*(level-model.level) + (1-beta)*model.trend
+		model.level = level
+		model.seasonal[t] = gamma*(y-level) + (1-gamma)*season
+	}
+	model.seasonal = model.seasonal[len(series)-m:]
+	return model
+}
+
+// forecast projects horizon steps ahead with prediction intervals of z
+// standard errors, using the additive Holt-Winters forecast variance. Values
+// and bounds are clamped at zero, since traffic cannot be negative.
+func (m holtWinters) forecast(horizon int, z float64) (values, lower, upper []float64) {
+	sigma := m.rmse()
+	season := len(m.seasonal)
+	values, lower, upper = make([]float64, horizon), make([]float64, horizon), make([]float64, horizon)
+	variance := 1.0
+	for h := 1; h <= horizon; h++ {
+		if h > 1 {
+			j := h - 1
+			c := m.alpha * (1 + float64(j)*m.beta)
+			if j%season == 0 {
+				c += m.gamma
+			}
+			variance += c * c
+		}
+		value := m.level + float64(h)*m.trend + m.seasonal[(h-1)%season]
+		spread := z * sigma * math.Sqrt(variance)
+		values[h-1] = max(value, 0)
+		lower[h-1] = max(value-spread, 0)
+		upper[h-1] = max(value+spread, 0)
+	}
+	return values, lower, upper
+}
+
+func (m holtWinters) rmse() float64 {
+	if len(m.actuals) == 0 {
+		return 0
+	}
+	return math.Sqrt(m.sse / float64(len(m.actuals)))
+}
+
+func (m holtWinters) mae() float64 {
+	if len(m.actuals) == 0 {
+		return 0
+	}
+	var total float64
+	for i, actual := range m.actuals {
+		total += math.Abs(actual - m.predictions[i])
+	}
+	return total / float64(len(m.actuals))
+}
+
+// meanAbsolutePercentError returns the MAPE of predicted against actual, in
+// percent, over the days with traffic, or nil if there were none.
+func meanAbsolutePercentError(actual, predicted []float64) *float64 {
+	var total float64
+	count := 0
+	for i, value := range actual {
+		if value == 0 {
+			continue
+		}
+		total += math.Abs(value-predicted[i]) / value * 100
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+	mape := total / float64(count)
+	return &mape
+}
+
+func mean(values []float64) float64 {
+	var total float64
+	for _, value := range values {
+		total += value
+	}
+	return total / float64(len(values))
+}
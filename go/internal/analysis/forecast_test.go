@@ -0,0 +1,178 @@
+package analysis_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// dailySeries builds a date-dimension response starting 2026-01-05, a Monday,
+// with clicks from clicksOn and ten times as many impressions.
+func dailySeries(days int, clicksOn func(day int) float64) *searchconsole.SearchAnalyticsResponse {
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	resp := &searchconsole.SearchAnalyticsResponse{
+		SiteURL:    "sc-domain:devleader.ca",
+		StartDate:  start.Format(time.DateOnly),
+		EndDate:    start.AddDate(0, 0, days-1).Format(time.DateOnly),
+		Dimensions: []string{"date"},
+	}
+	for day := range days {
+		clicks := clicksOn(day)
+		resp.Rows = append(resp.Rows, row([]string{start.AddDate(0, 0, day).Format(time.DateOnly)}, clicks, clicks*10, 5))
+	}
+	return resp
+}
+
+var weekdayPattern = []float64{20, 25, 25, 20, 10, -45, -55}
+
+func TestForecastTraffic_RepeatsAnExactWeeklyPattern(t *testing.T) {
+	t.Parallel()
+
+	resp := dailySeries(56, func(day int) float64 { return 100 + weekdayPattern[day%7] })
+	got, err := analysis.ForecastTraffic(resp, analysis.ForecastOptions{HorizonDays: 10, Confidence: 95})
+	if err != nil {
+		t.Fatalf("ForecastTraffic: %v", err)
+	}
+	if got.ForecastStart != "2026-03-02" || got.ForecastEnd != "2026-03-11" || len(got.Forecasts) != 2 {
+		t.Fatalf("result = %+v, want clicks and impressions from 2026-03-02", got)
+	}
+	clicks := got.Forecasts[0]
+	if clicks.Metric != analysis.ForecastMetricClicks || clicks.HistoryTotal != 5600 || len(clicks.Points) != 10 {
+		t.Fatalf("clicks = %+v", clicks)
+	}
+	for h, point := range clicks.Points {
+		want := 100 + weekdayPattern[h%7]
+		if !approxEqual(point.Value, want) || !approxEqual(point.Lower, want) || !approxEqual(point.Upper, want) {
+			t.Errorf("points[%d] = %+v, want exactly %g", h, point, want)
+		}
+	}
+	if !approxEqual(clicks.ForecastTotal, 1000+20+25+25) {
+		t.Errorf("forecastTotal = %g", clicks.ForecastTotal)
+	}
+	diagnostics := clicks.Diagnostics
+	if diagnostics.Model != analysis.ForecastModel || diagnostics.SeasonLength != 7 || diagnostics.Observations != 56 ||
+		diagnostics.RMSE > 1e-9 || diagnostics.MAPE == nil || *diagnostics.MAPE > 1e-9 {
+		t.Errorf("diagnostics = %+v, want a perfect fit", diagnostics)
+	}
+	if diagnostics.HoldoutDays != 10 || diagnostics.HoldoutCoverage == nil || *diagnostics.HoldoutCoverage != 1 {
+		t.Errorf("holdout = %d days, coverage %v, want 10 days all covered", diagnostics.HoldoutDays, diagnostics.HoldoutCoverage)
+	}
+	if impressions := got.Forecasts[1]; impressions.Metric != analysis.ForecastMetricImpressions || !approxEqual(impressions.Points[0].Value, 1200) {
+		t.Errorf("impressions = %+v", impressions.Points[0])
+	}
+}
+
+func TestForecastTraffic_ExtrapolatesTrendWithWideningIntervals(t *testing.T) {
+	t.Parallel()
+
+	// A growing weekly pattern with a little deterministic noise.
+	noise := []float64{3, -2, 4, -5, 1, -3, 2, 0, -4, 5, -1}
+	resp := dailySeries(140, func(day int) float64 { return 100 + 2*float64(day) + weekdayPattern[day%7] + noise[day%11] })
+	got, err := analysis.ForecastTraffic(resp, analysis.ForecastOptions{HorizonDays: 28, Confidence: 80})
+	if err != nil {
+		t.Fatalf("ForecastTraffic: %v", err)
+	}
+	clicks := got.Forecasts[0]
+	for _, h := range []int{0, 13, 27} {
+		want := 100 + 2*float64(140+h) + weekdayPattern[(140+h)%7]
+		if got := clicks.Points[h].Value; math.Abs(got-want)/want > 0.05 {
+			t.Errorf("points[%d].value = %.1f, want about %.1f", h, got, want)
+		}
+	}
+	first, last := clicks.Points[0], clicks.Points[27]
+	if first.Upper-first.Lower <= 0 || last.Upper-last.Lower <= first.Upper-first.Lower {
+		t.Errorf("interval widths = %g then %g, want positive and widening", first.Upper-first.Lower, last.Upper-last.Lower)
+	}
+	diagnostics := clicks.Diagnostics
+	if diagnostics.RMSE <= 0 || diagnostics.MAE <= 0 || diagnostics.MAE > diagnostics.RMSE ||
+		diagnostics.HoldoutDays != 28 || diagnostics.HoldoutMAPE == nil || *diagnostics.HoldoutMAPE > 10 {
+		t.Errorf("diagnostics = %+v", diagnostics)
+	}
+	for _, parameter := range []float64{diagnostics.Alpha, diagnostics.Beta, diagnostics.Gamma} {
+		if parameter < 0 || parameter > 1 {
+			t.Errorf("smoothing parameter %g outside [0, 1]", parameter)
+		}
+	}
+}
+
+func TestForecastTraffic_DropsTrailingFilledDays(t *testing.T) {
+	t.Parallel()
+
+	resp := dailySeries(35, func(day int) float64 { return 100 + weekdayPattern[day%7] })
+	for i := 30; i < 35; i++ {
+		resp.Rows[i] = searchconsole.SearchAnalyticsRow{Keys: resp.Rows[i].Keys, Filled: true}
+	}
+	resp.Rows[10].Filled = true // a filled day inside the history is kept
+	got, err := analysis.ForecastTraffic(resp, analysis.ForecastOptions{HorizonDays: 7, Confidence: 95})
+	if err != nil {
+		t.Fatalf("ForecastTraffic: %v", err)
+	}
+	if got.TrainingStart != "2026-01-05" || got.TrainingEnd != "2026-02-03" || got.TrainingDays != 30 ||
+		got.EndDate != "2026-02-08" || got.ForecastStart != "2026-02-04" {
+		t.Errorf("result = %+v, want training to stop at 2026-02-03 and the forecast to start after it", got)
+	}
+	if observations := got.Forecasts[0].Diagnostics.Observations; observations != 30 {
+		t.Errorf("observations = %d, want 30", observations)
+	}
+}
+
+func TestForecastTraffic_ClampsAtZero(t *testing.T) {
+	t.Parallel()
+
+	resp := dailySeries(42, func(day int) float64 { return max(60-2*float64(day), 0) })
+	got, err := analysis.ForecastTraffic(resp, analysis.ForecastOptions{HorizonDays: 30, Confidence: 95})
+	if err != nil {
+		t.Fatalf("ForecastTraffic: %v", err)
+	}
+	for _, point := range got.Forecasts[0].Points {
+		if point.Value < 0 || point.Lower < 0 || point.Upper < 0 {
+			t.Fatalf("point = %+v, want no negative values", point)
+		}
+	}
+}
+
+func TestForecastTraffic_NoTraffic_HasNoMAPE(t *testing.T) {
+	t.Parallel()
+
+	got, err := analysis.ForecastTraffic(dailySeries(28, func(int) float64 { return 0 }), analysis.ForecastOptions{HorizonDays: 7, Confidence: 95})
+	if err != nil {
+		t.Fatalf("ForecastTraffic: %v", err)
+	}
+	if diagnostics := got.Forecasts[0].Diagnostics; diagnostics.MAPE != nil || diagnostics.HoldoutDays != 0 || diagnostics.HoldoutCoverage != nil {
+		t.Errorf("diagnostics = %+v, want no MAPE and no holdout", diagnostics)
+	}
+}
+
+func TestForecastTraffic_RejectsUnusableInput(t *testing.T) {
+	t.Parallel()
+
+	options := analysis.ForecastOptions{HorizonDays: 7, Confidence: 95}
+	flat := func(int) float64 { return 10 }
+	byPage := dailySeries(28, flat)
+	byPage.Dimensions = []string{"date", "page"}
+	cases := []struct {
+		name    string
+		resp    *searchconsole.SearchAnalyticsResponse
+		options analysis.ForecastOptions
+		want    string
+	}{
+		{"short history", dailySeries(27, flat), options, "at least 28 days of history with data, got 27"},
+		{"other dimensions", byPage, options, "single dimension date"},
+		{"zero horizon", dailySeries(28, flat), analysis.ForecastOptions{HorizonDays: 0, Confidence: 95}, "invalid horizon_days 0"},
+		{"long horizon", dailySeries(28, flat), analysis.ForecastOptions{HorizonDays: 366, Confidence: 95}, "invalid horizon_days 366"},
+		{"confidence", dailySeries(28, flat), analysis.ForecastOptions{HorizonDays: 7, Confidence: 97}, "invalid confidence 97"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := analysis.ForecastTraffic(tc.resp, tc.options)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
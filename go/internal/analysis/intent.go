@@ -0,0 +1,276 @@
+package analysis
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// Search intents, as set on SearchAnalyticsRow.Intent.
+const (
+	IntentInformational = "informational"
+	IntentNavigational  = "navigational"
+	IntentCommercial    = "commercial"
+	IntentTransactional = "transactional"
+	IntentUnclassified  = "unclassified"
+)
+
+// Intents lists every intent Classify returns, in the order rollups report
+// them.
+var Intents = []string{IntentInformational, IntentNavigational, IntentCommercial, IntentTransactional, IntentUnclassified}
+
+// IntentRules lists the keywords of each intent and the question words that
+// make a query informational when it starts with one.
+type IntentRules struct {
+	Informational []string
+	Navigational  []string
+	Commercial    []string
+	Transactional []string
+	QuestionWords []string
+}
+
+// DefaultIntentRules returns the built-in English keyword lists.
+func DefaultIntentRules() IntentRules {
+	return IntentRules{
+		Informational: []string{
+			"guide", "tutorial", "tutorials", "example", "examples", "meaning", "definition", "define",
+			"learn", "tips", "explained", "ideas", "difference",
+		},
+		Navigational: []string{
+			"login", "log in", "sign in", "signin", "website", "official", "homepage", "home page", "account", "dashboard",
+		},
+		Commercial: []string{
+			"best", "top", "review", "reviews", "vs", "versus", "compare", "comparison", "alternative", "alternatives",
+		},
+		Transactional: []string{
+			"buy", "purchase", "order", "price", "prices", "pricing", "cost", "cheap", "cheapest", "discount",
+			"coupon", "deal", "deals", "sale", "subscribe", "subscription", "download", "free trial", "hire", "quote", "shop",
+		},
+		QuestionWords: []string{
+			"how", "what", "why", "when", "where", "who", "which", "can", "could", "does", "do", "is", "are", "should", "will", "would",
+		},
+	}
+}
+
+// Extend returns r with other's keywords and question words appended.
+func (r IntentRules) Extend(other IntentRules) IntentRules {
+	return IntentRules{
+		Informational: slices.Concat(r.Informational, other.Informational),
+		Navigational:  slices.Concat(r.Navigational, other.Navigational),
+		Commercial:    slices.Concat(r.Commercial, other.Commercial),
+		Transactional: slices.Concat(r.Transactional, other.Transactional),
+		QuestionWords: slices.Concat(r.QuestionWords, other.QuestionWords),
+	}
+}
+
+// IntentClassifier assigns queries a search intent by rules.
+type IntentClassifier struct {
+	// keywords holds the tokenised keywords of each intent, in precedence
+	// order.
+	keywords      []intentKeywords
+	questionWords map[string]bool
+	brand         *BrandMatcher
+}
+
+type intentKeywords struct {
+	intent  string
+	phrases [][]string
+}
+
+// NewIntentClassifier returns a classifier for rules. A query mentioning
+// brand, when it is non-nil, is navigational.
+func NewIntentClassifier(rules IntentRules, brand *BrandMatcher) (*IntentClassifier, error) {
+	c := &IntentClassifier{questionWords: map[string]bool{}, brand: brand}
+	// Earlier intents win: a query naming the brand or a product page is
+	// navigational, "buy" outweighs "best", and any purchase or comparison
+	// wording outweighs a question word.
+	for _, entry := range []struct {
+		intent   string
+		keywords []string
+	}{
+		{IntentNavigational, rules.Navigational},
+		{IntentTransactional, rules.Transactional},
+		{IntentCommercial, rules.Commercial},
+		{IntentInformational, rules.Informational},
+	} {
+		group := intentKeywords{intent: entry.intent}
+		for _, keyword := range entry.keywords {
+			if tokens := queryTokens(keyword); len(tokens) > 0 {
+				group.phrases = append(group.phrases, tokens)
+			}
+		}
+		c.keywords = append(c.keywords, group)
+	}
+	for _, word := range rules.QuestionWords {
+		if tokens := queryTokens(word); len(tokens) == 1 {
+			c.questionWords[tokens[0]] = true
+		} else if len(tokens) > 1 {
+			return nil, fmt.Errorf("invalid question word %q: must be a single word", word)
+		}
+	}
+	if brand == nil && len(c.questionWords) == 0 && !slices.ContainsFunc(c.keywords, func(k intentKeywords) bool { return len(k.phrases) > 0 }) {
+		return nil, errors.New("at least one intent keyword or question word is required")
+	}
+	return c, nil
+}
+
+// Classify returns the intent of query: navigational if it names the brand or
+// has a navigational keyword, otherwise the first of transactional,
+// commercial, and informational whose keywords it contains, informational
+// also if it starts with a question word, and IntentUnclassified otherwise.
+func (c *IntentClassifier) Classify(query string) string {
+	if c.brand != nil && c.brand.IsBranded(query) {
+		return IntentNavigational
+	}
+	tokens := queryTokens(query)
+	for _, group := range c.keywords {
+		for _, phrase := range group.phrases {
+			if containsPhrase(tokens, phrase) {
+				return group.intent
+			}
+		}
+	}
+	if len(tokens) > 0 && c.questionWords[tokens[0]] {
+		return IntentInformational
+	}
+	return IntentUnclassified
+}
+
+// queryTokens splits s into lowercase words, treating anything but letters
+// and digits as a separator. Intent keywords and brand terms both match whole
+// tokens.
+func queryTokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsPhrase reports whether phrase occurs as consecutive tokens.
+func containsPhrase(tokens, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(tokens); i++ {
+		if slices.Equal(tokens[i:i+len(phrase)], phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClassifyIntents sets the intent of every row of resp, which must include
+// the query dimension, and sets resp.Intents to the totals of each intent
+// that occurs, in the order of Intents.
+func ClassifyIntents(resp *searchconsole.SearchAnalyticsResponse, classifier *IntentClassifier) error {
+	queryIndex := slices.Index(resp.Dimensions, "query")
+	if queryIndex < 0 {
+		return errors.New("intent classification requires the query dimension")
+	}
+	byIntent := map[string][]searchconsole.SearchAnalyticsRow{}
+	for i, row := range resp.Rows {
+		if queryIndex >= len(row.Keys) {
+			continue
+		}
+		intent := classifier.Classify(row.Keys[queryIndex])
+		resp.Rows[i].Intent = intent
+		byIntent[intent] = append(byIntent[intent], resp.Rows[i])
+	}
+	resp.Intents = nil
+	for _, intent := range Intents {
+		if rows, ok := byIntent[intent]; ok {
+			resp.Intents = append(resp.Intents, segmentTotals(intent, rows))
+		}
+	}
+	return nil
+}
+
+// IntentGroup is the rolled-up performance of the queries of one intent.
+// Clicks and impressions are summed, CTR is recomputed from the sums, and
+// Position is weighted by impressions. ClickShare and ImpressionShare are
+// the intent's fractions (0-1) of all queries' clicks and impressions.
+type IntentGroup struct {
+	Intent          string                             `json:"intent"`
+	QueryCount      int                                `json:"queryCount"`
+	Clicks          float64                            `json:"clicks"`
+	Impressions     float64                            `json:"impressions"`
+	CTR             float64                            `json:"ctr"`
+	Position        float64                            `json:"position"`
+	ClickShare      float64                            `json:"clickShare"`
+	ImpressionShare float64                            `json:"impressionShare"`
+	TopQueries      []searchconsole.SearchAnalyticsRow `json:"topQueries"`
+}
+
+// IntentRollupResult is the result of RollupIntents. Intents has a group for
+// every intent, even one with no queries, in the order of Intents.
+type IntentRollupResult struct {
+	SiteURL    string                           `json:"siteUrl"`
+	StartDate  string                           `json:"startDate"`
+	EndDate    string                           `json:"endDate"`
+	SearchType string                           `json:"searchType"`
+	QueryCount int                              `json:"queryCount"`
+	Totals     searchconsole.SearchAnalyticsRow `json:"totals"`
+	Truncated  bool                             `json:"truncated"`
+	Warnings   []searchconsole.DataWarning      `json:"warnings,omitempty"`
+	Intents    []IntentGroup                    `json:"intents"`
+	QueriedAt  time.Time                        `json:"queriedAt"`
+}
+
+// RollupIntents classifies every query of resp, which must have the single
+// dimension query, and totals each intent, listing its topQueries queries
+// with the most clicks.
+func RollupIntents(resp *searchconsole.SearchAnalyticsResponse, classifier *IntentClassifier, topQueries int) (*IntentRollupResult, error) {
+	if !slices.Equal(resp.Dimensions, []string{"query"}) {
+		return nil, errors.New("intent rollups require the single dimension query")
+	}
+	if topQueries < 0 {
+		return nil, fmt.Errorf("invalid top_queries %d: must not be negative", topQueries)
+	}
+	byIntent := map[string][]searchconsole.SearchAnalyticsRow{}
+	for _, row := range resp.Rows {
+		if len(row.Keys) == 0 {
+			continue
+		}
+		row.Intent = classifier.Classify(row.Keys[0])
+		byIntent[row.Intent] = append(byIntent[row.Intent], row)
+	}
+
+	totals := Totals(resp.Rows)
+	result := &IntentRollupResult{
+		SiteURL:    resp.SiteURL,
+		StartDate:  resp.StartDate,
+		EndDate:    resp.EndDate,
+		SearchType: resp.SearchType,
+		QueryCount: len(resp.Rows),
+		Totals:     totals,
+		Truncated:  resp.Truncated,
+		Warnings:   mergeWarnings(resp),
+		QueriedAt:  resp.QueriedAt,
+	}
+	for _, intent := range Intents {
+		rows := byIntent[intent]
+		total := Totals(rows)
+		group := IntentGroup{
+			Intent:      intent,
+			QueryCount:  len(rows),
+			Clicks:      total.Clicks,
+			Impressions: total.Impressions,
+			CTR:         total.CTR,
+			Position:    total.Position,
+		}
+		if totals.Clicks > 0 {
+			group.ClickShare = total.Clicks / totals.Clicks
+		}
+		if totals.Impressions > 0 {
+			group.ImpressionShare = total.Impressions / totals.Impressions
+		}
+		slices.SortStableFunc(rows, func(a, b searchconsole.SearchAnalyticsRow) int {
+			return cmp.Or(cmp.Compare(b.Clicks, a.Clicks), cmp.Compare(b.Impressions, a.Impressions))
+		})
+		group.TopQueries = firstN(rows, topQueries)
+		result.Intents = append(result.Intents, group)
+	}
+	return result, nil
+}
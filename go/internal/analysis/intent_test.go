@@ -0,0 +1,168 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func defaultClassifier(t *testing.T) *analysis.IntentClassifier {
+	t.Helper()
+	brand, err := analysis.NewBrandMatcher([]string{"dev leader"}, nil)
+	if err != nil {
+		t.Fatalf("NewBrandMatcher: %v", err)
+	}
+	classifier, err := analysis.NewIntentClassifier(analysis.DefaultIntentRules(), brand)
+	if err != nil {
+		t.Fatalf("NewIntentClassifier: %v", err)
+	}
+	return classifier
+}
+
+func TestIntentClassifier_Classify_AppliesPrecedence(t *testing.T) {
+	t.Parallel()
+
+	classifier := defaultClassifier(t)
+	for query, want := range map[string]string{
+		"devleader blazor course":             analysis.IntentNavigational,
+		"github login":                        analysis.IntentNavigational,
+		"buy best blazor course":              analysis.IntentTransactional,
+		"blazor component library free trial": analysis.IntentTransactional,
+		"best blazor component library":       analysis.IntentCommercial,
+		"how to compare blazor and react":     analysis.IntentCommercial,
+		"blazor vs react":                     analysis.IntentCommercial,
+		"How do I use blazor forms":           analysis.IntentInformational,
+		"blazor forms tutorial":               analysis.IntentInformational,
+		"blazor forms":                        analysis.IntentUnclassified,
+		// Keywords match whole words only.
+		"bestseller free trials":  analysis.IntentUnclassified,
+		"c# top-level statements": analysis.IntentCommercial,
+	} {
+		if got := classifier.Classify(query); got != want {
+			t.Errorf("Classify(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestIntentClassifier_CustomRules(t *testing.T) {
+	t.Parallel()
+
+	rules := analysis.IntentRules{Commercial: []string{"course"}, QuestionWords: []string{"wat"}}
+	classifier, err := analysis.NewIntentClassifier(rules, nil)
+	if err != nil {
+		t.Fatalf("NewIntentClassifier: %v", err)
+	}
+	for query, want := range map[string]string{
+		"blazor course":   analysis.IntentCommercial,
+		"wat is blazor":   analysis.IntentInformational,
+		"how is blazor":   analysis.IntentUnclassified,
+		"buy blazor book": analysis.IntentUnclassified,
+	} {
+		if got := classifier.Classify(query); got != want {
+			t.Errorf("Classify(%q) = %q, want %q", query, got, want)
+		}
+	}
+
+	extended := analysis.DefaultIntentRules().Extend(rules)
+	if len(extended.Commercial) != len(analysis.DefaultIntentRules().Commercial)+1 {
+		t.Errorf("extended commercial = %v, want the defaults plus course", extended.Commercial)
+	}
+}
+
+func TestNewIntentClassifier_RejectsUnusableRules(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		rules analysis.IntentRules
+		want  string
+	}{
+		{analysis.IntentRules{}, "at least one intent keyword"},
+		{analysis.IntentRules{QuestionWords: []string{"how come"}}, `invalid question word "how come"`},
+	} {
+		if _, err := analysis.NewIntentClassifier(tc.rules, nil); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("NewIntentClassifier(%+v) err = %v, want %q", tc.rules, err, tc.want)
+		}
+	}
+}
+
+func TestClassifyIntents_SetsRowIntentsAndTotals(t *testing.T) {
+	t.Parallel()
+
+	resp := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"page", "query"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"/a", "how to use blazor"}, 10, 100, 4),
+			row([]string{"/a", "blazor forms"}, 1, 50, 9),
+			row([]string{"/b", "blazor guide"}, 5, 300, 2),
+			row([]string{"/c", "buy blazor book"}, 2, 20, 5),
+		},
+	}
+	if err := analysis.ClassifyIntents(resp, defaultClassifier(t)); err != nil {
+		t.Fatalf("ClassifyIntents: %v", err)
+	}
+	var intents []string
+	for _, r := range resp.Rows {
+		intents = append(intents, r.Intent)
+	}
+	if strings.Join(intents, ",") != "informational,unclassified,informational,transactional" {
+		t.Errorf("row intents = %v", intents)
+	}
+	if len(resp.Intents) != 3 || resp.Intents[0].Segment != "informational" || resp.Intents[0].RowCount != 2 ||
+		resp.Intents[0].Clicks != 15 || !approxEqual(resp.Intents[0].Position, 2.5) ||
+		resp.Intents[1].Segment != "transactional" || resp.Intents[2].Segment != "unclassified" {
+		t.Errorf("intents = %+v", resp.Intents)
+	}
+
+	if err := analysis.ClassifyIntents(&searchconsole.SearchAnalyticsResponse{Dimensions: []string{"page"}}, defaultClassifier(t)); err == nil {
+		t.Error("ClassifyIntents without the query dimension returned nil error")
+	}
+}
+
+func TestRollupIntents_TotalsEveryIntent(t *testing.T) {
+	t.Parallel()
+
+	resp := &searchconsole.SearchAnalyticsResponse{
+		SiteURL:    "sc-domain:devleader.ca",
+		Dimensions: []string{"query"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"how to use blazor"}, 10, 100, 4),
+			row([]string{"blazor guide"}, 30, 300, 2),
+			row([]string{"what is blazor"}, 20, 400, 3),
+			row([]string{"dev leader"}, 40, 100, 1),
+			row([]string{"blazor forms"}, 0, 100, 20),
+		},
+	}
+	got, err := analysis.RollupIntents(resp, defaultClassifier(t), 2)
+	if err != nil {
+		t.Fatalf("RollupIntents: %v", err)
+	}
+	if got.QueryCount != 5 || got.Totals.Clicks != 100 || len(got.Intents) != 5 {
+		t.Fatalf("result = %+v", got)
+	}
+	informational := got.Intents[0]
+	if informational.Intent != "informational" || informational.QueryCount != 3 || informational.Clicks != 60 ||
+		!approxEqual(informational.ClickShare, 0.6) || !approxEqual(informational.ImpressionShare, 0.8) ||
+		!approxEqual(informational.CTR, 0.075) {
+		t.Errorf("informational = %+v", informational)
+	}
+	if len(informational.TopQueries) != 2 || informational.TopQueries[0].Keys[0] != "blazor guide" ||
+		informational.TopQueries[0].Intent != "informational" {
+		t.Errorf("topQueries = %+v, want blazor guide first of 2", informational.TopQueries)
+	}
+	if navigational := got.Intents[1]; navigational.Intent != "navigational" || navigational.Clicks != 40 {
+		t.Errorf("navigational = %+v", navigational)
+	}
+	if commercial := got.Intents[2]; commercial.QueryCount != 0 || commercial.TopQueries == nil {
+		t.Errorf("commercial = %+v, want an empty group", commercial)
+	}
+	if unclassified := got.Intents[4]; unclassified.Intent != "unclassified" || unclassified.QueryCount != 1 {
+		t.Errorf("unclassified = %+v", unclassified)
+	}
+
+	resp.Dimensions = []string{"page"}
+	if _, err := analysis.RollupIntents(resp, defaultClassifier(t), 2); err == nil {
+		t.Error("RollupIntents with the page dimension returned nil error")
+	}
+}
@@ -0,0 +1,61 @@
+// Package analysis derives higher-level insights from Search Console search
+// analytics rows. Its functions are pure: callers fetch rows with the
+// searchconsole client and pass them in.
+package analysis
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// keySeparator joins dimension keys into a map key. It cannot occur in a
+// query, URL, or any other Search Console dimension value.
+const keySeparator = "\x00"
+
+// Totals aggregates rows the way Search Console does: clicks and impressions
+// are summed, CTR is recomputed from the sums, and position is averaged
+// weighted by impressions rather than naively across rows.
+func Totals(rows []searchconsole.SearchAnalyticsRow) searchconsole.SearchAnalyticsRow {
+	var total searchconsole.SearchAnalyticsRow
+	var weightedPosition float64
+	for _, row := range rows {
+		total.Clicks += row.Clicks
+		total.Impressions += row.Impressions
+		weightedPosition += row.Position * row.Impressions
+	}
+	if total.Impressions > 0 {
+		total.CTR = total.Clicks / total.Impressions
+		total.Position = weightedPosition / total.Impressions
+	}
+	return total
+}
+
+// mergeWarnings returns the distinct data warnings of responses, in order, so
+// a result derived from several queries carries each caveat once.
+func mergeWarnings(responses ...*searchconsole.SearchAnalyticsResponse) []searchconsole.DataWarning {
+	var warnings []searchconsole.DataWarning
+	for _, resp := range responses {
+		for _, warning := range resp.Warnings {
+			if !slices.Contains(warnings, warning) {
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+	return warnings
+}
+
+func joinKeys(keys []string) string {
+	return strings.Join(keys, keySeparator)
+}
+
+// percentChange returns the relative change from previous to current, or nil
+// when previous is zero and the change has no finite percentage.
+func percentChange(previous, current float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	p := (current - previous) / previous * 100
+	return &p
+}
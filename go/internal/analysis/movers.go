@@ -0,0 +1,174 @@
+package analysis
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// Metrics TopMovers can rank by.
+const (
+	MoverMetricClicks      = "clicks"
+	MoverMetricImpressions = "impressions"
+	MoverMetricPosition    = "position"
+)
+
+// MoversResult is the result of ranking the rows of a period comparison by how
+// much one metric moved. CandidateCount is how many rows passed the
+// minimum-impressions threshold before winners and losers were split off.
+type MoversResult struct {
+	SiteURL        string                      `json:"siteUrl"`
+	Comparison     string                      `json:"comparison"`
+	CurrentPeriod  Period                      `json:"currentPeriod"`
+	PreviousPeriod Period                      `json:"previousPeriod"`
+	Dimension      string                      `json:"dimension"`
+	SearchType     string                      `json:"searchType"`
+	Metric         string                      `json:"metric"`
+	MinImpressions float64                     `json:"minImpressions"`
+	Totals         RowComparison               `json:"totals"`
+	CandidateCount int                         `json:"candidateCount"`
+	Truncated      bool                        `json:"truncated"`
+	Warnings       []searchconsole.DataWarning `json:"warnings,omitempty"`
+	Winners        []RowComparison             `json:"winners"`
+	Losers         []RowComparison             `json:"losers"`
+	QueriedAt      time.Time                   `json:"queriedAt"`
+}
+
+// MoverOptions selects how TopMovers ranks rows: by Metric (clicks, the
+// default when empty, impressions, or position), considering only rows with
+// at least MinImpressions in either period, and returning at most Limit
+// winners and Limit losers.
+type MoverOptions struct {
+	Metric         string
+	MinImpressions float64
+	Limit          int
+}
+
+// Validate reports whether o can be used, so callers can reject it before
+// fetching any rows.
+func (o MoverOptions) Validate() error {
+	if o.Metric != "" {
+		if _, err := moverGain(o.Metric); err != nil {
+			return err
+		}
+	}
+	if o.MinImpressions < 0 {
+		return fmt.Errorf("invalid min_impressions %v: must not be negative", o.MinImpressions)
+	}
+	if o.Limit <= 0 {
+		return fmt.Errorf("invalid limit %d: must be positive", o.Limit)
+	}
+	return nil
+}
+
+// TopMovers compares current and previous, which must share a single
+// dimension, and returns the options' winners and losers. currentTotals and
+// previousTotals are the periods' totals, as for ComparePeriods.
+//
+// A row is a candidate when its impressions reach MinImpressions in either
+// period, so a row that vanished is still reported as a loser. For clicks and
+// impressions, winners gained and losers dropped; for position, only rows
+// ranking in both periods are candidates, and winners moved up (a negative
+// position change).
+func TopMovers(
+	current, previous *searchconsole.SearchAnalyticsResponse,
+	currentTotals, previousTotals searchconsole.SearchAnalyticsRow,
+	comparison string,
+	options MoverOptions,
+) (*MoversResult, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	metric, minImpressions, limit := options.Metric, options.MinImpressions, options.Limit
+	if metric == "" {
+		metric = MoverMetricClicks
+	}
+	gain, err := moverGain(metric)
+	if err != nil {
+		return nil, err
+	}
+
+	var winners, losers []RowComparison
+	candidates := 0
+	for _, row := range CompareRows(current.Rows, previous.Rows) {
+		if max(row.Impressions.Current, row.Impressions.Previous) < minImpressions {
+			continue
+		}
+		g, ok := gain(row)
+		if !ok {
+			continue
+		}
+		candidates++
+		switch {
+		case g > 0:
+			winners = append(winners, row)
+		case g < 0:
+			losers = append(losers, row)
+		}
+	}
+
+	byGain := func(a, b RowComparison) int {
+		ga, _ := gain(a)
+		gb, _ := gain(b)
+		return cmp.Compare(gb, ga)
+	}
+	slices.SortStableFunc(winners, byGain)
+	slices.SortStableFunc(losers, func(a, b RowComparison) int { return byGain(b, a) })
+
+	dimension := ""
+	if len(current.Dimensions) > 0 {
+		dimension = current.Dimensions[0]
+	}
+	return &MoversResult{
+		SiteURL:        current.SiteURL,
+		Comparison:     comparison,
+		CurrentPeriod:  Period{StartDate: current.StartDate, EndDate: current.EndDate},
+		PreviousPeriod: Period{StartDate: previous.StartDate, EndDate: previous.EndDate},
+		Dimension:      dimension,
+		SearchType:     current.SearchType,
+		Metric:         metric,
+		MinImpressions: minImpressions,
+		Totals:         compareRow(nil, currentTotals, previousTotals, true, true),
+		CandidateCount: candidates,
+		Truncated:      current.Truncated || previous.Truncated,
+		Warnings:       mergeWarnings(current, previous),
+		Winners:        firstN(winners, limit),
+		Losers:         firstN(losers, limit),
+		QueriedAt:      current.QueriedAt,
+	}, nil
+}
+
+// moverGain returns a function reporting how much a row improved by metric,
+// positive meaning better, and whether the row can be ranked by it at all.
+func moverGain(metric string) (func(RowComparison) (float64, bool), error) {
+	switch metric {
+	case MoverMetricClicks:
+		return func(r RowComparison) (float64, bool) { return r.Clicks.Change, true }, nil
+	case MoverMetricImpressions:
+		return func(r RowComparison) (float64, bool) { return r.Impressions.Change, true }, nil
+	case MoverMetricPosition:
+		return func(r RowComparison) (float64, bool) {
+			if r.Position.Change == nil {
+				return 0, false
+			}
+			return -*r.Position.Change, true
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid metric %q: must be one of clicks, impressions, position", metric)
+	}
+}
+
+// firstN returns at most n leading elements of s, never nil so it marshals as
+// an empty JSON array.
+func firstN[T any](s []T, n int) []T {
+	if len(s) > n {
+		s = s[:n]
+	}
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,124 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func moverResponses() (current, previous *searchconsole.SearchAnalyticsResponse) {
+	current = &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"query"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"up big"}, 50, 500, 2),
+			row([]string{"up small"}, 12, 300, 6),
+			row([]string{"down"}, 5, 400, 9),
+			row([]string{"noise"}, 3, 4, 1),
+			row([]string{"new"}, 8, 80, 7),
+		},
+	}
+	previous = &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"query"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"up big"}, 10, 400, 5),
+			row([]string{"up small"}, 10, 300, 4),
+			row([]string{"down"}, 25, 450, 3),
+			row([]string{"noise"}, 0, 5, 20),
+			row([]string{"gone"}, 30, 200, 2),
+		},
+	}
+	return current, previous
+}
+
+func TestTopMovers_Clicks_SplitsWinnersAndLosersAboveThreshold(t *testing.T) {
+	t.Parallel()
+
+	current, previous := moverResponses()
+	got, err := analysis.TopMovers(
+		current, previous, analysis.Totals(current.Rows), analysis.Totals(previous.Rows), "previous_period", analysis.MoverOptions{MinImpressions: 50, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Metric != "clicks" || got.Dimension != "query" {
+		t.Errorf("metric/dimension = %q/%q, want clicks/query", got.Metric, got.Dimension)
+	}
+	// "noise" never reached 50 impressions, so it is not a candidate.
+	if got.CandidateCount != 5 {
+		t.Errorf("candidateCount = %d, want 5", got.CandidateCount)
+	}
+	if keysOf(got.Winners) != "up big,new,up small" {
+		t.Errorf("winners = %s, want up big,new,up small", keysOf(got.Winners))
+	}
+	if keysOf(got.Losers) != "gone,down" {
+		t.Errorf("losers = %s, want gone,down", keysOf(got.Losers))
+	}
+}
+
+func TestTopMovers_Position_RanksOnlyRowsInBothPeriods(t *testing.T) {
+	t.Parallel()
+
+	current, previous := moverResponses()
+	got, err := analysis.TopMovers(
+		current, previous, analysis.Totals(current.Rows), analysis.Totals(previous.Rows), "previous_period", analysis.MoverOptions{Metric: "position", Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// noise moved 20 -> 1, the largest improvement; down fell 3 -> 9.
+	if keysOf(got.Winners) != "noise" {
+		t.Errorf("winners = %s, want noise", keysOf(got.Winners))
+	}
+	if keysOf(got.Losers) != "down" {
+		t.Errorf("losers = %s, want down", keysOf(got.Losers))
+	}
+	if got.CandidateCount != 4 {
+		t.Errorf("candidateCount = %d, want 4 (new and gone have no position change)", got.CandidateCount)
+	}
+}
+
+func TestTopMovers_NoMovers_ReturnsEmptyLists(t *testing.T) {
+	t.Parallel()
+
+	empty := &searchconsole.SearchAnalyticsResponse{Dimensions: []string{"page"}}
+	got, err := analysis.TopMovers(
+		empty, empty, analysis.Totals(empty.Rows), analysis.Totals(empty.Rows), "year_over_year", analysis.MoverOptions{Metric: "impressions", Limit: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Winners == nil || got.Losers == nil {
+		t.Errorf("winners/losers = %v/%v, want empty non-nil slices", got.Winners, got.Losers)
+	}
+}
+
+func TestTopMovers_InvalidInput_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	current, previous := moverResponses()
+	for _, tt := range []struct {
+		metric         string
+		minImpressions float64
+		limit          int
+		marker         string
+	}{
+		{metric: "ctr", limit: 10, marker: `invalid metric "ctr"`},
+		{minImpressions: -1, limit: 10, marker: "invalid min_impressions"},
+		{limit: 0, marker: "invalid limit 0"},
+	} {
+		_, err := analysis.TopMovers(
+			current, previous, analysis.Totals(current.Rows), analysis.Totals(previous.Rows), "previous_period", analysis.MoverOptions{Metric: tt.metric, MinImpressions: tt.minImpressions, Limit: tt.limit})
+		if err == nil || !strings.Contains(err.Error(), tt.marker) {
+			t.Errorf("error = %v, want it to contain %q", err, tt.marker)
+		}
+	}
+}
+
+func keysOf(rows []analysis.RowComparison) string {
+	keys := make([]string, len(rows))
+	for i, r := range rows {
+		keys[i] = strings.Join(r.Keys, "|")
+	}
+	return strings.Join(keys, ",")
+}
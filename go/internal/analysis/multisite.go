@@ -0,0 +1,123 @@
+package analysis
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// SiteTotals is one property's line in a multi-site totals table. RowCount
+// and Truncated describe its returned rows; the metrics are the property's
+// totals for the whole query. CoveredBy names the domain property in the same
+// query that already includes this URL-prefix property's traffic.
+type SiteTotals struct {
+	SiteURL     string  `json:"siteUrl"`
+	RowCount    int     `json:"rowCount"`
+	Clicks      float64 `json:"clicks"`
+	Impressions float64 `json:"impressions"`
+	CTR         float64 `json:"ctr"`
+	Position    float64 `json:"position"`
+	Truncated   bool    `json:"truncated"`
+	CoveredBy   string  `json:"coveredBy,omitempty"`
+}
+
+// SiteResult is one property's outcome in a multi-site query. Exactly one
+// of Error and Response is set.
+type SiteResult struct {
+	SiteURL  string                                 `json:"siteUrl"`
+	Error    string                                 `json:"error,omitempty"`
+	Response *searchconsole.SearchAnalyticsResponse `json:"response,omitempty"`
+}
+
+// MultiSiteResult is the result of CombineSites. Totals has a line for every
+// property that succeeded, ordered by clicks, and Combined aggregates them,
+// except for the URL-prefix properties a domain property covers, which would
+// otherwise be counted twice.
+type MultiSiteResult struct {
+	StartDate      string                           `json:"startDate"`
+	EndDate        string                           `json:"endDate"`
+	Dimensions     []string                         `json:"dimensions,omitempty"`
+	SearchType     string                           `json:"searchType"`
+	SiteCount      int                              `json:"siteCount"`
+	SucceededCount int                              `json:"succeededCount"`
+	FailedCount    int                              `json:"failedCount"`
+	Combined       searchconsole.SearchAnalyticsRow `json:"combined"`
+	Totals         []SiteTotals                     `json:"totals"`
+	Sites          []SiteResult                     `json:"sites"`
+	QueriedAt      time.Time                        `json:"queriedAt"`
+}
+
+// CombineSites builds the totals table for the per-property results of one
+// query. Each property's totals come from its TotalsResponse when the query
+// had dimensions, and from its single dimensionless row otherwise, so they
+// never depend on the row limit. With keepRows false, per-site responses are
+// returned without their rows.
+func CombineSites(results []searchconsole.SiteQueryResult, keepRows bool) *MultiSiteResult {
+	combined := &MultiSiteResult{
+		SiteCount: len(results),
+		Totals:    []SiteTotals{},
+		Sites:     make([]SiteResult, len(results)),
+	}
+	var domainProperties []string
+	for _, result := range results {
+		if result.Err == nil && strings.HasPrefix(result.Response.SiteURL, "sc-domain:") {
+			domainProperties = append(domainProperties, result.Response.SiteURL)
+		}
+	}
+	var siteRows []searchconsole.SearchAnalyticsRow
+	for i, result := range results {
+		combined.Sites[i].SiteURL = result.SiteURL
+		if result.Err != nil {
+			combined.Sites[i].Error = result.Err.Error()
+			combined.FailedCount++
+			continue
+		}
+		resp := result.Response
+		combined.SucceededCount++
+		if combined.SucceededCount == 1 {
+			combined.StartDate = resp.StartDate
+			combined.EndDate = resp.EndDate
+			combined.Dimensions = resp.Dimensions
+			combined.SearchType = resp.SearchType
+			combined.QueriedAt = resp.QueriedAt
+		}
+		total := Totals(resp.Rows)
+		if result.TotalsResponse != nil {
+			total = Totals(result.TotalsResponse.Rows)
+		}
+		coveredBy := ""
+		for _, domainProperty := range domainProperties {
+			if searchconsole.CoveredByDomainProperty(resp.SiteURL, domainProperty) {
+				coveredBy = domainProperty
+				break
+			}
+		}
+		if coveredBy == "" {
+			siteRows = append(siteRows, total)
+		}
+		combined.Totals = append(combined.Totals, SiteTotals{
+			SiteURL:     result.SiteURL,
+			RowCount:    resp.RowCount,
+			Clicks:      total.Clicks,
+			Impressions: total.Impressions,
+			CTR:         total.CTR,
+			Position:    total.Position,
+			Truncated:   resp.Truncated,
+			CoveredBy:   coveredBy,
+		})
+		if !keepRows {
+			trimmed := *resp
+			trimmed.Rows = []searchconsole.SearchAnalyticsRow{}
+			resp = &trimmed
+		}
+		combined.Sites[i].Response = resp
+	}
+	slices.SortStableFunc(combined.Totals, func(a, b SiteTotals) int {
+		return cmp.Or(cmp.Compare(b.Clicks, a.Clicks), cmp.Compare(b.Impressions, a.Impressions))
+	})
+	combined.Combined = Totals(siteRows)
+	return combined
+}
@@ -0,0 +1,100 @@
+package analysis_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestCombineSites_BuildsTotalsTableAndKeepsErrors(t *testing.T) {
+	t.Parallel()
+
+	response := func(rows ...searchconsole.SearchAnalyticsRow) *searchconsole.SearchAnalyticsResponse {
+		return &searchconsole.SearchAnalyticsResponse{
+			StartDate: "2026-02-01", EndDate: "2026-02-28", SearchType: "web", RowCount: len(rows), Rows: rows,
+		}
+	}
+	results := []searchconsole.SiteQueryResult{
+		{SiteURL: "a.example", Response: response(row(nil, 10, 100, 2))},
+		{SiteURL: "broken.example", Err: errors.New("API error (status 500)")},
+		{SiteURL: "b.example", Response: response(row([]string{"x"}, 30, 100, 4), row([]string{"y"}, 10, 300, 8))},
+	}
+
+	got := analysis.CombineSites(results, false)
+	if got.SiteCount != 3 || got.SucceededCount != 2 || got.FailedCount != 1 || got.StartDate != "2026-02-01" {
+		t.Errorf("result = %+v", got)
+	}
+	if len(got.Totals) != 2 || got.Totals[0].SiteURL != "b.example" || got.Totals[1].SiteURL != "a.example" {
+		t.Fatalf("totals = %+v, want b.example then a.example", got.Totals)
+	}
+	b := got.Totals[0]
+	if b.Clicks != 40 || b.Impressions != 400 || b.RowCount != 2 || !approxEqual(b.CTR, 0.1) || !approxEqual(b.Position, 7) {
+		t.Errorf("b.example totals = %+v", b)
+	}
+	if c := got.Combined; c.Clicks != 50 || c.Impressions != 500 || !approxEqual(c.Position, (7*400+2*100)/500.0) {
+		t.Errorf("combined = %+v", c)
+	}
+	if got.Sites[1].Error != "API error (status 500)" || got.Sites[1].Response != nil {
+		t.Errorf("sites[1] = %+v, want the error", got.Sites[1])
+	}
+	if resp := got.Sites[2].Response; resp == nil || len(resp.Rows) != 0 || resp.RowCount != 2 {
+		t.Errorf("sites[2].Response = %+v, want rows dropped and rowCount kept", resp)
+	}
+	if len(results[2].Response.Rows) != 2 {
+		t.Errorf("input rows were modified")
+	}
+}
+
+func TestCombineSites_LeavesCoveredURLPrefixPropertiesOutOfCombined(t *testing.T) {
+	t.Parallel()
+
+	response := func(siteURL string, clicks float64) *searchconsole.SearchAnalyticsResponse {
+		return &searchconsole.SearchAnalyticsResponse{
+			SiteURL: siteURL, RowCount: 1, Rows: []searchconsole.SearchAnalyticsRow{row(nil, clicks, clicks*10, 5)},
+		}
+	}
+	results := []searchconsole.SiteQueryResult{
+		{SiteURL: "https://www.devleader.ca/", Response: response("https://www.devleader.ca/", 80)},
+		{SiteURL: "sc-domain:devleader.ca", Response: response("sc-domain:devleader.ca", 100)},
+		{SiteURL: "https://other.example/", Response: response("https://other.example/", 5)},
+	}
+
+	got := analysis.CombineSites(results, true)
+
+	if got.Combined.Clicks != 105 {
+		t.Errorf("combined clicks = %v, want 105 (the covered property left out)", got.Combined.Clicks)
+	}
+	coveredBy := map[string]string{}
+	for _, line := range got.Totals {
+		coveredBy[line.SiteURL] = line.CoveredBy
+	}
+	if coveredBy["https://www.devleader.ca/"] != "sc-domain:devleader.ca" || coveredBy["https://other.example/"] != "" {
+		t.Errorf("coveredBy = %v, want only www.devleader.ca covered by sc-domain:devleader.ca", coveredBy)
+	}
+}
+
+func TestCombineSites_TakesTotalsFromTheDimensionlessResponse(t *testing.T) {
+	t.Parallel()
+
+	results := []searchconsole.SiteQueryResult{{
+		SiteURL: "a.example",
+		Response: &searchconsole.SearchAnalyticsResponse{
+			RowCount: 1, Truncated: true, Rows: []searchconsole.SearchAnalyticsRow{row([]string{"x"}, 10, 100, 2)},
+		},
+		TotalsResponse: &searchconsole.SearchAnalyticsResponse{
+			RowCount: 1, Rows: []searchconsole.SearchAnalyticsRow{row(nil, 25, 400, 6)},
+		},
+	}}
+
+	got := analysis.CombineSites(results, true)
+
+	line := got.Totals[0]
+	if line.Clicks != 25 || line.Impressions != 400 || line.RowCount != 1 || !line.Truncated {
+		t.Errorf("totals = %+v, want 25 clicks from the totals response, with the rows' count and truncation", line)
+	}
+	if got.Combined.Clicks != 25 || len(got.Sites[0].Response.Rows) != 1 {
+		t.Errorf("combined = %+v, sites[0] = %+v; want the totals combined and the dimensioned rows kept", got.Combined, got.Sites[0])
+	}
+}
@@ -0,0 +1,225 @@
+package analysis
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// OtherPageGroup is the group that collects pages matching no pattern.
+const OtherPageGroup = "(other)"
+
+// maxPageGroupDepth is the deepest directory level GroupPages groups by.
+const maxPageGroupDepth = 10
+
+// topPagesPerGroup is how many example pages each group lists.
+const topPagesPerGroup = 5
+
+// PageGroupRule names a site section by a URL path pattern, in which "*"
+// matches any run of characters, including "/". A pattern ending in "/*" also
+// matches the directory without its trailing slash.
+type PageGroupRule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+// PageGroup is the rolled-up performance of one site section. Clicks and
+// impressions are summed, CTR is recomputed from the sums, and Position is
+// weighted by impressions.
+type PageGroup struct {
+	Name        string   `json:"name"`
+	Pattern     string   `json:"pattern,omitempty"`
+	PageCount   int      `json:"pageCount"`
+	Clicks      float64  `json:"clicks"`
+	Impressions float64  `json:"impressions"`
+	CTR         float64  `json:"ctr"`
+	Position    float64  `json:"position"`
+	TopPages    []string `json:"topPages"`
+}
+
+// PageGroupResult is the result of GroupPages. Exactly one of Rules and
+// Depth is set, depending on how pages were grouped.
+type PageGroupResult struct {
+	SiteURL    string                           `json:"siteUrl"`
+	StartDate  string                           `json:"startDate"`
+	EndDate    string                           `json:"endDate"`
+	SearchType string                           `json:"searchType"`
+	Rules      []PageGroupRule                  `json:"rules,omitempty"`
+	Depth      int                              `json:"depth,omitempty"`
+	GroupCount int                              `json:"groupCount"`
+	Totals     searchconsole.SearchAnalyticsRow `json:"totals"`
+	Truncated  bool                             `json:"truncated"`
+	Warnings   []searchconsole.DataWarning      `json:"warnings,omitempty"`
+	Groups     []PageGroup                      `json:"groups"`
+	QueriedAt  time.Time                        `json:"queriedAt"`
+}
+
+// PageGroupOptions controls GroupPages. With Rules, each page joins the first
+// rule its path matches, or OtherPageGroup; otherwise pages are grouped by
+// their first Depth path segments. At most Limit groups are returned.
+type PageGroupOptions struct {
+	Rules []PageGroupRule
+	Depth int
+	Limit int
+}
+
+// Validate reports whether o can be used, so callers can reject it before
+// fetching any rows.
+func (o PageGroupOptions) Validate() error {
+	if _, err := compilePageGroupRules(o.Rules); err != nil {
+		return err
+	}
+	switch {
+	case len(o.Rules) > 0 && o.Depth != 0:
+		return errors.New("pass either page group patterns or depth, not both")
+	case len(o.Rules) == 0 && (o.Depth < 1 || o.Depth > maxPageGroupDepth):
+		return fmt.Errorf("invalid depth %d: must be between 1 and %d", o.Depth, maxPageGroupDepth)
+	case o.Limit <= 0:
+		return fmt.Errorf("invalid limit %d: must be positive", o.Limit)
+	}
+	return nil
+}
+
+// GroupPages rolls resp's rows, which must have the single dimension page, up
+// into site sections. Groups are ordered by clicks, then impressions.
+func GroupPages(resp *searchconsole.SearchAnalyticsResponse, options PageGroupOptions) (*PageGroupResult, error) {
+	if !slices.Equal(resp.Dimensions, []string{"page"}) {
+		return nil, errors.New("page grouping requires the single dimension page")
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	patterns, _ := compilePageGroupRules(options.Rules)
+
+	type pageGroup struct {
+		pattern string
+		rows    []searchconsole.SearchAnalyticsRow
+	}
+	groups := make(map[string]*pageGroup)
+	for _, row := range resp.Rows {
+		if len(row.Keys) != 1 {
+			continue
+		}
+		path := pagePath(row.Keys[0])
+		name, pattern := OtherPageGroup, ""
+		if len(options.Rules) == 0 {
+			name = directoryGroup(path, options.Depth)
+		} else {
+			for i, re := range patterns {
+				if re.MatchString(path) {
+					name, pattern = options.Rules[i].Name, options.Rules[i].Pattern
+					break
+				}
+			}
+		}
+		group, ok := groups[name]
+		if !ok {
+			group = &pageGroup{pattern: pattern}
+			groups[name] = group
+		}
+		group.rows = append(group.rows, row)
+	}
+
+	result := make([]PageGroup, 0, len(groups))
+	for name, group := range groups {
+		total := Totals(group.rows)
+		rows := slices.Clone(group.rows)
+		slices.SortStableFunc(rows, func(a, b searchconsole.SearchAnalyticsRow) int {
+			return cmp.Or(cmp.Compare(b.Clicks, a.Clicks), cmp.Compare(b.Impressions, a.Impressions))
+		})
+		topPages := make([]string, 0, topPagesPerGroup)
+		for _, row := range firstN(rows, topPagesPerGroup) {
+			topPages = append(topPages, row.Keys[0])
+		}
+		result = append(result, PageGroup{
+			Name:        name,
+			Pattern:     group.pattern,
+			PageCount:   len(group.rows),
+			Clicks:      total.Clicks,
+			Impressions: total.Impressions,
+			CTR:         total.CTR,
+			Position:    total.Position,
+			TopPages:    topPages,
+		})
+	}
+	slices.SortFunc(result, func(a, b PageGroup) int {
+		return cmp.Or(cmp.Compare(b.Clicks, a.Clicks), cmp.Compare(b.Impressions, a.Impressions), cmp.Compare(a.Name, b.Name))
+	})
+
+	return &PageGroupResult{
+		SiteURL:    resp.SiteURL,
+		StartDate:  resp.StartDate,
+		EndDate:    resp.EndDate,
+		SearchType: resp.SearchType,
+		Rules:      options.Rules,
+		Depth:      options.Depth,
+		GroupCount: len(result),
+		Totals:     Totals(resp.Rows),
+		Truncated:  resp.Truncated,
+		Warnings:   mergeWarnings(resp),
+		Groups:     firstN(result, options.Limit),
+		QueriedAt:  resp.QueriedAt,
+	}, nil
+}
+
+// compilePageGroupRules turns each rule's pattern into an anchored regular
+// expression.
+func compilePageGroupRules(rules []PageGroupRule) ([]*regexp.Regexp, error) {
+	names := make(map[string]bool, len(rules))
+	patterns := make([]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		if strings.TrimSpace(rule.Name) == "" {
+			return nil, fmt.Errorf("page group %d has no name", i+1)
+		}
+		if names[rule.Name] || rule.Name == OtherPageGroup {
+			return nil, fmt.Errorf("duplicate page group name %q", rule.Name)
+		}
+		names[rule.Name] = true
+		if !strings.HasPrefix(rule.Pattern, "/") {
+			return nil, fmt.Errorf("invalid pattern %q for page group %q: must start with /", rule.Pattern, rule.Name)
+		}
+		// A trailing "/*" also matches the bare directory, so "/blog/*"
+		// covers the index page "/blog" as well as "/blog/" and its children.
+		pattern, suffix := rule.Pattern, ""
+		if trimmed, ok := strings.CutSuffix(pattern, "/*"); ok {
+			pattern, suffix = trimmed, "(?:/.*)?"
+		}
+		parts := strings.Split(pattern, "*")
+		for j, part := range parts {
+			parts[j] = regexp.QuoteMeta(part)
+		}
+		patterns[i] = regexp.MustCompile("^" + strings.Join(parts, ".*") + suffix + "$")
+	}
+	return patterns, nil
+}
+
+// pagePath returns the path of a page URL, or "/" for a bare host.
+func pagePath(page string) string {
+	u, err := url.Parse(page)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+// directoryGroup names the section of path at depth: its first depth segments
+// followed by "/*", so at depth 1 "/blog", "/blog/", and "/blog/2026/post" are
+// all in "/blog/*", the group a "/blog/*" pattern would make. A page with fewer
+// segments than depth is its own group.
+func directoryGroup(path string, depth int) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if segments[0] == "" {
+		return "/"
+	}
+	if len(segments) < depth {
+		return path
+	}
+	return "/" + strings.Join(segments[:depth], "/") + "/*"
+}
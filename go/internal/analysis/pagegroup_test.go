@@ -0,0 +1,164 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func pageRows() *searchconsole.SearchAnalyticsResponse {
+	return &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"page"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"https://www.example.com/blog/"}, 10, 100, 5),
+			row([]string{"https://www.example.com/blog/2026/blazor-forms"}, 30, 300, 3),
+			row([]string{"https://www.example.com/blog/di?ref=x"}, 20, 600, 9),
+			row([]string{"https://www.example.com/docs/setup"}, 25, 250, 2),
+			row([]string{"https://www.example.com/about"}, 5, 50, 1),
+			row([]string{"https://www.example.com/"}, 40, 200, 1),
+		},
+	}
+}
+
+func TestGroupPages_ByPattern_SumsAndWeightsPosition(t *testing.T) {
+	t.Parallel()
+
+	got, err := analysis.GroupPages(pageRows(), analysis.PageGroupOptions{
+		Rules: []analysis.PageGroupRule{
+			{Name: "Blazor posts", Pattern: "/blog/*blazor*"},
+			{Name: "Blog", Pattern: "/blog/*"},
+			{Name: "Docs", Pattern: "/docs/*"},
+		},
+		Limit: 10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.GroupCount != 4 || got.Totals.Clicks != 130 {
+		t.Fatalf("groups/clicks = %d/%v, want 4/130", got.GroupCount, got.Totals.Clicks)
+	}
+	want := []struct {
+		name   string
+		pages  int
+		clicks float64
+	}{
+		{name: "(other)", pages: 2, clicks: 45},
+		// Tied on clicks, so ordered by impressions.
+		{name: "Blog", pages: 2, clicks: 30},
+		{name: "Blazor posts", pages: 1, clicks: 30},
+		{name: "Docs", pages: 1, clicks: 25},
+	}
+	for i, w := range want {
+		group := got.Groups[i]
+		if group.Name != w.name || group.PageCount != w.pages || group.Clicks != w.clicks {
+			t.Errorf("groups[%d] = %+v, want %s with %d pages and %v clicks", i, group, w.name, w.pages, w.clicks)
+		}
+	}
+	blog := got.Groups[1]
+	// (100*5 + 600*9) / 700, not the naive (5+9)/2.
+	if blog.Impressions != 700 || !approxEqual(blog.Position, 5900.0/700) || !approxEqual(blog.CTR, 30.0/700) {
+		t.Errorf("Blog = %+v, want 700 impressions and impression-weighted position", blog)
+	}
+	if blog.Pattern != "/blog/*" || blog.TopPages[0] != "https://www.example.com/blog/di?ref=x" {
+		t.Errorf("Blog pattern/top pages = %q/%v", blog.Pattern, blog.TopPages)
+	}
+}
+
+func TestGroupPages_TrailingWildcard_MatchesBareDirectory(t *testing.T) {
+	t.Parallel()
+
+	resp := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"page"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"https://www.example.com/blog"}, 10, 100, 5),
+			row([]string{"https://www.example.com/blog/post"}, 5, 50, 3),
+			row([]string{"https://www.example.com/blogroll"}, 1, 10, 8),
+		},
+	}
+	got, err := analysis.GroupPages(resp, analysis.PageGroupOptions{
+		Rules: []analysis.PageGroupRule{{Name: "Blog", Pattern: "/blog/*"}},
+		Limit: 10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Groups[0].Name != "Blog" || got.Groups[0].PageCount != 2 {
+		t.Errorf("groups[0] = %+v, want Blog with /blog and /blog/post", got.Groups[0])
+	}
+	if got.Groups[1].Name != "(other)" || got.Groups[1].PageCount != 1 {
+		t.Errorf("groups[1] = %+v, want /blogroll in (other)", got.Groups[1])
+	}
+}
+
+func TestGroupPages_ByDepth_GroupsDirectories(t *testing.T) {
+	t.Parallel()
+
+	got, err := analysis.GroupPages(pageRows(), analysis.PageGroupOptions{Depth: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := make(map[string]int)
+	for _, group := range got.Groups {
+		names[group.Name] = group.PageCount
+	}
+	if len(names) != 4 || names["/blog/*"] != 3 || names["/docs/*"] != 1 || names["/about/*"] != 1 || names["/"] != 1 {
+		t.Errorf("groups = %v, want /blog/* (3), /docs/*, /about/*, and /", names)
+	}
+
+	got, err = analysis.GroupPages(pageRows(), analysis.PageGroupOptions{Depth: 2, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Groups[1].Name != "/blog/2026/*" {
+		t.Errorf("depth 2 groups[1] = %q, want /blog/2026/*", got.Groups[1].Name)
+	}
+}
+
+func TestGroupPages_ByDepth_BareDirectoryJoinsItsGroup(t *testing.T) {
+	t.Parallel()
+
+	resp := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"page"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"https://www.example.com/blog"}, 10, 100, 5),
+			row([]string{"https://www.example.com/blog/"}, 5, 50, 3),
+			row([]string{"https://www.example.com/blog/post"}, 1, 10, 8),
+		},
+	}
+	got, err := analysis.GroupPages(resp, analysis.PageGroupOptions{Depth: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.GroupCount != 1 || got.Groups[0].Name != "/blog/*" || got.Groups[0].PageCount != 3 {
+		t.Errorf("groups = %+v, want /blog, /blog/, and /blog/post all in /blog/*", got.Groups)
+	}
+}
+
+func TestGroupPages_InvalidOptions_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	blog := analysis.PageGroupRule{Name: "Blog", Pattern: "/blog/*"}
+	for _, tt := range []struct {
+		options analysis.PageGroupOptions
+		marker  string
+	}{
+		{options: analysis.PageGroupOptions{Limit: 10}, marker: "invalid depth 0"},
+		{options: analysis.PageGroupOptions{Rules: []analysis.PageGroupRule{blog}, Depth: 1, Limit: 10}, marker: "not both"},
+		{options: analysis.PageGroupOptions{Rules: []analysis.PageGroupRule{blog, blog}, Limit: 10}, marker: `duplicate page group name "Blog"`},
+		{options: analysis.PageGroupOptions{Rules: []analysis.PageGroupRule{{Name: "Blog", Pattern: "blog/*"}}, Limit: 10}, marker: "must start with /"},
+		{options: analysis.PageGroupOptions{Rules: []analysis.PageGroupRule{{Pattern: "/blog/*"}}, Limit: 10}, marker: "page group 1 has no name"},
+	} {
+		if err := tt.options.Validate(); err == nil || !strings.Contains(err.Error(), tt.marker) {
+			t.Errorf("Validate(%+v) error = %v, want %q", tt.options, err, tt.marker)
+		}
+	}
+
+	_, err := analysis.GroupPages(&searchconsole.SearchAnalyticsResponse{Dimensions: []string{"query"}}, analysis.PageGroupOptions{Depth: 1, Limit: 10})
+	if err == nil || !strings.Contains(err.Error(), "requires the single dimension page") {
+		t.Errorf("GroupPages on query rows error = %v", err)
+	}
+}
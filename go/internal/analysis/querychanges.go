@@ -0,0 +1,129 @@
+package analysis
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// Kinds of query change QueryChanges finds.
+const (
+	QueryChangeNew  = "new"
+	QueryChangeLost = "lost"
+)
+
+// QueryChangeResult is the result of QueryChanges. Rows are the new or lost
+// queries (or query and page pairs), with their metrics from the period in
+// which they had impressions: the current period for new queries, the
+// previous one for lost queries. Count, Clicks, and Impressions cover every
+// such row before limit; ImpressionShare is Impressions as a fraction (0-1)
+// of that period's impressions across all rows.
+type QueryChangeResult struct {
+	SiteURL         string                             `json:"siteUrl"`
+	Change          string                             `json:"change"`
+	Comparison      string                             `json:"comparison"`
+	CurrentPeriod   Period                             `json:"currentPeriod"`
+	PreviousPeriod  Period                             `json:"previousPeriod"`
+	Dimensions      []string                           `json:"dimensions"`
+	SearchType      string                             `json:"searchType"`
+	MinImpressions  float64                            `json:"minImpressions"`
+	Count           int                                `json:"count"`
+	Clicks          float64                            `json:"clicks"`
+	Impressions     float64                            `json:"impressions"`
+	ImpressionShare float64                            `json:"impressionShare"`
+	Truncated       bool                               `json:"truncated"`
+	Warnings        []searchconsole.DataWarning        `json:"warnings,omitempty"`
+	Rows            []searchconsole.SearchAnalyticsRow `json:"rows"`
+	QueriedAt       time.Time                          `json:"queriedAt"`
+}
+
+// QueryChangeOptions controls QueryChanges. Rows with fewer than
+// MinImpressions impressions are ignored; at most Limit rows are returned.
+type QueryChangeOptions struct {
+	MinImpressions float64
+	Limit          int
+}
+
+// Validate reports whether o can be used, so callers can reject it before
+// fetching any rows.
+func (o QueryChangeOptions) Validate() error {
+	if o.MinImpressions < 0 {
+		return fmt.Errorf("invalid min_impressions %v: must not be negative", o.MinImpressions)
+	}
+	if o.Limit <= 0 {
+		return fmt.Errorf("invalid limit %d: must be positive", o.Limit)
+	}
+	return nil
+}
+
+// QueryChanges compares current and previous, which must share dimensions
+// starting with query, and returns the rows that went from no impressions to
+// some (change QueryChangeNew) or from some to none (QueryChangeLost),
+// ordered by impressions and then clicks. Both responses must hold every row
+// of their period: a row missing from a truncated response would be reported
+// as new or lost, so Truncated is set when either one is.
+func QueryChanges(
+	current, previous *searchconsole.SearchAnalyticsResponse,
+	comparison string,
+	change string,
+	options QueryChangeOptions,
+) (*QueryChangeResult, error) {
+	if len(current.Dimensions) == 0 || current.Dimensions[0] != "query" || !slices.Equal(current.Dimensions, previous.Dimensions) {
+		return nil, fmt.Errorf("query changes require both periods to share dimensions starting with query, got %v and %v", current.Dimensions, previous.Dimensions)
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	// from is the period the rows come from, against is the one they must be
+	// absent from.
+	from, against := current, previous
+	switch change {
+	case QueryChangeNew:
+	case QueryChangeLost:
+		from, against = previous, current
+	default:
+		return nil, fmt.Errorf("invalid change %q: must be new or lost", change)
+	}
+
+	seen := make(map[string]bool, len(against.Rows))
+	for _, row := range against.Rows {
+		if row.Impressions > 0 {
+			seen[joinKeys(row.Keys)] = true
+		}
+	}
+	var rows []searchconsole.SearchAnalyticsRow
+	for _, row := range from.Rows {
+		if row.Impressions > 0 && row.Impressions >= options.MinImpressions && !seen[joinKeys(row.Keys)] {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortStableFunc(rows, func(a, b searchconsole.SearchAnalyticsRow) int {
+		return cmp.Or(cmp.Compare(b.Impressions, a.Impressions), cmp.Compare(b.Clicks, a.Clicks))
+	})
+
+	changed, all := Totals(rows), Totals(from.Rows)
+	result := &QueryChangeResult{
+		SiteURL:        current.SiteURL,
+		Change:         change,
+		Comparison:     comparison,
+		CurrentPeriod:  Period{StartDate: current.StartDate, EndDate: current.EndDate},
+		PreviousPeriod: Period{StartDate: previous.StartDate, EndDate: previous.EndDate},
+		Dimensions:     current.Dimensions,
+		SearchType:     current.SearchType,
+		MinImpressions: options.MinImpressions,
+		Count:          len(rows),
+		Clicks:         changed.Clicks,
+		Impressions:    changed.Impressions,
+		Truncated:      current.Truncated || previous.Truncated,
+		Warnings:       mergeWarnings(current, previous),
+		Rows:           firstN(rows, options.Limit),
+		QueriedAt:      current.QueriedAt,
+	}
+	if all.Impressions > 0 {
+		result.ImpressionShare = changed.Impressions / all.Impressions
+	}
+	return result, nil
+}
@@ -0,0 +1,123 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func queryChangeResponses(dimensions ...string) (current, previous *searchconsole.SearchAnalyticsResponse) {
+	current = &searchconsole.SearchAnalyticsResponse{
+		StartDate:  "2026-02-08",
+		EndDate:    "2026-02-14",
+		Dimensions: dimensions,
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"kept"}, 10, 500, 3),
+			row([]string{"new small"}, 1, 20, 8),
+			row([]string{"new big"}, 6, 80, 4),
+			row([]string{"new tiny"}, 0, 2, 30),
+		},
+	}
+	previous = &searchconsole.SearchAnalyticsResponse{
+		StartDate:  "2026-02-01",
+		EndDate:    "2026-02-07",
+		Dimensions: dimensions,
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"kept"}, 12, 450, 3),
+			row([]string{"gone"}, 30, 300, 2),
+			row([]string{"gone quietly"}, 0, 50, 40),
+		},
+	}
+	return current, previous
+}
+
+func TestQueryChanges_New_ListsQueriesAbsentFromPrevious(t *testing.T) {
+	t.Parallel()
+
+	current, previous := queryChangeResponses("query")
+	got, err := analysis.QueryChanges(current, previous, "previous_period", analysis.QueryChangeNew, analysis.QueryChangeOptions{MinImpressions: 5, Limit: 10})
+	if err != nil {
+		t.Fatalf("QueryChanges: %v", err)
+	}
+	var queries []string
+	for _, r := range got.Rows {
+		queries = append(queries, r.Keys[0])
+	}
+	if strings.Join(queries, ",") != "new big,new small" {
+		t.Errorf("rows = %v, want new big then new small", queries)
+	}
+	if got.Change != "new" || got.Count != 2 || got.Clicks != 7 || got.Impressions != 100 || !approxEqual(got.ImpressionShare, 100.0/602) {
+		t.Errorf("result = %+v", got)
+	}
+	if got.CurrentPeriod.StartDate != "2026-02-08" || got.PreviousPeriod.StartDate != "2026-02-01" {
+		t.Errorf("periods = %+v, %+v", got.CurrentPeriod, got.PreviousPeriod)
+	}
+}
+
+func TestQueryChanges_Lost_UsesPreviousMetricsAndLimit(t *testing.T) {
+	t.Parallel()
+
+	current, previous := queryChangeResponses("query")
+	previous.Truncated = true
+	got, err := analysis.QueryChanges(current, previous, "previous_period", analysis.QueryChangeLost, analysis.QueryChangeOptions{Limit: 1})
+	if err != nil {
+		t.Fatalf("QueryChanges: %v", err)
+	}
+	if got.Count != 2 || len(got.Rows) != 1 || got.Rows[0].Keys[0] != "gone" || got.Rows[0].Clicks != 30 {
+		t.Errorf("result = %+v, want gone first of 2", got)
+	}
+	if got.Impressions != 350 || !approxEqual(got.ImpressionShare, 350.0/800) || !got.Truncated {
+		t.Errorf("totals = %v impressions, share %v, truncated %v", got.Impressions, got.ImpressionShare, got.Truncated)
+	}
+}
+
+func TestQueryChanges_ByPage_ComparesPairs(t *testing.T) {
+	t.Parallel()
+
+	current := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"query", "page"},
+		Rows:       []searchconsole.SearchAnalyticsRow{row([]string{"blazor", "/new"}, 5, 100, 3)},
+	}
+	previous := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"query", "page"},
+		Rows:       []searchconsole.SearchAnalyticsRow{row([]string{"blazor", "/old"}, 5, 100, 3)},
+	}
+	got, err := analysis.QueryChanges(current, previous, "previous_period", analysis.QueryChangeNew, analysis.QueryChangeOptions{Limit: 10})
+	if err != nil {
+		t.Fatalf("QueryChanges: %v", err)
+	}
+	if len(got.Rows) != 1 || got.Rows[0].Keys[1] != "/new" {
+		t.Errorf("rows = %+v, want blazor on /new", got.Rows)
+	}
+}
+
+func TestQueryChanges_RejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	current, previous := queryChangeResponses("query")
+	pages, _ := queryChangeResponses("page")
+	options := analysis.QueryChangeOptions{Limit: 10}
+	cases := []struct {
+		name    string
+		current *searchconsole.SearchAnalyticsResponse
+		change  string
+		options analysis.QueryChangeOptions
+		want    string
+	}{
+		{"dimensions", pages, analysis.QueryChangeNew, options, "dimensions starting with query"},
+		{"change", current, "gained", options, `invalid change "gained"`},
+		{"limit", current, analysis.QueryChangeNew, analysis.QueryChangeOptions{}, "invalid limit 0"},
+		{"min impressions", current, analysis.QueryChangeNew, analysis.QueryChangeOptions{MinImpressions: -1, Limit: 10}, "invalid min_impressions -1"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := analysis.QueryChanges(tc.current, previous, "previous_period", tc.change, tc.options)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package analysis
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// StrikingDistanceOptions selects striking-distance opportunities. A query+page
+// row qualifies when its position is within [MinPosition, MaxPosition] and it
+// has at least MinImpressions; uplift is estimated for reaching
+// TargetPosition; at most Limit opportunities are returned.
+type StrikingDistanceOptions struct {
+	MinPosition    float64
+	MaxPosition    float64
+	MinImpressions float64
+	TargetPosition float64
+	Limit          int
+}
+
+// Validate reports whether o can be used, so callers can reject it before
+// fetching any rows.
+func (o StrikingDistanceOptions) Validate() error {
+	if o.MinPosition < 1 || o.MaxPosition < o.MinPosition {
+		return fmt.Errorf("invalid position range %v-%v: min_position must be at least 1 and at most max_position", o.MinPosition, o.MaxPosition)
+	}
+	if o.TargetPosition < 1 || o.TargetPosition >= o.MinPosition {
+		return fmt.Errorf("invalid target_position %v: must be at least 1 and better than min_position %v", o.TargetPosition, o.MinPosition)
+	}
+	if o.MinImpressions < 0 {
+		return fmt.Errorf("invalid min_impressions %v: must not be negative", o.MinImpressions)
+	}
+	if o.Limit <= 0 {
+		return fmt.Errorf("invalid limit %d: must be positive", o.Limit)
+	}
+	return nil
+}
+
+// StrikingDistanceKeyword is a query ranking just off the top of the results
+// for one landing page. EstimatedClicksAtTarget is Impressions times the
+// curve's CTR at the target position; EstimatedClickUplift is how many more
+// clicks that is than the row has now, never negative.
+type StrikingDistanceKeyword struct {
+	Query                   string  `json:"query"`
+	Page                    string  `json:"page"`
+	Clicks                  float64 `json:"clicks"`
+	Impressions             float64 `json:"impressions"`
+	CTR                     float64 `json:"ctr"`
+	Position                float64 `json:"position"`
+	ExpectedCTRAtTarget     float64 `json:"expectedCtrAtTarget"`
+	EstimatedClicksAtTarget float64 `json:"estimatedClicksAtTarget"`
+	EstimatedClickUplift    float64 `json:"estimatedClickUplift"`
+}
+
+// StrikingDistanceResult is the result of StrikingDistance. CTRCurve is the
+// curve the estimates came from, fitted from every row of the query.
+// OpportunityCount counts all qualifying rows, of which at most the requested
+// limit are listed in Keywords.
+type StrikingDistanceResult struct {
+	SiteURL          string                      `json:"siteUrl"`
+	StartDate        string                      `json:"startDate"`
+	EndDate          string                      `json:"endDate"`
+	SearchType       string                      `json:"searchType"`
+	MinPosition      float64                     `json:"minPosition"`
+	MaxPosition      float64                     `json:"maxPosition"`
+	MinImpressions   float64                     `json:"minImpressions"`
+	TargetPosition   float64                     `json:"targetPosition"`
+	CTRCurve         CTRCurve                    `json:"ctrCurve"`
+	OpportunityCount int                         `json:"opportunityCount"`
+	Truncated        bool                        `json:"truncated"`
+	Warnings         []searchconsole.DataWarning `json:"warnings,omitempty"`
+	Keywords         []StrikingDistanceKeyword   `json:"keywords"`
+	QueriedAt        time.Time                   `json:"queriedAt"`
+}
+
+// StrikingDistance finds the query+page rows of resp, which must have the
+// dimensions query and page in that order, that rank within the options'
+// position range, and orders them by estimated click uplift, largest first.
+// The CTR curve is fitted from all of resp's rows.
+func StrikingDistance(
+	resp *searchconsole.SearchAnalyticsResponse,
+	options StrikingDistanceOptions,
+) (*StrikingDistanceResult, error) {
+	if !slices.Equal(resp.Dimensions, []string{"query", "page"}) {
+		return nil, errors.New("striking distance analysis requires the dimensions query and page, in that order")
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	curve := FitCTRCurve(resp.Rows)
+	targetCTR := curve.ExpectedCTR(options.TargetPosition)
+
+	var keywords []StrikingDistanceKeyword
+	for _, row := range resp.Rows {
+		if len(row.Keys) != 2 ||
+			row.Position < options.MinPosition || row.Position > options.MaxPosition ||
+			row.Impressions < options.MinImpressions {
+			continue
+		}
+		estimated := row.Impressions * targetCTR
+		keywords = append(keywords, StrikingDistanceKeyword{
+			Query:                   row.Keys[0],
+			Page:                    row.Keys[1],
+			Clicks:                  row.Clicks,
+			Impressions:             row.Impressions,
+			CTR:                     row.CTR,
+			Position:                row.Position,
+			ExpectedCTRAtTarget:     targetCTR,
+			EstimatedClicksAtTarget: estimated,
+			EstimatedClickUplift:    max(estimated-row.Clicks, 0),
+		})
+	}
+	slices.SortStableFunc(keywords, func(a, b StrikingDistanceKeyword) int {
+		if c := cmp.Compare(b.EstimatedClickUplift, a.EstimatedClickUplift); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Impressions, a.Impressions)
+	})
+
+	return &StrikingDistanceResult{
+		SiteURL:          resp.SiteURL,
+		StartDate:        resp.StartDate,
+		EndDate:          resp.EndDate,
+		SearchType:       resp.SearchType,
+		MinPosition:      options.MinPosition,
+		MaxPosition:      options.MaxPosition,
+		MinImpressions:   options.MinImpressions,
+		TargetPosition:   options.TargetPosition,
+		CTRCurve:         curve,
+		OpportunityCount: len(keywords),
+		Truncated:        resp.Truncated,
+		Warnings:         mergeWarnings(resp),
+		Keywords:         firstN(keywords, options.Limit),
+		QueriedAt:        resp.QueriedAt,
+	}, nil
+}
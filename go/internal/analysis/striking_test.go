@@ -0,0 +1,71 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestStrikingDistance_RanksRowsInRangeByEstimatedUplift(t *testing.T) {
+	t.Parallel()
+
+	resp := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"query", "page"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			// Curve anchors: 20% CTR at position 3.
+			row([]string{"top", "/top"}, 200, 1000, 3),
+			row([]string{"close", "/close"}, 10, 1000, 9),
+			row([]string{"bigger", "/bigger"}, 30, 3000, 15),
+			row([]string{"too few", "/few"}, 0, 50, 12),
+			row([]string{"too deep", "/deep"}, 0, 5000, 25),
+		},
+	}
+
+	got, err := analysis.StrikingDistance(resp, analysis.StrikingDistanceOptions{
+		MinPosition: 8, MaxPosition: 20, MinImpressions: 100, TargetPosition: 3, Limit: 10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.OpportunityCount != 2 || len(got.Keywords) != 2 {
+		t.Fatalf("keywords = %+v, want bigger and close", got.Keywords)
+	}
+	first := got.Keywords[0]
+	if first.Query != "bigger" || first.Page != "/bigger" {
+		t.Errorf("first keyword = %s %s, want bigger /bigger", first.Query, first.Page)
+	}
+	if !approxEqual(first.ExpectedCTRAtTarget, 0.2) || !approxEqual(first.EstimatedClicksAtTarget, 600) || !approxEqual(first.EstimatedClickUplift, 570) {
+		t.Errorf("first estimates = %+v, want 0.2 CTR, 600 clicks, 570 uplift", first)
+	}
+	if got.Keywords[1].Query != "close" || !approxEqual(got.Keywords[1].EstimatedClickUplift, 190) {
+		t.Errorf("second keyword = %+v, want close with 190 uplift", got.Keywords[1])
+	}
+	if len(got.CTRCurve.Points) == 0 {
+		t.Error("ctrCurve is empty, want the fitted curve included")
+	}
+}
+
+func TestStrikingDistanceOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name    string
+		options analysis.StrikingDistanceOptions
+		marker  string
+	}{
+		{name: "inverted range", options: analysis.StrikingDistanceOptions{MinPosition: 20, MaxPosition: 8, TargetPosition: 3, Limit: 1}, marker: "invalid position range"},
+		{name: "target inside range", options: analysis.StrikingDistanceOptions{MinPosition: 8, MaxPosition: 20, TargetPosition: 10, Limit: 1}, marker: "invalid target_position 10"},
+		{name: "negative impressions", options: analysis.StrikingDistanceOptions{MinPosition: 8, MaxPosition: 20, TargetPosition: 3, MinImpressions: -1, Limit: 1}, marker: "invalid min_impressions"},
+		{name: "zero limit", options: analysis.StrikingDistanceOptions{MinPosition: 8, MaxPosition: 20, TargetPosition: 3}, marker: "invalid limit 0"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.marker) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.marker)
+			}
+		})
+	}
+}
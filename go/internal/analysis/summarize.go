@@ -0,0 +1,46 @@
+package analysis
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// Metrics SummarizeRows can keep the top rows by.
+const (
+	SummaryMetricClicks      = "clicks"
+	SummaryMetricImpressions = "impressions"
+)
+
+// SummarizeRows returns a copy of resp that keeps only its top keep rows by
+// metric, with a Summary that aggregates the omitted rows and every row.
+// resp itself is not modified.
+func SummarizeRows(resp *searchconsole.SearchAnalyticsResponse, metric string, keep int) (*searchconsole.SearchAnalyticsResponse, error) {
+	if metric != SummaryMetricClicks && metric != SummaryMetricImpressions {
+		return nil, fmt.Errorf("invalid summary_metric %q: must be %s or %s", metric, SummaryMetricClicks, SummaryMetricImpressions)
+	}
+	keep = min(max(keep, 0), len(resp.Rows))
+
+	rows := slices.Clone(resp.Rows)
+	slices.SortStableFunc(rows, func(a, b searchconsole.SearchAnalyticsRow) int {
+		if metric == SummaryMetricImpressions {
+			return cmp.Compare(b.Impressions, a.Impressions)
+		}
+		return cmp.Or(cmp.Compare(b.Clicks, a.Clicks), cmp.Compare(b.Impressions, a.Impressions))
+	})
+
+	summarized := *resp
+	summarized.Rows = rows[:keep]
+	summarized.RowCount = keep
+	summarized.Summary = &searchconsole.RowSummary{
+		Summarized:  true,
+		SortedBy:    metric,
+		RowsShown:   keep,
+		RowsOmitted: len(rows) - keep,
+		Other:       Totals(rows[keep:]),
+		Totals:      Totals(rows),
+	}
+	return &summarized, nil
+}
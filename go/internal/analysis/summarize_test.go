@@ -0,0 +1,62 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestSummarizeRows_KeepsTopRowsAndBucketsTheRest(t *testing.T) {
+	t.Parallel()
+
+	resp := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"query"},
+		RowCount:   4,
+		Rows: []searchconsole.SearchAnalyticsRow{
+			row([]string{"c"}, 5, 500, 8),
+			row([]string{"a"}, 20, 100, 2),
+			row([]string{"d"}, 1, 300, 12),
+			row([]string{"b"}, 10, 100, 4),
+		},
+	}
+
+	got, err := analysis.SummarizeRows(resp, analysis.SummaryMetricClicks, 2)
+	if err != nil {
+		t.Fatalf("SummarizeRows: %v", err)
+	}
+	if len(got.Rows) != 2 || got.Rows[0].Keys[0] != "a" || got.Rows[1].Keys[0] != "b" || got.RowCount != 2 {
+		t.Fatalf("rows = %+v, want a then b", got.Rows)
+	}
+	s := got.Summary
+	if !s.Summarized || s.SortedBy != "clicks" || s.RowsShown != 2 || s.RowsOmitted != 2 {
+		t.Errorf("summary = %+v", s)
+	}
+	if s.Other.Clicks != 6 || s.Other.Impressions != 800 || !approxEqual(s.Other.Position, (8*500+12*300)/800.0) {
+		t.Errorf("other = %+v, want c and d combined", s.Other)
+	}
+	if s.Totals.Clicks != 36 || s.Totals.Impressions != 1000 || !approxEqual(s.Totals.CTR, 0.036) {
+		t.Errorf("totals = %+v, want every row combined", s.Totals)
+	}
+	if len(resp.Rows) != 4 || resp.Rows[0].Keys[0] != "c" || resp.Summary != nil {
+		t.Errorf("input was modified: %+v", resp)
+	}
+
+	byImpressions, err := analysis.SummarizeRows(resp, analysis.SummaryMetricImpressions, 1)
+	if err != nil {
+		t.Fatalf("SummarizeRows: %v", err)
+	}
+	if byImpressions.Rows[0].Keys[0] != "c" || byImpressions.Summary.RowsOmitted != 3 {
+		t.Errorf("by impressions = %+v, want c kept", byImpressions)
+	}
+}
+
+func TestSummarizeRows_InvalidMetric_ReturnsError(t *testing.T) {
+	t.Parallel()
+
+	_, err := analysis.SummarizeRows(&searchconsole.SearchAnalyticsResponse{}, "ctr", 1)
+	if err == nil || !strings.Contains(err.Error(), `invalid summary_metric "ctr"`) {
+		t.Errorf("error = %v, want invalid summary_metric", err)
+	}
+}
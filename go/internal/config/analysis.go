@@ -0,0 +1,96 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+const envVarAnalysisConfigFile = "GSC_ANALYSIS_CONFIG_FILE"
+
+// AnalysisConfig holds optional per-property settings for the analysis
+// tools, loaded from a JSON file.
+type AnalysisConfig struct {
+	// Brands maps a property to the terms that make a query branded. Keys are
+	// matched against a tool's site_url by domain, so "devleader.ca",
+	// "sc-domain:devleader.ca", and "https://www.devleader.ca/" are
+	// equivalent; the key "*" applies to every property without its own entry.
+	Brands map[string]BrandConfig `json:"brands,omitempty"`
+
+	// PageGroups maps a property, keyed like Brands, to the site sections
+	// that page rollups group URLs into.
+	PageGroups map[string][]PageGroupConfig `json:"page_groups,omitempty"`
+
+	// Intents maps a property, keyed like Brands, to the keywords that
+	// classify its queries by search intent.
+	Intents map[string]IntentConfig `json:"intents,omitempty"`
+}
+
+// BrandConfig lists the brand terms and regular expressions for a property.
+type BrandConfig struct {
+	// Terms are matched case-insensitively as whole words of a query, with or
+	// without the spaces between them, so "dev leader" matches both
+	// "devleader" and "dev leader blog", but "go" does not match "google".
+	Terms []string `json:"terms,omitempty"`
+
+	// Patterns are RE2 regular expressions, matched case-insensitively.
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// PageGroupConfig is one named site section.
+type PageGroupConfig struct {
+	Name string `json:"name"`
+
+	// Pattern is matched against the whole URL path; "*" matches any run of
+	// characters, including "/", so "/blog/*" covers every page under /blog/.
+	Pattern string `json:"pattern"`
+}
+
+// IntentConfig lists the keywords of each search intent for a property. A
+// keyword matches whole words anywhere in a query, case-insensitively, so
+// "free trial" matches "blazor free trial" but not "freed". The lists extend
+// the built-in ones unless ReplaceDefaults is set.
+type IntentConfig struct {
+	Informational []string `json:"informational,omitempty"`
+	Navigational  []string `json:"navigational,omitempty"`
+	Commercial    []string `json:"commercial,omitempty"`
+	Transactional []string `json:"transactional,omitempty"`
+
+	// QuestionWords make a query informational when it starts with one.
+	QuestionWords []string `json:"question_words,omitempty"`
+
+	// ReplaceDefaults drops the built-in keyword lists and question words,
+	// leaving only the ones above.
+	ReplaceDefaults bool `json:"replace_defaults,omitempty"`
+}
+
+// LoadAnalysisConfig reads the analysis config file named by
+// analysisConfigFile (the --analysis-config-file CLI flag) or, when that is
+// empty, by the GSC_ANALYSIS_CONFIG_FILE env var. With neither set it returns
+// an empty config. Unlike credentials, a config file that was asked for but
+// cannot be read or parsed is an error, since silently running without it
+// would silently misreport every brand split, page rollup, or intent.
+func LoadAnalysisConfig(analysisConfigFile string) (AnalysisConfig, error) {
+	path := analysisConfigFile
+	if path == "" {
+		path = os.Getenv(envVarAnalysisConfigFile)
+	}
+	if path == "" {
+		return AnalysisConfig{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return AnalysisConfig{}, fmt.Errorf("reading analysis config file: %w", err)
+	}
+	var cfg AnalysisConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return AnalysisConfig{}, fmt.Errorf("parsing analysis config file %s: %w", path, err)
+	}
+	slog.Debug("analysis config loaded", "path", path, "brands", len(cfg.Brands), "page_groups", len(cfg.PageGroups), "intents", len(cfg.Intents))
+	return cfg, nil
+}
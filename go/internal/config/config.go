@@ -1,6 +1,7 @@
 // Package config resolves Google Search Console service account credentials from multiple sources.
 // Priority order: CLI flag (file path) > GOOGLE_SERVICE_ACCOUNT_FILE env var >
-// GOOGLE_SERVICE_ACCOUNT_JSON env var > .env file.
+// GOOGLE_SERVICE_ACCOUNT_JSON env var > .env file. It also loads the optional
+// analysis config file that holds per-property settings for the analysis tools.
 package config
 
 import (
@@ -132,3 +132,75 @@ func TestResolve_UnreadableFlagFile_FallsThroughToNextSource(t *testing.T) {
 		t.Errorf("ServiceAccountJSON = %q, want fallthrough to the env var's content", got.ServiceAccountJSON)
 	}
 }
+
+// TestLoadAnalysisConfig_FlagTakesPriorityOverEnv confirms the
+// --analysis-config-file flag wins over GSC_ANALYSIS_CONFIG_FILE.
+func TestLoadAnalysisConfig_FlagTakesPriorityOverEnv(t *testing.T) {
+	dir := t.TempDir()
+	flagPath := filepath.Join(dir, "flag.json")
+	envPath := filepath.Join(dir, "env.json")
+	if err := os.WriteFile(flagPath, []byte(`{
+		"brands":{"devleader.ca":{"terms":["dev leader"],"patterns":["^nick"]}},
+		"page_groups":{"*":[{"name":"Blog","pattern":"/blog/*"}]},
+		"intents":{"devleader.ca":{"commercial":["course"],"question_words":["wat"],"replace_defaults":true}}
+	}`), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := os.WriteFile(envPath, []byte(`{"brands":{"*":{"terms":["env"]}}}`), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	t.Setenv("GSC_ANALYSIS_CONFIG_FILE", envPath)
+
+	got, err := config.LoadAnalysisConfig(flagPath)
+	if err != nil {
+		t.Fatalf("LoadAnalysisConfig: %v", err)
+	}
+	brand := got.Brands["devleader.ca"]
+	if len(brand.Terms) != 1 || brand.Terms[0] != "dev leader" || len(brand.Patterns) != 1 {
+		t.Errorf("Brands = %+v, want the flag file's devleader.ca entry", got.Brands)
+	}
+	if groups := got.PageGroups["*"]; len(groups) != 1 || groups[0].Name != "Blog" || groups[0].Pattern != "/blog/*" {
+		t.Errorf("PageGroups = %+v, want the flag file's Blog group", got.PageGroups)
+	}
+	if intents := got.Intents["devleader.ca"]; len(intents.Commercial) != 1 || len(intents.QuestionWords) != 1 || !intents.ReplaceDefaults {
+		t.Errorf("Intents = %+v, want the flag file's devleader.ca entry", got.Intents)
+	}
+
+	got, err = config.LoadAnalysisConfig("")
+	if err != nil {
+		t.Fatalf("LoadAnalysisConfig: %v", err)
+	}
+	if _, ok := got.Brands["*"]; !ok {
+		t.Errorf("Brands = %+v, want the env file's entry when no flag is given", got.Brands)
+	}
+}
+
+// TestLoadAnalysisConfig_NothingConfigured_ReturnsEmptyConfig confirms the
+// analysis config is optional.
+func TestLoadAnalysisConfig_NothingConfigured_ReturnsEmptyConfig(t *testing.T) {
+	t.Setenv("GSC_ANALYSIS_CONFIG_FILE", "")
+
+	got, err := config.LoadAnalysisConfig("")
+	if err != nil {
+		t.Fatalf("LoadAnalysisConfig: %v", err)
+	}
+	if got.Brands != nil {
+		t.Errorf("Brands = %+v, want nil", got.Brands)
+	}
+}
+
+// TestLoadAnalysisConfig_BadFile_ReturnsError confirms a config file that was
+// asked for but is missing or malformed is reported rather than ignored.
+func TestLoadAnalysisConfig_BadFile_ReturnsError(t *testing.T) {
+	dir := t.TempDir()
+	typo := filepath.Join(dir, "typo.json")
+	if err := os.WriteFile(typo, []byte(`{"brand":{}}`), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	for _, path := range []string{filepath.Join(dir, "missing.json"), typo} {
+		if _, err := config.LoadAnalysisConfig(path); err == nil {
+			t.Errorf("LoadAnalysisConfig(%q) returned nil error", path)
+		}
+	}
+}
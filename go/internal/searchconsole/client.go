@@ -11,6 +11,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -26,6 +27,20 @@ const (
 	// search_type, matching the upstream API's own documented default.
 	defaultSearchType   = "web"
 	defaultLanguageCode = "en-US"
+
+	// defaultAggregationType and defaultDataState are the effective values
+	// upstream applies when the request omits aggregationType and dataState.
+	defaultAggregationType = "auto"
+	defaultDataState       = "final"
+
+	// defaultRowLimit is the page size used when the caller omits row_limit.
+	// maxRowsPerRequest is the upstream API's own per-request ceiling.
+	defaultRowLimit   = 1000
+	maxRowsPerRequest = 25000
+
+	// defaultMaxRows caps how many rows an all-rows query collects across pages
+	// when the caller does not set a cap of its own.
+	defaultMaxRows = 100000
 )
 
 // validSearchTypes are the upstream Search Console API's supported values for
@@ -40,6 +55,22 @@ var validSearchTypes = map[string]bool{
 	"googleNews": true,
 }
 
+// validAggregationTypes are the upstream API's supported aggregationType values.
+var validAggregationTypes = map[string]bool{
+	"auto":                true,
+	"byPage":              true,
+	"byProperty":          true,
+	"byNewsShowcasePanel": true,
+}
+
+// validDataStates are the upstream API's supported dataState values.
+// "hourly_all" is only valid together with the hour dimension.
+var validDataStates = map[string]bool{
+	"final":      true,
+	"all":        true,
+	"hourly_all": true,
+}
+
 var (
 	// apiBaseURL serves the Sites, Sitemaps, and Search Analytics resources.
 	apiBaseURL = "https://www.googleapis.com/webmasters/v3"
@@ -123,12 +154,41 @@ func withResolvedSiteURL[T any](
 	return operation(resolvedURL)
 }
 
+// SearchAnalyticsOptions carries the optional search analytics request fields
+// beyond the date range, dimensions, row limit, and search type. The zero value
+// sends none of them, preserving the upstream defaults.
+type SearchAnalyticsOptions struct {
+	// DimensionFilterGroups restricts the query to matching rows. Each group's
+	// GroupType defaults to "and" and each filter's Operator to "equals".
+	DimensionFilterGroups []DimensionFilterGroup
+
+	// StartRow is the zero-based index of the first row to return.
+	StartRow int
+
+	// AllRows pages through results, rowLimit rows at a time (25,000 when
+	// rowLimit is omitted), until upstream returns a short page or MaxRows rows
+	// have been collected.
+	AllRows bool
+
+	// MaxRows caps the rows collected when AllRows is set. Zero means 100,000.
+	MaxRows int
+
+	// AggregationType selects how upstream aggregates results: "auto",
+	// "byPage", "byProperty", or "byNewsShowcasePanel". Empty omits the field.
+	AggregationType string
+
+	// DataState selects "final" data only or "all" data including fresh,
+	// still-changing days. Empty omits the field.
+	DataState string
+}
+
 // QuerySearchAnalytics queries search analytics data for the given site.
 // siteURL accepts any of: bare domain ("example.com"), URL ("https://example.com"),
 // or canonical GSC form ("sc-domain:example.com", "https://example.com/").
 // searchType filters results to one upstream-supported type ("web", "image",
 // "video", "news", "discover", "googleNews"); an empty string omits the field
 // from the outbound request entirely, which upstream defaults to "web".
+// options are validated before any network call is made.
 func (c *Client) QuerySearchAnalytics(
 	ctx context.Context,
 	siteURL string,
@@ -136,18 +196,58 @@ func (c *Client) QuerySearchAnalytics(
 	dimensions []string,
 	rowLimit int,
 	searchType string,
+	options SearchAnalyticsOptions,
 ) (*SearchAnalyticsResponse, error) {
-	if searchType != "" && !validSearchTypes[searchType] {
-		return nil, fmt.Errorf(
-			"invalid search_type %q: must be one of web, image, video, news, discover, googleNews", searchType)
+	options, err := validateSearchAnalyticsQuery(startDate, endDate, dimensions, rowLimit, searchType, options)
+	if err != nil {
+		return nil, err
 	}
 
 	return withResolvedSiteURL(ctx, c, siteURL, func(resolved string) (*SearchAnalyticsResponse, error) {
 		return c.querySearchAnalyticsWithURL(
-			ctx, resolved, startDate, endDate, dimensions, rowLimit, searchType)
+			ctx, resolved, startDate, endDate, dimensions, rowLimit, searchType, options)
 	})
 }
 
+// validateSearchAnalyticsQuery checks a search analytics query without
+// sending it, returning options with its filter groups normalized.
+func validateSearchAnalyticsQuery(
+	startDate, endDate string,
+	dimensions []string,
+	rowLimit int,
+	searchType string,
+	options SearchAnalyticsOptions,
+) (SearchAnalyticsOptions, error) {
+	if searchType != "" && !validSearchTypes[searchType] {
+		return options, fmt.Errorf(
+			"invalid search_type %q: must be one of web, image, video, news, discover, googleNews", searchType)
+	}
+	if err := validateDates(startDate, endDate); err != nil {
+		return options, err
+	}
+	if err := validateDimensions(dimensions, searchType); err != nil {
+		return options, err
+	}
+	if rowLimit > maxRowsPerRequest {
+		return options, fmt.Errorf("invalid row_limit %d: must be at most %d", rowLimit, maxRowsPerRequest)
+	}
+	if options.StartRow < 0 {
+		return options, fmt.Errorf("invalid start_row %d: must not be negative", options.StartRow)
+	}
+	if options.MaxRows < 0 {
+		return options, fmt.Errorf("invalid max_rows %d: must not be negative", options.MaxRows)
+	}
+	filterGroups, err := normalizeFilterGroups(options.DimensionFilterGroups)
+	if err != nil {
+		return options, err
+	}
+	options.DimensionFilterGroups = filterGroups
+	if err := validateAggregation(dimensions, searchType, options); err != nil {
+		return options, err
+	}
+	return options, nil
+}
+
 func (c *Client) querySearchAnalyticsWithURL(
 	ctx context.Context,
 	siteURL string,
@@ -155,17 +255,170 @@ func (c *Client) querySearchAnalyticsWithURL(
 	dimensions []string,
 	rowLimit int,
 	searchType string,
+	options SearchAnalyticsOptions,
 ) (*SearchAnalyticsResponse, error) {
+	requestedStartDate := startDate
+	startDate = clampToRetention(startDate)
 	if rowLimit <= 0 {
-		rowLimit = 1000
+		if options.AllRows {
+			rowLimit = maxRowsPerRequest
+		} else {
+			rowLimit = defaultRowLimit
+		}
 	}
 	reqBody := apiSearchAnalyticsRequest{
-		StartDate:  startDate,
-		EndDate:    endDate,
-		Dimensions: dimensions,
-		Type:       searchType,
-		RowLimit:   rowLimit,
+		StartDate:             startDate,
+		EndDate:               endDate,
+		Dimensions:            dimensions,
+		Type:                  searchType,
+		DimensionFilterGroups: options.DimensionFilterGroups,
+		AggregationType:       options.AggregationType,
+		DataState:             options.DataState,
+		RowLimit:              rowLimit,
+		StartRow:              options.StartRow,
+	}
+
+	var rows []SearchAnalyticsRow
+	truncated := false
+	if options.AllRows {
+		maxRows := options.MaxRows
+		if maxRows <= 0 {
+			maxRows = defaultMaxRows
+		}
+		// Page until upstream returns a short page, which is the only signal it
+		// gives that no further rows exist, or until the caller's cap is reached.
+		for {
+			reqBody.RowLimit = min(rowLimit, maxRows-len(rows))
+			page, err := c.fetchSearchAnalyticsRows(ctx, siteURL, reqBody)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, page...)
+			if len(page) < reqBody.RowLimit {
+				break
+			}
+			if len(rows) >= maxRows {
+				truncated = true
+				break
+			}
+			reqBody.StartRow += len(page)
+		}
+	} else {
+		page, err := c.fetchSearchAnalyticsRows(ctx, siteURL, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		rows = page
+		truncated = len(rows) >= rowLimit
+	}
+	if rows == nil {
+		rows = []SearchAnalyticsRow{}
 	}
+
+	effectiveSearchType := searchType
+	if effectiveSearchType == "" {
+		effectiveSearchType = defaultSearchType
+	}
+	effectiveAggregationType := options.AggregationType
+	if effectiveAggregationType == "" {
+		effectiveAggregationType = defaultAggregationType
+	}
+	effectiveDataState := options.DataState
+	if effectiveDataState == "" {
+		effectiveDataState = defaultDataState
+	}
+
+	return &SearchAnalyticsResponse{
+		SiteURL:               siteURL,
+		StartDate:             startDate,
+		EndDate:               endDate,
+		Dimensions:            dimensions,
+		SearchType:            effectiveSearchType,
+		AggregationType:       effectiveAggregationType,
+		DataState:             effectiveDataState,
+		DimensionFilterGroups: options.DimensionFilterGroups,
+		StartRow:              options.StartRow,
+		RowCount:              len(rows),
+		Truncated:             truncated,
+		Warnings:              dataWarnings(requestedStartDate, endDate, effectiveDataState),
+		Rows:                  rows,
+		QueriedAt:             time.Now().UTC(),
+	}, nil
+}
+
+// validateAggregation rejects aggregationType and dataState values upstream does
+// not support, and the aggregationType combinations it documents as forbidden.
+// filter groups must already be normalized.
+func validateAggregation(dimensions []string, searchType string, options SearchAnalyticsOptions) error {
+	if options.DataState != "" && !validDataStates[options.DataState] {
+		return fmt.Errorf("invalid data_state %q: must be one of final, all, hourly_all", options.DataState)
+	}
+	groupsByHour := slices.Contains(dimensions, "hour")
+	if groupsByHour && options.DataState != hourlyDataState {
+		return errors.New("the hour dimension requires data_state \"hourly_all\"")
+	}
+	if !groupsByHour && options.DataState == hourlyDataState {
+		return errors.New("data_state \"hourly_all\" requires the hour dimension")
+	}
+
+	aggregationType := options.AggregationType
+	if aggregationType == "" {
+		return nil
+	}
+	if !validAggregationTypes[aggregationType] {
+		return fmt.Errorf(
+			"invalid aggregation_type %q: must be one of auto, byPage, byProperty, byNewsShowcasePanel", aggregationType)
+	}
+
+	usesPage := slices.Contains(dimensions, "page")
+	for _, group := range options.DimensionFilterGroups {
+		for _, filter := range group.Filters {
+			if filter.Dimension == "page" {
+				usesPage = true
+			}
+		}
+	}
+
+	switch aggregationType {
+	case "byProperty":
+		if usesPage {
+			return errors.New(
+				"aggregation_type \"byProperty\" cannot be combined with the page dimension or a page filter: use auto or byPage")
+		}
+	case "byNewsShowcasePanel":
+		if searchType != "discover" && searchType != "googleNews" {
+			return errors.New(
+				"aggregation_type \"byNewsShowcasePanel\" requires search_type \"discover\" or \"googleNews\"")
+		}
+		if usesPage {
+			return errors.New(
+				"aggregation_type \"byNewsShowcasePanel\" cannot be combined with the page dimension or a page filter")
+		}
+		if !hasNewsShowcaseFilter(options.DimensionFilterGroups) {
+			return errors.New(
+				"aggregation_type \"byNewsShowcasePanel\" requires a searchAppearance equals NEWS_SHOWCASE filter")
+		}
+	}
+	return nil
+}
+
+func hasNewsShowcaseFilter(groups []DimensionFilterGroup) bool {
+	for _, group := range groups {
+		for _, filter := range group.Filters {
+			if filter.Dimension == "searchAppearance" && filter.Operator == "equals" && filter.Expression == "NEWS_SHOWCASE" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fetchSearchAnalyticsRows sends one search analytics request and returns its rows.
+func (c *Client) fetchSearchAnalyticsRows(
+	ctx context.Context,
+	siteURL string,
+	reqBody apiSearchAnalyticsRequest,
+) ([]SearchAnalyticsRow, error) {
 	bodyBytes, err := json.Marshal(reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("marshalling request body: %w", err)
@@ -200,24 +453,15 @@ func (c *Client) querySearchAnalyticsWithURL(
 
 	rows := make([]SearchAnalyticsRow, len(raw.Rows))
 	for i, r := range raw.Rows {
-		rows[i] = SearchAnalyticsRow(r)
-	}
-
-	effectiveSearchType := searchType
-	if effectiveSearchType == "" {
-		effectiveSearchType = defaultSearchType
+		rows[i] = SearchAnalyticsRow{
+			Keys:        r.Keys,
+			Clicks:      r.Clicks,
+			Impressions: r.Impressions,
+			CTR:         r.CTR,
+			Position:    r.Position,
+		}
 	}
-
-	return &SearchAnalyticsResponse{
-		SiteURL:    siteURL,
-		StartDate:  startDate,
-		EndDate:    endDate,
-		Dimensions: dimensions,
-		SearchType: effectiveSearchType,
-		RowCount:   len(rows),
-		Rows:       rows,
-		QueriedAt:  time.Now().UTC(),
-	}, nil
+	return rows, nil
 }
 
 // ListSites returns all Search Console properties accessible to the service account.
@@ -0,0 +1,156 @@
+package searchconsole
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestQuerySearchAnalytics_AggregationAndDataState_SentUpstreamAndEchoed(t *testing.T) {
+	var gotBody apiSearchAnalyticsRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	client := NewTestClient(srv.Client())
+	resp, err := client.QuerySearchAnalytics(
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", []string{"page"}, 10, "",
+		SearchAnalyticsOptions{AggregationType: "byPage", DataState: "all"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotBody.AggregationType != "byPage" || gotBody.DataState != "all" {
+		t.Errorf("request aggregationType/dataState = %q/%q, want byPage/all", gotBody.AggregationType, gotBody.DataState)
+	}
+	if resp.AggregationType != "byPage" || resp.DataState != "all" {
+		t.Errorf("response aggregationType/dataState = %q/%q, want byPage/all", resp.AggregationType, resp.DataState)
+	}
+}
+
+// TestQuerySearchAnalytics_AggregationOmitted_PreservesRequestAndEchoesDefaults
+// confirms omission sends neither field, while the response still reports the
+// effective upstream defaults explicitly.
+func TestQuerySearchAnalytics_AggregationOmitted_PreservesRequestAndEchoesDefaults(t *testing.T) {
+	var rawBody map[string]json.RawMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&rawBody)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	client := NewTestClient(srv.Client())
+	resp, err := client.QuerySearchAnalytics(
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, 10, "", SearchAnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, field := range []string{"aggregationType", "dataState"} {
+		if _, present := rawBody[field]; present {
+			t.Errorf("request body unexpectedly contains %q", field)
+		}
+	}
+	if resp.AggregationType != "auto" || resp.DataState != "final" {
+		t.Errorf("response aggregationType/dataState = %q/%q, want auto/final", resp.AggregationType, resp.DataState)
+	}
+}
+
+func TestQuerySearchAnalytics_NewsShowcaseAggregation_IsAccepted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	client := NewTestClient(srv.Client())
+	_, err := client.QuerySearchAnalytics(
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, 10, "discover",
+		SearchAnalyticsOptions{
+			AggregationType: "byNewsShowcasePanel",
+			DimensionFilterGroups: []DimensionFilterGroup{{
+				Filters: []DimensionFilter{{Dimension: "searchAppearance", Expression: "NEWS_SHOWCASE"}},
+			}},
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestQuerySearchAnalytics_InvalidAggregation_RejectedWithoutHTTPCall(t *testing.T) {
+	pageFilter := []DimensionFilterGroup{{Filters: []DimensionFilter{{Dimension: "page", Operator: "contains", Expression: "/blog/"}}}}
+	tests := []struct {
+		name       string
+		dimensions []string
+		searchType string
+		options    SearchAnalyticsOptions
+		marker     string
+	}{
+		{
+			name:    "unknown aggregation type",
+			options: SearchAnalyticsOptions{AggregationType: "byQuery"},
+			marker:  `invalid aggregation_type "byQuery"`,
+		},
+		{
+			name:    "unknown data state",
+			options: SearchAnalyticsOptions{DataState: "fresh"},
+			marker:  `invalid data_state "fresh"`,
+		},
+		{
+			name:       "byProperty with page dimension",
+			dimensions: []string{"query", "page"},
+			options:    SearchAnalyticsOptions{AggregationType: "byProperty"},
+			marker:     `"byProperty" cannot be combined with the page dimension`,
+		},
+		{
+			name:    "byProperty with page filter",
+			options: SearchAnalyticsOptions{AggregationType: "byProperty", DimensionFilterGroups: pageFilter},
+			marker:  `"byProperty" cannot be combined with the page dimension or a page filter`,
+		},
+		{
+			name:       "news showcase on web",
+			searchType: "web",
+			options:    SearchAnalyticsOptions{AggregationType: "byNewsShowcasePanel"},
+			marker:     `requires search_type "discover" or "googleNews"`,
+		},
+		{
+			name:       "news showcase without appearance filter",
+			searchType: "googleNews",
+			options:    SearchAnalyticsOptions{AggregationType: "byNewsShowcasePanel"},
+			marker:     "requires a searchAppearance equals NEWS_SHOWCASE filter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callCount := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				callCount++
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`{"rows":[]}`))
+			}))
+			defer srv.Close()
+			defer SetTestAPIBaseURL(srv.URL)()
+
+			client := NewTestClient(srv.Client())
+			_, err := client.QuerySearchAnalytics(
+				context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", tt.dimensions, 10, tt.searchType, tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.marker) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.marker)
+			}
+			if callCount != 0 {
+				t.Errorf("expected 0 HTTP calls, got %d", callCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package searchconsole
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestQuerySearchAnalytics_DimensionFilterGroups_SentUpstreamWithDefaults
+// confirms filter groups reach the upstream request body with the API's own
+// defaults (groupType "and", operator "equals") made explicit, and that the
+// response echoes exactly what was applied.
+func TestQuerySearchAnalytics_DimensionFilterGroups_SentUpstreamWithDefaults(t *testing.T) {
+	var gotBody apiSearchAnalyticsRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("decoding request body: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	client := NewTestClient(srv.Client())
+	resp, err := client.QuerySearchAnalytics(
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", []string{"query"}, 10, "",
+		SearchAnalyticsOptions{DimensionFilterGroups: []DimensionFilterGroup{{
+			Filters: []DimensionFilter{
+				{Dimension: "query", Operator: "contains", Expression: "pricing"},
+				{Dimension: "device", Expression: "mobile"},
+				{Dimension: "country", Expression: "USA"},
+			},
+		}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []DimensionFilterGroup{{
+		GroupType: "and",
+		Filters: []DimensionFilter{
+			{Dimension: "query", Operator: "contains", Expression: "pricing"},
+			{Dimension: "device", Operator: "equals", Expression: "MOBILE"},
+			{Dimension: "country", Operator: "equals", Expression: "usa"},
+		},
+	}}
+	assertFilterGroupsEqual(t, "request body", gotBody.DimensionFilterGroups, want)
+	assertFilterGroupsEqual(t, "response", resp.DimensionFilterGroups, want)
+}
+
+// TestQuerySearchAnalytics_NoFilters_OmitsFieldUpstream confirms the
+// pre-existing request shape is preserved when no filters are supplied.
+func TestQuerySearchAnalytics_NoFilters_OmitsFieldUpstream(t *testing.T) {
+	var rawBody map[string]json.RawMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&rawBody)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	client := NewTestClient(srv.Client())
+	_, err := client.QuerySearchAnalytics(
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, 10, "", SearchAnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, present := rawBody["dimensionFilterGroups"]; present {
+		t.Errorf(`request body unexpectedly contains "dimensionFilterGroups": %s`, rawBody["dimensionFilterGroups"])
+	}
+}
+
+// TestQuerySearchAnalytics_InvalidFilters_RejectedWithoutHTTPCall table-drives
+// the dimension/operator/expression combinations rejected locally, each of
+// which would otherwise surface as an opaque upstream 400 or silently match
+// nothing.
+func TestQuerySearchAnalytics_InvalidFilters_RejectedWithoutHTTPCall(t *testing.T) {
+	tests := []struct {
+		name   string
+		group  DimensionFilterGroup
+		marker string
+	}{
+		{
+			name:   "unknown dimension",
+			group:  DimensionFilterGroup{Filters: []DimensionFilter{{Dimension: "date", Expression: "2025-01-01"}}},
+			marker: `invalid dimension "date"`,
+		},
+		{
+			name:   "unknown operator",
+			group:  DimensionFilterGroup{Filters: []DimensionFilter{{Dimension: "query", Operator: "startsWith", Expression: "a"}}},
+			marker: `invalid operator "startsWith"`,
+		},
+		{
+			name:   "regex on device",
+			group:  DimensionFilterGroup{Filters: []DimensionFilter{{Dimension: "device", Operator: "includingRegex", Expression: "MOB.*"}}},
+			marker: `operator "includingRegex" is not supported for dimension "device"`,
+		},
+		{
+			name:   "invalid device",
+			group:  DimensionFilterGroup{Filters: []DimensionFilter{{Dimension: "device", Expression: "phone"}}},
+			marker: `invalid device expression "phone"`,
+		},
+		{
+			name:   "two-letter country",
+			group:  DimensionFilterGroup{Filters: []DimensionFilter{{Dimension: "country", Expression: "us"}}},
+			marker: `invalid country expression "us"`,
+		},
+		{
+			name:   "invalid regex",
+			group:  DimensionFilterGroup{Filters: []DimensionFilter{{Dimension: "page", Operator: "includingRegex", Expression: "/blog/(("}}},
+			marker: `invalid regex expression`,
+		},
+		{
+			name:   "empty expression",
+			group:  DimensionFilterGroup{Filters: []DimensionFilter{{Dimension: "query", Expression: "  "}}},
+			marker: "expression must not be empty",
+		},
+		{
+			name:   "or group type",
+			group:  DimensionFilterGroup{GroupType: "or", Filters: []DimensionFilter{{Dimension: "query", Expression: "a"}}},
+			marker: `invalid group_type "or"`,
+		},
+		{
+			name:   "empty group",
+			group:  DimensionFilterGroup{},
+			marker: "filters must not be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callCount := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				callCount++
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`{"rows":[]}`))
+			}))
+			defer srv.Close()
+			defer SetTestAPIBaseURL(srv.URL)()
+
+			client := NewTestClient(srv.Client())
+			_, err := client.QuerySearchAnalytics(
+				context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, 10, "",
+				SearchAnalyticsOptions{DimensionFilterGroups: []DimensionFilterGroup{tt.group}})
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.marker) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.marker)
+			}
+			if !strings.Contains(err.Error(), "dimension_filter_groups[0]") {
+				t.Errorf("error = %q, want it to locate the offending group", err)
+			}
+			if callCount != 0 {
+				t.Errorf("expected 0 HTTP calls for an invalid filter, got %d", callCount)
+			}
+		})
+	}
+}
+
+func assertFilterGroupsEqual(t *testing.T, label string, got, want []DimensionFilterGroup) {
+	t.Helper()
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("%s filter groups = %s, want %s", label, gotJSON, wantJSON)
+	}
+}
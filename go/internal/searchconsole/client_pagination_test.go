@@ -0,0 +1,209 @@
+package searchconsole
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newPagingServer fakes an upstream property holding totalRows rows, honouring
+// startRow and rowLimit the way the real API does, and records every request.
+func newPagingServer(t *testing.T, totalRows int, requests *[]apiSearchAnalyticsRequest) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body apiSearchAnalyticsRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request body: %v", err)
+		}
+		*requests = append(*requests, body)
+
+		rows := []apiSearchAnalyticsRow{}
+		for i := body.StartRow; i < totalRows && i < body.StartRow+body.RowLimit; i++ {
+			rows = append(rows, apiSearchAnalyticsRow{Keys: []string{fmt.Sprintf("q%d", i)}, Clicks: 1, Impressions: 1})
+		}
+		b, _ := json.Marshal(apiSearchAnalyticsResponse{Rows: rows})
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(b)
+	}))
+}
+
+func TestQuerySearchAnalytics_StartRow_SentUpstreamAndEchoed(t *testing.T) {
+	var requests []apiSearchAnalyticsRequest
+	srv := newPagingServer(t, 50, &requests)
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	client := NewTestClient(srv.Client())
+	resp, err := client.QuerySearchAnalytics(
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", []string{"query"}, 10, "",
+		SearchAnalyticsOptions{StartRow: 20})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(requests) != 1 || requests[0].StartRow != 20 {
+		t.Fatalf("requests = %+v, want one request with startRow 20", requests)
+	}
+	if resp.StartRow != 20 {
+		t.Errorf("resp.StartRow = %d, want 20", resp.StartRow)
+	}
+	if resp.Rows[0].Keys[0] != "q20" {
+		t.Errorf("first row = %v, want q20", resp.Rows[0].Keys)
+	}
+	if !resp.Truncated {
+		t.Error("resp.Truncated = false, want true for a full page")
+	}
+}
+
+func TestQuerySearchAnalytics_SinglePage_ShortPageIsNotTruncated(t *testing.T) {
+	var requests []apiSearchAnalyticsRequest
+	srv := newPagingServer(t, 5, &requests)
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	client := NewTestClient(srv.Client())
+	resp, err := client.QuerySearchAnalytics(
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", []string{"query"}, 10, "",
+		SearchAnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Truncated {
+		t.Error("resp.Truncated = true, want false for a short page")
+	}
+}
+
+// TestQuerySearchAnalytics_AllRows_PagesUntilShortPage confirms all-rows mode
+// advances startRow by each page's size and stops on the first short page.
+func TestQuerySearchAnalytics_AllRows_PagesUntilShortPage(t *testing.T) {
+	var requests []apiSearchAnalyticsRequest
+	srv := newPagingServer(t, 25, &requests)
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	client := NewTestClient(srv.Client())
+	resp, err := client.QuerySearchAnalytics(
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", []string{"query"}, 10, "",
+		SearchAnalyticsOptions{AllRows: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("request count = %d, want 3", len(requests))
+	}
+	for i, want := range []int{0, 10, 20} {
+		if requests[i].StartRow != want {
+			t.Errorf("request %d startRow = %d, want %d", i, requests[i].StartRow, want)
+		}
+	}
+	if resp.RowCount != 25 || len(resp.Rows) != 25 {
+		t.Errorf("row count = %d (%d rows), want 25", resp.RowCount, len(resp.Rows))
+	}
+	if resp.Truncated {
+		t.Error("resp.Truncated = true, want false when upstream ran out of rows")
+	}
+}
+
+// TestQuerySearchAnalytics_AllRows_ExactMultipleNeedsEmptyPage covers the
+// boundary where the total is an exact multiple of the page size: only the
+// following empty page proves there are no more rows.
+func TestQuerySearchAnalytics_AllRows_ExactMultipleNeedsEmptyPage(t *testing.T) {
+	var requests []apiSearchAnalyticsRequest
+	srv := newPagingServer(t, 20, &requests)
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	client := NewTestClient(srv.Client())
+	resp, err := client.QuerySearchAnalytics(
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", []string{"query"}, 10, "",
+		SearchAnalyticsOptions{AllRows: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requests) != 3 {
+		t.Errorf("request count = %d, want 3", len(requests))
+	}
+	if resp.RowCount != 20 || resp.Truncated {
+		t.Errorf("rowCount = %d, truncated = %v, want 20 and false", resp.RowCount, resp.Truncated)
+	}
+}
+
+func TestQuerySearchAnalytics_AllRows_StopsAtMaxRowsAndReportsTruncation(t *testing.T) {
+	var requests []apiSearchAnalyticsRequest
+	srv := newPagingServer(t, 100, &requests)
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	client := NewTestClient(srv.Client())
+	resp, err := client.QuerySearchAnalytics(
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", []string{"query"}, 10, "",
+		SearchAnalyticsOptions{AllRows: true, MaxRows: 25})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.RowCount != 25 {
+		t.Errorf("resp.RowCount = %d, want 25", resp.RowCount)
+	}
+	if !resp.Truncated {
+		t.Error("resp.Truncated = false, want true when max_rows was reached")
+	}
+	if last := requests[len(requests)-1]; last.RowLimit != 5 || last.StartRow != 20 {
+		t.Errorf("last request = %+v, want rowLimit 5 at startRow 20", last)
+	}
+}
+
+func TestQuerySearchAnalytics_AllRows_DefaultsPageSizeToUpstreamMaximum(t *testing.T) {
+	var requests []apiSearchAnalyticsRequest
+	srv := newPagingServer(t, 3, &requests)
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	client := NewTestClient(srv.Client())
+	_, err := client.QuerySearchAnalytics(
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", []string{"query"}, 0, "",
+		SearchAnalyticsOptions{AllRows: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests[0].RowLimit != maxRowsPerRequest {
+		t.Errorf("rowLimit = %d, want %d", requests[0].RowLimit, maxRowsPerRequest)
+	}
+}
+
+func TestQuerySearchAnalytics_InvalidPaging_RejectedWithoutHTTPCall(t *testing.T) {
+	tests := []struct {
+		name     string
+		rowLimit int
+		options  SearchAnalyticsOptions
+		marker   string
+	}{
+		{name: "row limit above upstream maximum", rowLimit: 25001, marker: "invalid row_limit 25001"},
+		{name: "negative start row", rowLimit: 10, options: SearchAnalyticsOptions{StartRow: -1}, marker: "invalid start_row -1"},
+		{name: "negative max rows", rowLimit: 10, options: SearchAnalyticsOptions{AllRows: true, MaxRows: -5}, marker: "invalid max_rows -5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []apiSearchAnalyticsRequest
+			srv := newPagingServer(t, 10, &requests)
+			defer srv.Close()
+			defer SetTestAPIBaseURL(srv.URL)()
+
+			client := NewTestClient(srv.Client())
+			_, err := client.QuerySearchAnalytics(
+				context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, tt.rowLimit, "", tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.marker) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.marker)
+			}
+			if len(requests) != 0 {
+				t.Errorf("expected 0 HTTP calls, got %d", len(requests))
+			}
+		})
+	}
+}
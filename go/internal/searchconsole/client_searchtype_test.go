@@ -25,7 +25,7 @@ func TestQuerySearchAnalytics_SearchTypeVideo_SendsTypeVideoUpstream(t *testing.
 
 	client := NewTestClient(srv.Client())
 	_, err := client.QuerySearchAnalytics(
-		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, 10, "video")
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, 10, "video", SearchAnalyticsOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -54,7 +54,7 @@ func TestQuerySearchAnalytics_SearchTypeOmitted_PreservesExistingRequest(t *test
 
 	client := NewTestClient(srv.Client())
 	resp, err := client.QuerySearchAnalytics(
-		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, 10, "")
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, 10, "", SearchAnalyticsOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -82,7 +82,7 @@ func TestQuerySearchAnalytics_SearchTypeWeb_SendsTypeWebExplicitly(t *testing.T)
 
 	client := NewTestClient(srv.Client())
 	resp, err := client.QuerySearchAnalytics(
-		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, 10, "web")
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, 10, "web", SearchAnalyticsOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -112,7 +112,7 @@ func TestQuerySearchAnalytics_AllValidSearchTypes_AreAccepted(t *testing.T) {
 
 			client := NewTestClient(srv.Client())
 			resp, err := client.QuerySearchAnalytics(
-				context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, 10, st)
+				context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, 10, st, SearchAnalyticsOptions{})
 			if err != nil {
 				t.Fatalf("unexpected error for search_type %q: %v", st, err)
 			}
@@ -143,7 +143,7 @@ func TestQuerySearchAnalytics_SearchTypeVideo_ComposesWithDimensions(t *testing.
 
 	client := NewTestClient(srv.Client())
 	resp, err := client.QuerySearchAnalytics(
-		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", []string{"page"}, 10, "video")
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", []string{"page"}, 10, "video", SearchAnalyticsOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -174,7 +174,7 @@ func TestQuerySearchAnalytics_InvalidSearchType_RejectedWithoutHTTPCall(t *testi
 
 	client := NewTestClient(srv.Client())
 	_, err := client.QuerySearchAnalytics(
-		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, 10, "youtube")
+		context.Background(), "devleader.ca", "2025-01-01", "2025-12-31", nil, 10, "youtube", SearchAnalyticsOptions{})
 	if err == nil {
 		t.Fatal("expected an error for invalid search_type, got nil")
 	}
@@ -0,0 +1,154 @@
+package searchconsole
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	// Embed the IANA time zone database so Pacific Time and caller-chosen zones
+	// resolve on hosts without system zoneinfo (e.g. Windows, scratch images).
+	_ "time/tzdata"
+)
+
+const (
+	// dataLagDays is how many days behind today, in Pacific Time, the latest
+	// day of settled Search Console data typically is. Relative ranges end on
+	// that day so they compare like with like.
+	dataLagDays = 2
+
+	// maxRelativeRangeDays bounds a relative range to Search Console's
+	// 16-month retention.
+	maxRelativeRangeDays = 486
+)
+
+// pacificTime is the time zone Search Console uses for day boundaries and
+// hour keys.
+var pacificTime = mustLoadLocation("America/Los_Angeles")
+
+// nowFunc is the clock used for date defaults and relative ranges; overridden
+// in tests.
+var nowFunc = time.Now
+
+var (
+	lastNDaysPattern   = regexp.MustCompile(`^last_(\d+)_days$`)
+	lastNMonthsPattern = regexp.MustCompile(`^last_(\d+)_months$`)
+	dayOffsetPattern   = regexp.MustCompile(`^-(\d+)d$`)
+)
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(fmt.Sprintf("loading time zone %q: %v", name, err))
+	}
+	return loc
+}
+
+// SetTestNow pins the clock used for date defaults and relative ranges,
+// returning a function that restores it. Exported solely for package tests.
+func SetTestNow(now time.Time) (restore func()) {
+	orig := nowFunc
+	nowFunc = func() time.Time { return now }
+	return func() { nowFunc = orig }
+}
+
+// pacificToday returns midnight of the current day in Pacific Time.
+func pacificToday() time.Time {
+	now := nowFunc().In(pacificTime)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, pacificTime)
+}
+
+// LatestCompleteDate returns the latest day, in Pacific Time, that relative
+// ranges treat as having settled data.
+func LatestCompleteDate() time.Time {
+	return pacificToday().AddDate(0, 0, -dataLagDays)
+}
+
+// ResolveDateRange converts a named or relative date range into concrete
+// YYYY-MM-DD start and end dates, using Search Console's Pacific Time day
+// boundary. Supported forms:
+//   - "last_N_days" and "-Nd" -- the N days ending on the latest complete date.
+//   - "last_N_months" -- N calendar months ending on the latest complete date.
+//   - "month_to_date" -- the current month up to the latest complete date.
+//   - "previous_month" -- the whole previous calendar month.
+func ResolveDateRange(spec string) (startDate, endDate string, err error) {
+	spec = strings.TrimSpace(spec)
+	today := pacificToday()
+	latest := today.AddDate(0, 0, -dataLagDays)
+
+	var start, end time.Time
+	switch {
+	case spec == "month_to_date":
+		start = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, pacificTime)
+		end = latest
+		if end.Before(start) {
+			return "", "", fmt.Errorf(
+				"date_range \"month_to_date\" has no complete days yet: the latest complete date is %s; use previous_month instead",
+				latest.Format(time.DateOnly))
+		}
+	case spec == "previous_month":
+		start = time.Date(today.Year(), today.Month()-1, 1, 0, 0, 0, 0, pacificTime)
+		end = time.Date(today.Year(), today.Month(), 0, 0, 0, 0, 0, pacificTime)
+	case lastNDaysPattern.MatchString(spec) || dayOffsetPattern.MatchString(spec):
+		match := lastNDaysPattern.FindStringSubmatch(spec)
+		if match == nil {
+			match = dayOffsetPattern.FindStringSubmatch(spec)
+		}
+		days, convErr := strconv.Atoi(match[1])
+		if convErr != nil || days < 1 || days > maxRelativeRangeDays {
+			return "", "", fmt.Errorf("invalid date_range %q: the day count must be between 1 and %d", spec, maxRelativeRangeDays)
+		}
+		end = latest
+		start = end.AddDate(0, 0, -(days - 1))
+	case lastNMonthsPattern.MatchString(spec):
+		months, convErr := strconv.Atoi(lastNMonthsPattern.FindStringSubmatch(spec)[1])
+		if convErr != nil || months < 1 || months > 16 {
+			return "", "", fmt.Errorf("invalid date_range %q: the month count must be between 1 and 16", spec)
+		}
+		end = latest
+		start = addMonthsClamped(end, -months).AddDate(0, 0, 1)
+	default:
+		return "", "", fmt.Errorf(
+			"invalid date_range %q: must be last_N_days, last_N_months, month_to_date, previous_month, or an offset such as -90d",
+			spec)
+	}
+	return start.Format(time.DateOnly), end.Format(time.DateOnly), nil
+}
+
+// addMonthsClamped adds months to t, clamping the day to the end of the target
+// month instead of overflowing into the next one as time.AddDate does.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	firstOfTarget := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+	return firstOfTarget.AddDate(0, 0, min(t.Day(), lastDay)-1)
+}
+
+// ComparisonDateRange returns the period to compare startDate..endDate
+// against. comparison is "previous_period" for the equally long period ending
+// the day before startDate, or "year_over_year" for the same dates one year
+// earlier (February 29 maps to February 28).
+func ComparisonDateRange(startDate, endDate, comparison string) (string, string, error) {
+	start, err := time.Parse(time.DateOnly, startDate)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid start_date %q: must be YYYY-MM-DD", startDate)
+	}
+	end, err := time.Parse(time.DateOnly, endDate)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid end_date %q: must be YYYY-MM-DD", endDate)
+	}
+	if end.Before(start) {
+		return "", "", fmt.Errorf("start_date %s is after end_date %s", startDate, endDate)
+	}
+
+	switch comparison {
+	case "previous_period":
+		days := int(end.Sub(start).Hours()/24) + 1
+		previousEnd := start.AddDate(0, 0, -1)
+		return previousEnd.AddDate(0, 0, -(days - 1)).Format(time.DateOnly), previousEnd.Format(time.DateOnly), nil
+	case "year_over_year":
+		return addMonthsClamped(start, -12).Format(time.DateOnly), addMonthsClamped(end, -12).Format(time.DateOnly), nil
+	default:
+		return "", "", fmt.Errorf("invalid comparison %q: must be previous_period or year_over_year", comparison)
+	}
+}
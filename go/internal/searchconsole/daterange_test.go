@@ -0,0 +1,138 @@
+package searchconsole_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestResolveDateRange_NamedAndRelativeRanges(t *testing.T) {
+	// 05:00 UTC on March 15 is still March 14 in Pacific Time, so the latest
+	// complete date is March 12.
+	defer searchconsole.SetTestNow(time.Date(2026, 3, 15, 5, 0, 0, 0, time.UTC))()
+
+	tests := []struct {
+		spec      string
+		wantStart string
+		wantEnd   string
+	}{
+		{spec: "last_7_days", wantStart: "2026-03-06", wantEnd: "2026-03-12"},
+		{spec: "last_28_days", wantStart: "2026-02-13", wantEnd: "2026-03-12"},
+		{spec: "-90d", wantStart: "2025-12-13", wantEnd: "2026-03-12"},
+		{spec: "last_3_months", wantStart: "2025-12-13", wantEnd: "2026-03-12"},
+		{spec: "month_to_date", wantStart: "2026-03-01", wantEnd: "2026-03-12"},
+		{spec: "previous_month", wantStart: "2026-02-01", wantEnd: "2026-02-28"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			start, end, err := searchconsole.ResolveDateRange(tt.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("ResolveDateRange(%q) = %s..%s, want %s..%s", tt.spec, start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+// TestResolveDateRange_LastMonths_ClampsToMonthEnd guards against
+// time.AddDate's overflow, which would turn "one month before March 31" into
+// early March instead of the end of February.
+func TestResolveDateRange_LastMonths_ClampsToMonthEnd(t *testing.T) {
+	defer searchconsole.SetTestNow(time.Date(2026, 4, 1, 20, 0, 0, 0, time.UTC))()
+
+	start, end, err := searchconsole.ResolveDateRange("last_1_months")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if start != "2026-03-01" || end != "2026-03-30" {
+		t.Errorf("last_1_months = %s..%s, want 2026-03-01..2026-03-30", start, end)
+	}
+}
+
+func TestResolveDateRange_PreviousMonth_InJanuary_WrapsYear(t *testing.T) {
+	defer searchconsole.SetTestNow(time.Date(2026, 1, 10, 20, 0, 0, 0, time.UTC))()
+
+	start, end, err := searchconsole.ResolveDateRange("previous_month")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if start != "2025-12-01" || end != "2025-12-31" {
+		t.Errorf("previous_month = %s..%s, want 2025-12-01..2025-12-31", start, end)
+	}
+}
+
+func TestResolveDateRange_InvalidSpecs_ReturnActionableErrors(t *testing.T) {
+	defer searchconsole.SetTestNow(time.Date(2026, 3, 2, 20, 0, 0, 0, time.UTC))()
+
+	tests := []struct {
+		spec   string
+		marker string
+	}{
+		{spec: "last_week", marker: "must be last_N_days"},
+		{spec: "last_0_days", marker: "between 1 and 486"},
+		{spec: "-1000d", marker: "between 1 and 486"},
+		{spec: "last_17_months", marker: "between 1 and 16"},
+		{spec: "month_to_date", marker: "has no complete days yet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, _, err := searchconsole.ResolveDateRange(tt.spec)
+			if err == nil || !strings.Contains(err.Error(), tt.marker) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.marker)
+			}
+		})
+	}
+}
+
+func TestComparisonDateRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		start, end string
+		comparison string
+		wantStart  string
+		wantEnd    string
+	}{
+		{name: "previous week", start: "2026-03-09", end: "2026-03-15", comparison: "previous_period", wantStart: "2026-03-02", wantEnd: "2026-03-08"},
+		{name: "previous period across month", start: "2026-03-01", end: "2026-03-31", comparison: "previous_period", wantStart: "2026-01-29", wantEnd: "2026-02-28"},
+		{name: "single day", start: "2026-03-01", end: "2026-03-01", comparison: "previous_period", wantStart: "2026-02-28", wantEnd: "2026-02-28"},
+		{name: "year over year", start: "2026-03-01", end: "2026-03-31", comparison: "year_over_year", wantStart: "2025-03-01", wantEnd: "2025-03-31"},
+		{name: "year over year leap day", start: "2024-02-29", end: "2024-02-29", comparison: "year_over_year", wantStart: "2023-02-28", wantEnd: "2023-02-28"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := searchconsole.ComparisonDateRange(tt.start, tt.end, tt.comparison)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("got %s..%s, want %s..%s", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestComparisonDateRange_InvalidInput(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		start, end, comparison, marker string
+	}{
+		{start: "2026-03-01", end: "2026-03-31", comparison: "last_year", marker: `invalid comparison "last_year"`},
+		{start: "03/01/2026", end: "2026-03-31", comparison: "previous_period", marker: "invalid start_date"},
+		{start: "2026-03-31", end: "2026-03-01", comparison: "previous_period", marker: "is after end_date"},
+	} {
+		_, _, err := searchconsole.ComparisonDateRange(tt.start, tt.end, tt.comparison)
+		if err == nil || !strings.Contains(err.Error(), tt.marker) {
+			t.Errorf("ComparisonDateRange(%q, %q, %q) error = %v, want %q", tt.start, tt.end, tt.comparison, err, tt.marker)
+		}
+	}
+}
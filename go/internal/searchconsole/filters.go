@@ -0,0 +1,147 @@
+package searchconsole
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// defaultFilterOperator and defaultFilterGroupType mirror the upstream
+	// API's own defaults for an omitted operator and groupType.
+	defaultFilterOperator  = "equals"
+	defaultFilterGroupType = "and"
+)
+
+// DimensionFilter restricts a search analytics query to rows whose value for
+// Dimension matches Expression under Operator.
+type DimensionFilter struct {
+	Dimension  string `json:"dimension"`
+	Operator   string `json:"operator"`
+	Expression string `json:"expression"`
+}
+
+// DimensionFilterGroup is a set of filters combined by GroupType. The upstream
+// API currently supports only "and".
+type DimensionFilterGroup struct {
+	GroupType string            `json:"groupType"`
+	Filters   []DimensionFilter `json:"filters"`
+}
+
+// filterOperatorsByDimension lists, for each filterable dimension, the operators
+// that produce a meaningful upstream request. country, device, and
+// searchAppearance hold enumerated codes, so substring and regex matching
+// against them is rejected rather than silently matching nothing.
+var filterOperatorsByDimension = map[string]map[string]bool{
+	"query": {
+		"equals": true, "notEquals": true, "contains": true, "notContains": true,
+		"includingRegex": true, "excludingRegex": true,
+	},
+	"page": {
+		"equals": true, "notEquals": true, "contains": true, "notContains": true,
+		"includingRegex": true, "excludingRegex": true,
+	},
+	"country":          {"equals": true, "notEquals": true},
+	"device":           {"equals": true, "notEquals": true},
+	"searchAppearance": {"equals": true, "notEquals": true},
+}
+
+// validFilterDevices are the upstream device codes accepted in a device filter.
+var validFilterDevices = map[string]bool{
+	"DESKTOP": true,
+	"MOBILE":  true,
+	"TABLET":  true,
+}
+
+// countryCodePattern matches an ISO 3166-1 alpha-3 code, which is the form the
+// upstream API expects in a country filter.
+var countryCodePattern = regexp.MustCompile(`^[A-Za-z]{3}$`)
+
+// normalizeFilterGroups validates groups and returns a copy with upstream
+// defaults (groupType "and", operator "equals") made explicit, so the outbound
+// request and the echoed response agree on exactly what was applied.
+func normalizeFilterGroups(groups []DimensionFilterGroup) ([]DimensionFilterGroup, error) {
+	if len(groups) == 0 {
+		return nil, nil
+	}
+
+	normalized := make([]DimensionFilterGroup, len(groups))
+	for i, group := range groups {
+		groupType := strings.TrimSpace(group.GroupType)
+		if groupType == "" {
+			groupType = defaultFilterGroupType
+		}
+		if groupType != defaultFilterGroupType {
+			return nil, fmt.Errorf(
+				"dimension_filter_groups[%d]: invalid group_type %q: must be \"and\"", i, group.GroupType)
+		}
+		if len(group.Filters) == 0 {
+			return nil, fmt.Errorf("dimension_filter_groups[%d]: filters must not be empty", i)
+		}
+
+		filters := make([]DimensionFilter, len(group.Filters))
+		for j, filter := range group.Filters {
+			normalizedFilter, err := normalizeFilter(filter)
+			if err != nil {
+				return nil, fmt.Errorf("dimension_filter_groups[%d].filters[%d]: %w", i, j, err)
+			}
+			filters[j] = normalizedFilter
+		}
+		normalized[i] = DimensionFilterGroup{GroupType: groupType, Filters: filters}
+	}
+	return normalized, nil
+}
+
+func normalizeFilter(filter DimensionFilter) (DimensionFilter, error) {
+	dimension := strings.TrimSpace(filter.Dimension)
+	operators, ok := filterOperatorsByDimension[dimension]
+	if !ok {
+		return DimensionFilter{}, fmt.Errorf(
+			"invalid dimension %q: must be one of query, page, country, device, searchAppearance", filter.Dimension)
+	}
+
+	operator := strings.TrimSpace(filter.Operator)
+	if operator == "" {
+		operator = defaultFilterOperator
+	}
+	if !operators[operator] {
+		if dimension == "query" || dimension == "page" {
+			return DimensionFilter{}, fmt.Errorf(
+				"invalid operator %q: must be one of equals, notEquals, contains, notContains, includingRegex, excludingRegex",
+				filter.Operator)
+		}
+		return DimensionFilter{}, fmt.Errorf(
+			"operator %q is not supported for dimension %q: use equals or notEquals", operator, dimension)
+	}
+
+	expression := filter.Expression
+	if strings.TrimSpace(expression) == "" {
+		return DimensionFilter{}, fmt.Errorf("expression must not be empty")
+	}
+
+	switch dimension {
+	case "country":
+		if !countryCodePattern.MatchString(expression) {
+			return DimensionFilter{}, fmt.Errorf(
+				"invalid country expression %q: must be an ISO 3166-1 alpha-3 code such as \"usa\"", expression)
+		}
+		expression = strings.ToLower(expression)
+	case "device":
+		upper := strings.ToUpper(expression)
+		if !validFilterDevices[upper] {
+			return DimensionFilter{}, fmt.Errorf(
+				"invalid device expression %q: must be one of DESKTOP, MOBILE, TABLET", expression)
+		}
+		expression = upper
+	}
+
+	if operator == "includingRegex" || operator == "excludingRegex" {
+		// Upstream evaluates these with RE2, the same syntax as Go's regexp package,
+		// so a pattern that fails to compile here would also fail upstream.
+		if _, err := regexp.Compile(expression); err != nil {
+			return DimensionFilter{}, fmt.Errorf("invalid regex expression %q: %w", expression, err)
+		}
+	}
+
+	return DimensionFilter{Dimension: dimension, Operator: operator, Expression: expression}, nil
+}
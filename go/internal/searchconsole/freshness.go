@@ -0,0 +1,147 @@
+package searchconsole
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Codes of the warnings attached to search analytics responses.
+const (
+	// WarningProvisionalRange means the range ends after the latest date
+	// Search Console usually has final data for.
+	WarningProvisionalRange = "provisional_range"
+
+	// WarningFreshData means the response includes fresh data, which
+	// Search Console may still revise.
+	WarningFreshData = "fresh_data"
+
+	// WarningOutsideRetention means the range started before the oldest day
+	// Search Console still keeps data for, and was moved forward to it.
+	WarningOutsideRetention = "outside_retention"
+
+	// WarningFinalDataDelayed means final data ends earlier than usual.
+	WarningFinalDataDelayed = "final_data_delayed"
+
+	// WarningNoRecentData means no recent day has any data at all.
+	WarningNoRecentData = "no_recent_data"
+)
+
+// freshnessProbeDays is how many days, ending today in Pacific Time,
+// GetDataFreshness looks back for the latest day with data.
+const freshnessProbeDays = 10
+
+// DataWarning flags that some of a response's data is incomplete or may
+// still change. Code is stable for programmatic checks; Message explains it.
+type DataWarning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// DataFreshness reports how recent a property's data is. LatestFinalDate is
+// the latest day with final data, and LatestFreshDate the latest day with any
+// data; ProvisionalDays counts the days between them, whose numbers may still
+// change. ExpectedFinalDate is the latest day final data usually covers,
+// which relative date ranges end on. Either latest date is empty when no day
+// in the probed window has data.
+type DataFreshness struct {
+	SiteURL           string        `json:"siteUrl"`
+	SearchType        string        `json:"searchType"`
+	LatestFinalDate   string        `json:"latestFinalDate,omitempty"`
+	LatestFreshDate   string        `json:"latestFreshDate,omitempty"`
+	ProvisionalDays   int           `json:"provisionalDays"`
+	ExpectedFinalDate string        `json:"expectedFinalDate"`
+	Warnings          []DataWarning `json:"warnings,omitempty"`
+	QueriedAt         time.Time     `json:"queriedAt"`
+}
+
+// dataWarnings returns the warnings for a query of data from startDate to
+// endDate in dataState.
+func dataWarnings(startDate, endDate, dataState string) []DataWarning {
+	var warnings []DataWarning
+	if earliest := EarliestRetainedDate().Format(time.DateOnly); startDate < earliest {
+		warnings = append(warnings, DataWarning{
+			Code: WarningOutsideRetention,
+			Message: fmt.Sprintf(
+				"start date %s is older than Search Console's %d-month retention; the range was clamped to start on %s",
+				startDate, retentionMonths, earliest),
+		})
+	}
+	if latest := LatestCompleteDate().Format(time.DateOnly); endDate > latest {
+		warnings = append(warnings, DataWarning{
+			Code: WarningProvisionalRange,
+			Message: fmt.Sprintf(
+				"end date %s is after %s, the latest date Search Console usually has final data for; the last days may be missing or incomplete",
+				endDate, latest),
+		})
+	}
+	if dataState != "" && dataState != defaultDataState {
+		warnings = append(warnings, DataWarning{
+			Code:    WarningFreshData,
+			Message: fmt.Sprintf("data_state %s includes fresh data, which Search Console may still revise", dataState),
+		})
+	}
+	return warnings
+}
+
+// GetDataFreshness probes the last few days of a property's data, once for
+// final data and once for all data, to find the latest day each covers.
+func (c *Client) GetDataFreshness(ctx context.Context, siteURL, searchType string) (*DataFreshness, error) {
+	if searchType != "" && !validSearchTypes[searchType] {
+		return nil, fmt.Errorf(
+			"invalid search_type %q: must be one of web, image, video, news, discover, googleNews", searchType)
+	}
+	today := pacificToday()
+	startDate := today.AddDate(0, 0, -(freshnessProbeDays - 1)).Format(time.DateOnly)
+	endDate := today.Format(time.DateOnly)
+
+	return withResolvedSiteURL(ctx, c, siteURL, func(resolved string) (*DataFreshness, error) {
+		latest := make(map[string]string, 2)
+		var effectiveSearchType string
+		for _, dataState := range []string{defaultDataState, "all"} {
+			resp, err := c.querySearchAnalyticsWithURL(
+				ctx, resolved, startDate, endDate, []string{"date"}, freshnessProbeDays, searchType,
+				SearchAnalyticsOptions{DataState: dataState})
+			if err != nil {
+				return nil, err
+			}
+			effectiveSearchType = resp.SearchType
+			for _, row := range resp.Rows {
+				if len(row.Keys) == 1 && row.Keys[0] > latest[dataState] {
+					latest[dataState] = row.Keys[0]
+				}
+			}
+		}
+
+		freshness := &DataFreshness{
+			SiteURL:           resolved,
+			SearchType:        effectiveSearchType,
+			LatestFinalDate:   latest[defaultDataState],
+			LatestFreshDate:   latest["all"],
+			ExpectedFinalDate: LatestCompleteDate().Format(time.DateOnly),
+			QueriedAt:         time.Now().UTC(),
+		}
+		switch {
+		case freshness.LatestFinalDate == "" && freshness.LatestFreshDate == "":
+			freshness.Warnings = append(freshness.Warnings, DataWarning{
+				Code:    WarningNoRecentData,
+				Message: fmt.Sprintf("no data between %s and %s", startDate, endDate),
+			})
+		case freshness.LatestFinalDate < freshness.ExpectedFinalDate:
+			message := fmt.Sprintf("no final data in the last %d days", freshnessProbeDays)
+			if freshness.LatestFinalDate != "" {
+				message = fmt.Sprintf("final data ends on %s, before the usual %s", freshness.LatestFinalDate, freshness.ExpectedFinalDate)
+			}
+			freshness.Warnings = append(freshness.Warnings, DataWarning{
+				Code:    WarningFinalDataDelayed,
+				Message: message + "; relative date ranges may end on incomplete days",
+			})
+		}
+		if freshness.LatestFinalDate != "" && freshness.LatestFreshDate > freshness.LatestFinalDate {
+			final, _ := time.Parse(time.DateOnly, freshness.LatestFinalDate)
+			fresh, _ := time.Parse(time.DateOnly, freshness.LatestFreshDate)
+			freshness.ProvisionalDays = int(fresh.Sub(final).Hours() / 24)
+		}
+		return freshness, nil
+	})
+}
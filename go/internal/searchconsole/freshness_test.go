@@ -0,0 +1,124 @@
+package searchconsole
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestQuerySearchAnalytics_Warnings_FlagProvisionalRangeAndFreshData(t *testing.T) {
+	defer SetTestNow(time.Date(2026, 3, 15, 20, 0, 0, 0, time.UTC))()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+	client := NewTestClient(srv.Client())
+
+	for _, tc := range []struct {
+		endDate   string
+		dataState string
+		want      []string
+	}{
+		{endDate: "2026-03-13", want: nil},
+		{endDate: "2026-03-14", want: []string{WarningProvisionalRange}},
+		{endDate: "2026-03-13", dataState: "all", want: []string{WarningFreshData}},
+		{endDate: "2026-03-15", dataState: "all", want: []string{WarningProvisionalRange, WarningFreshData}},
+	} {
+		resp, err := client.QuerySearchAnalytics(
+			context.Background(), "devleader.ca", "2026-03-01", tc.endDate, []string{"date"}, 0, "",
+			SearchAnalyticsOptions{DataState: tc.dataState})
+		if err != nil {
+			t.Fatalf("QuerySearchAnalytics: %v", err)
+		}
+		var codes []string
+		for _, w := range resp.Warnings {
+			codes = append(codes, w.Code)
+			if w.Message == "" {
+				t.Errorf("warning %s has no message", w.Code)
+			}
+		}
+		if !slices.Equal(codes, tc.want) {
+			t.Errorf("end %s, data_state %q: warnings = %v, want %v", tc.endDate, tc.dataState, codes, tc.want)
+		}
+	}
+}
+
+func TestGetDataFreshness_ReportsLatestFinalAndFreshDates(t *testing.T) {
+	defer SetTestNow(time.Date(2026, 3, 15, 20, 0, 0, 0, time.UTC))()
+	var bodies []apiSearchAnalyticsRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body apiSearchAnalyticsRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		if body.DataState == "all" {
+			_, _ = w.Write([]byte(`{"rows":[{"keys":["2026-03-15"],"clicks":1},{"keys":["2026-03-11"],"clicks":9}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"rows":[{"keys":["2026-03-10"],"clicks":8},{"keys":["2026-03-11"],"clicks":9}]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	got, err := NewTestClient(srv.Client()).GetDataFreshness(context.Background(), "devleader.ca", "")
+	if err != nil {
+		t.Fatalf("GetDataFreshness: %v", err)
+	}
+	if len(bodies) != 2 || bodies[0].StartDate != "2026-03-06" || bodies[0].EndDate != "2026-03-15" ||
+		len(bodies[0].Dimensions) != 1 || bodies[0].Dimensions[0] != "date" {
+		t.Errorf("requests = %+v, want two date probes of 2026-03-06..2026-03-15", bodies)
+	}
+	if got.LatestFinalDate != "2026-03-11" || got.LatestFreshDate != "2026-03-15" || got.ProvisionalDays != 4 {
+		t.Errorf("freshness = %+v, want final 2026-03-11, fresh 2026-03-15, 4 provisional days", got)
+	}
+	if got.ExpectedFinalDate != "2026-03-13" || got.SearchType != "web" {
+		t.Errorf("freshness = %+v, want expected final date 2026-03-13 and search type web", got)
+	}
+	if len(got.Warnings) != 1 || got.Warnings[0].Code != WarningFinalDataDelayed {
+		t.Errorf("warnings = %+v, want final_data_delayed", got.Warnings)
+	}
+}
+
+func TestGetDataFreshness_NoRows_WarnsNoRecentData(t *testing.T) {
+	defer SetTestNow(time.Date(2026, 3, 15, 20, 0, 0, 0, time.UTC))()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	got, err := NewTestClient(srv.Client()).GetDataFreshness(context.Background(), "devleader.ca", "")
+	if err != nil {
+		t.Fatalf("GetDataFreshness: %v", err)
+	}
+	if got.LatestFinalDate != "" || got.LatestFreshDate != "" || len(got.Warnings) != 1 || got.Warnings[0].Code != WarningNoRecentData {
+		t.Errorf("freshness = %+v, want no dates and no_recent_data", got)
+	}
+}
+
+func TestQueryHourlyPerformance_CarriesWarnings(t *testing.T) {
+	defer SetTestNow(time.Date(2026, 3, 15, 20, 0, 0, 0, time.UTC))()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+	client := NewTestClient(srv.Client())
+
+	resp, err := client.QueryHourlyPerformance(
+		context.Background(), "devleader.ca", "", "", nil, "", "", SearchAnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("QueryHourlyPerformance: %v", err)
+	}
+	var codes []string
+	for _, w := range resp.Warnings {
+		codes = append(codes, w.Code)
+	}
+	if want := []string{WarningProvisionalRange, WarningFreshData}; !slices.Equal(codes, want) {
+		t.Errorf("warnings = %v, want %v", codes, want)
+	}
+}
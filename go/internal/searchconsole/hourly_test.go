@@ -0,0 +1,174 @@
+package searchconsole
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryHourlyPerformance_RequestsHourDimensionWithHourlyAll(t *testing.T) {
+	var gotBody apiSearchAnalyticsRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	client := NewTestClient(srv.Client())
+	resp, err := client.QueryHourlyPerformance(
+		context.Background(), "devleader.ca", "2025-06-09", "2025-06-10", []string{"device"}, "", "", SearchAnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotBody.DataState != "hourly_all" {
+		t.Errorf("request dataState = %q, want hourly_all", gotBody.DataState)
+	}
+	if len(gotBody.Dimensions) != 2 || gotBody.Dimensions[0] != "hour" || gotBody.Dimensions[1] != "device" {
+		t.Errorf("request dimensions = %v, want [hour device]", gotBody.Dimensions)
+	}
+	if resp.Timezone != "UTC" {
+		t.Errorf("resp.Timezone = %q, want UTC default", resp.Timezone)
+	}
+}
+
+// TestQueryHourlyPerformance_ConvertsAndOrdersHours confirms Pacific hour keys
+// are converted to the caller's zone, that the original is kept, and that rows
+// come back in time order rather than upstream's clicks order.
+func TestQueryHourlyPerformance_ConvertsAndOrdersHours(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"rows":[
+			{"keys":["2025-06-10T10:00:00-07:00","MOBILE"],"clicks":9,"impressions":90,"ctr":0.1,"position":2},
+			{"keys":["2025-06-10T09:00:00-07:00","DESKTOP"],"clicks":3,"impressions":30,"ctr":0.1,"position":4}
+		]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	client := NewTestClient(srv.Client())
+	resp, err := client.QueryHourlyPerformance(
+		context.Background(), "devleader.ca", "2025-06-09", "2025-06-10", []string{"device"}, "", "Asia/Tokyo",
+		SearchAnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.Rows) != 2 {
+		t.Fatalf("row count = %d, want 2", len(resp.Rows))
+	}
+	first := resp.Rows[0]
+	if first.Hour != "2025-06-11T01:00:00+09:00" {
+		t.Errorf("first hour = %q, want 2025-06-11T01:00:00+09:00", first.Hour)
+	}
+	if first.HourPacific != "2025-06-10T09:00:00-07:00" {
+		t.Errorf("first hourPacific = %q, want 2025-06-10T09:00:00-07:00", first.HourPacific)
+	}
+	if len(first.Keys) != 1 || first.Keys[0] != "DESKTOP" {
+		t.Errorf("first keys = %v, want [DESKTOP]", first.Keys)
+	}
+}
+
+func TestQueryHourlyPerformance_OmittedDates_DefaultToLastThreePacificDays(t *testing.T) {
+	restore := nowFunc
+	// 03:00 UTC on June 11 is still June 10 in Pacific Time.
+	nowFunc = func() time.Time { return time.Date(2025, 6, 11, 3, 0, 0, 0, time.UTC) }
+	defer func() { nowFunc = restore }()
+
+	var gotBody apiSearchAnalyticsRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	client := NewTestClient(srv.Client())
+	_, err := client.QueryHourlyPerformance(
+		context.Background(), "devleader.ca", "", "", nil, "", "", SearchAnalyticsOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotBody.StartDate != "2025-06-08" || gotBody.EndDate != "2025-06-10" {
+		t.Errorf("request dates = %s..%s, want 2025-06-08..2025-06-10", gotBody.StartDate, gotBody.EndDate)
+	}
+}
+
+func TestQueryHourlyPerformance_InvalidInput_RejectedWithoutHTTPCall(t *testing.T) {
+	tests := []struct {
+		name       string
+		dimensions []string
+		timezone   string
+		marker     string
+	}{
+		{name: "unknown timezone", timezone: "Mars/Olympus", marker: `invalid timezone "Mars/Olympus"`},
+		{name: "hour in dimensions", dimensions: []string{"hour"}, marker: "dimensions must not include hour"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callCount := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				callCount++
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`{"rows":[]}`))
+			}))
+			defer srv.Close()
+			defer SetTestAPIBaseURL(srv.URL)()
+
+			client := NewTestClient(srv.Client())
+			_, err := client.QueryHourlyPerformance(
+				context.Background(), "devleader.ca", "", "", tt.dimensions, "", tt.timezone, SearchAnalyticsOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.marker) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.marker)
+			}
+			if callCount != 0 {
+				t.Errorf("expected 0 HTTP calls, got %d", callCount)
+			}
+		})
+	}
+}
+
+// TestQuerySearchAnalytics_HourDimension_RequiresHourlyAll confirms the
+// hour/hourly_all pairing upstream requires is enforced in both directions.
+func TestQuerySearchAnalytics_HourDimension_RequiresHourlyAll(t *testing.T) {
+	client := NewTestClient(http.DefaultClient)
+
+	_, err := client.QuerySearchAnalytics(
+		context.Background(), "devleader.ca", "2025-06-09", "2025-06-10", []string{"hour"}, 10, "", SearchAnalyticsOptions{})
+	if err == nil || !strings.Contains(err.Error(), `the hour dimension requires data_state "hourly_all"`) {
+		t.Errorf("hour without hourly_all: error = %v", err)
+	}
+
+	_, err = client.QuerySearchAnalytics(
+		context.Background(), "devleader.ca", "2025-06-09", "2025-06-10", []string{"query"}, 10, "",
+		SearchAnalyticsOptions{DataState: "hourly_all"})
+	if err == nil || !strings.Contains(err.Error(), `data_state "hourly_all" requires the hour dimension`) {
+		t.Errorf("hourly_all without hour: error = %v", err)
+	}
+}
+
+func TestParseHourKey_AcceptsOffsetAndBarePacificForms(t *testing.T) {
+	t.Parallel()
+	want := time.Date(2025, 6, 10, 16, 0, 0, 0, time.UTC)
+	for _, key := range []string{"2025-06-10T09:00:00-07:00", "2025-06-10T09:00:00"} {
+		got, err := parseHourKey(key)
+		if err != nil {
+			t.Errorf("parseHourKey(%q): %v", key, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("parseHourKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+	if _, err := parseHourKey("yesterday"); err == nil {
+		t.Error("parseHourKey(\"yesterday\") returned nil error")
+	}
+}
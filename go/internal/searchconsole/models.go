@@ -10,25 +10,135 @@ import (
 	"time"
 )
 
-// SearchAnalyticsRow is a single row from a search analytics query.
+// SearchAnalyticsRow is a single row from a search analytics query. Segment
+// is set only when the row has been classified into a segment, such as
+// branded or non-branded, and Intent only when its query has been classified
+// by search intent. Filled marks a zero row inserted for a date Search
+// Console returned no row for.
 type SearchAnalyticsRow struct {
 	Keys        []string `json:"keys,omitempty"`
 	Clicks      float64  `json:"clicks"`
 	Impressions float64  `json:"impressions"`
 	CTR         float64  `json:"ctr"`
 	Position    float64  `json:"position"`
+	Segment     string   `json:"segment,omitempty"`
+	Intent      string   `json:"intent,omitempty"`
+	Filled      bool     `json:"filled,omitempty"`
+}
+
+// SegmentTotals aggregates the rows of one segment: clicks and impressions
+// summed, CTR recomputed from the sums, and position weighted by impressions.
+type SegmentTotals struct {
+	Segment     string  `json:"segment"`
+	RowCount    int     `json:"rowCount"`
+	Clicks      float64 `json:"clicks"`
+	Impressions float64 `json:"impressions"`
+	CTR         float64 `json:"ctr"`
+	Position    float64 `json:"position"`
 }
 
 // SearchAnalyticsResponse is the parsed result of a search analytics query.
+// DateRange is the named or relative range StartDate and EndDate were resolved
+// from, when the caller supplied one. Truncated reports that the row limit (or,
+// when paging through all rows, the row cap) was reached, so upstream may hold
+// further rows. Warnings flags data that may be incomplete or still
+// changing. Segment names the segmentation applied to Rows, if any, and
+// Segments holds the totals of each segment. Intents likewise holds the
+// totals of each search intent when rows have been classified by intent.
+// Summary is set when Rows was cut down to fit an output budget.
 type SearchAnalyticsResponse struct {
-	SiteURL    string               `json:"siteUrl"`
-	StartDate  string               `json:"startDate"`
-	EndDate    string               `json:"endDate"`
-	Dimensions []string             `json:"dimensions,omitempty"`
-	SearchType string               `json:"searchType"`
-	RowCount   int                  `json:"rowCount"`
-	Rows       []SearchAnalyticsRow `json:"rows"`
-	QueriedAt  time.Time            `json:"queriedAt"`
+	SiteURL               string                 `json:"siteUrl"`
+	StartDate             string                 `json:"startDate"`
+	EndDate               string                 `json:"endDate"`
+	DateRange             string                 `json:"dateRange,omitempty"`
+	Dimensions            []string               `json:"dimensions,omitempty"`
+	SearchType            string                 `json:"searchType"`
+	AggregationType       string                 `json:"aggregationType,omitempty"`
+	DataState             string                 `json:"dataState,omitempty"`
+	DimensionFilterGroups []DimensionFilterGroup `json:"dimensionFilterGroups,omitempty"`
+	StartRow              int                    `json:"startRow,omitempty"`
+	RowCount              int                    `json:"rowCount"`
+	Truncated             bool                   `json:"truncated"`
+	Warnings              []DataWarning          `json:"warnings,omitempty"`
+	Segment               string                 `json:"segment,omitempty"`
+	Segments              []SegmentTotals        `json:"segments,omitempty"`
+	Intents               []SegmentTotals        `json:"intents,omitempty"`
+	Summary               *RowSummary            `json:"summary,omitempty"`
+	Rows                  []SearchAnalyticsRow   `json:"rows"`
+	QueriedAt             time.Time              `json:"queriedAt"`
+}
+
+// RowSummary reports that a response was summarized: only the top RowsShown
+// rows by SortedBy were kept. Other aggregates the RowsOmitted rows left out
+// and Totals aggregates every row, both the way Search Console does.
+type RowSummary struct {
+	Summarized      bool               `json:"summarized"`
+	MaxOutputTokens int                `json:"maxOutputTokens,omitempty"`
+	SortedBy        string             `json:"sortedBy"`
+	RowsShown       int                `json:"rowsShown"`
+	RowsOmitted     int                `json:"rowsOmitted"`
+	Other           SearchAnalyticsRow `json:"other"`
+	Totals          SearchAnalyticsRow `json:"totals"`
+}
+
+// NamedRow is a SearchAnalyticsRow with its keys in fields named after their
+// dimensions. Fields for dimensions the query did not request are empty.
+type NamedRow struct {
+	Query            string  `json:"query,omitempty"`
+	Page             string  `json:"page,omitempty"`
+	Country          string  `json:"country,omitempty"`
+	Device           string  `json:"device,omitempty"`
+	Date             string  `json:"date,omitempty"`
+	Hour             string  `json:"hour,omitempty"`
+	SearchAppearance string  `json:"searchAppearance,omitempty"`
+	Clicks           float64 `json:"clicks"`
+	Impressions      float64 `json:"impressions"`
+	CTR              float64 `json:"ctr"`
+	Position         float64 `json:"position"`
+	Segment          string  `json:"segment,omitempty"`
+	Intent           string  `json:"intent,omitempty"`
+	Filled           bool    `json:"filled,omitempty"`
+}
+
+// NamedRows returns r's rows with each key moved into the field for its
+// dimension, so callers need not track which position of Keys holds which
+// dimension.
+func (r *SearchAnalyticsResponse) NamedRows() []NamedRow {
+	rows := make([]NamedRow, len(r.Rows))
+	for i, row := range r.Rows {
+		named := NamedRow{
+			Clicks:      row.Clicks,
+			Impressions: row.Impressions,
+			CTR:         row.CTR,
+			Position:    row.Position,
+			Segment:     row.Segment,
+			Intent:      row.Intent,
+			Filled:      row.Filled,
+		}
+		for j, key := range row.Keys {
+			if j >= len(r.Dimensions) {
+				break
+			}
+			switch r.Dimensions[j] {
+			case "query":
+				named.Query = key
+			case "page":
+				named.Page = key
+			case "country":
+				named.Country = key
+			case "device":
+				named.Device = key
+			case "date":
+				named.Date = key
+			case "hour":
+				named.Hour = key
+			case "searchAppearance":
+				named.SearchAppearance = key
+			}
+		}
+		rows[i] = named
+	}
+	return rows
 }
 
 // Site represents a Search Console property.
@@ -126,11 +236,15 @@ type apiSitemapListResponse struct {
 }
 
 type apiSearchAnalyticsRequest struct {
-	StartDate  string   `json:"startDate"`
-	EndDate    string   `json:"endDate"`
-	Dimensions []string `json:"dimensions,omitempty"`
-	Type       string   `json:"type,omitempty"`
-	RowLimit   int      `json:"rowLimit,omitempty"`
+	StartDate             string                 `json:"startDate"`
+	EndDate               string                 `json:"endDate"`
+	Dimensions            []string               `json:"dimensions,omitempty"`
+	Type                  string                 `json:"type,omitempty"`
+	DimensionFilterGroups []DimensionFilterGroup `json:"dimensionFilterGroups,omitempty"`
+	AggregationType       string                 `json:"aggregationType,omitempty"`
+	DataState             string                 `json:"dataState,omitempty"`
+	RowLimit              int                    `json:"rowLimit,omitempty"`
+	StartRow              int                    `json:"startRow,omitempty"`
 }
 
 type apiSearchAnalyticsRow struct {
@@ -35,3 +35,43 @@ func TestSearchAnalyticsResponse_RowCount(t *testing.T) {
 		t.Errorf("RowCount = %d, want %d", r.RowCount, len(r.Rows))
 	}
 }
+
+func TestSearchAnalyticsResponse_NamedRows_MapsKeysByDimension(t *testing.T) {
+	t.Parallel()
+	r := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"page", "query", "searchAppearance"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			{Keys: []string{"https://example.com/a", "blazor", "VIDEO"}, Clicks: 10, Impressions: 100, CTR: 0.1, Position: 3.5, Segment: "branded"},
+			{Keys: []string{"https://example.com/b"}, Clicks: 1, Impressions: 20},
+		},
+	}
+
+	got := r.NamedRows()
+
+	want := searchconsole.NamedRow{
+		Query: "blazor", Page: "https://example.com/a", SearchAppearance: "VIDEO",
+		Clicks: 10, Impressions: 100, CTR: 0.1, Position: 3.5, Segment: "branded",
+	}
+	if len(got) != 2 || got[0] != want {
+		t.Fatalf("NamedRows()[0] = %+v, want %+v", got[0], want)
+	}
+	if got[1].Page != "https://example.com/b" || got[1].Query != "" {
+		t.Errorf("NamedRows()[1] = %+v, want only page set", got[1])
+	}
+}
+
+func TestSearchAnalyticsResponse_NamedRows_MapsHour(t *testing.T) {
+	t.Parallel()
+	r := &searchconsole.SearchAnalyticsResponse{
+		Dimensions: []string{"hour", "device"},
+		Rows: []searchconsole.SearchAnalyticsRow{
+			{Keys: []string{"2026-03-10T14:00:00-07:00", "MOBILE"}, Clicks: 3, Impressions: 40},
+		},
+	}
+
+	got := r.NamedRows()
+
+	if len(got) != 1 || got[0].Hour != "2026-03-10T14:00:00-07:00" || got[0].Device != "MOBILE" {
+		t.Errorf("NamedRows() = %+v, want hour and device set", got)
+	}
+}
@@ -0,0 +1,102 @@
+package searchconsole
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	// DefaultSiteConcurrency is how many properties QueryMultipleSites
+	// queries at once when the caller does not choose.
+	DefaultSiteConcurrency = 4
+
+	// maxSiteConcurrency bounds QueryMultipleSites' parallelism to stay well
+	// inside Search Console's per-user request quota.
+	maxSiteConcurrency = 10
+)
+
+// SiteQueryResult is one property's outcome in a multi-site query: its
+// response, or the error that stopped it. When the query has dimensions,
+// TotalsResponse is the same query without them, whose single row is the
+// property's totals including rows past the row limit and anonymized
+// queries.
+type SiteQueryResult struct {
+	SiteURL        string
+	Response       *SearchAnalyticsResponse
+	TotalsResponse *SearchAnalyticsResponse
+	Err            error
+}
+
+// QueryMultipleSites runs the same search analytics query against each of
+// siteURLs, or every property ListSites returns when siteURLs is empty, with
+// at most concurrency queries in flight (zero means
+// DefaultSiteConcurrency). Results are in the order of the properties; one
+// property failing is reported in its result rather than failing the batch.
+// A query with dimensions is followed by one without them for each property,
+// so its totals do not depend on which rows were returned.
+// The returned error is set only when no property can be queried: the query
+// itself is invalid or listing properties failed.
+func (c *Client) QueryMultipleSites(
+	ctx context.Context,
+	siteURLs []string,
+	startDate, endDate string,
+	dimensions []string,
+	rowLimit int,
+	searchType string,
+	options SearchAnalyticsOptions,
+	concurrency int,
+) ([]SiteQueryResult, error) {
+	if concurrency == 0 {
+		concurrency = DefaultSiteConcurrency
+	}
+	if concurrency < 1 || concurrency > maxSiteConcurrency {
+		return nil, fmt.Errorf("invalid concurrency %d: must be between 1 and %d", concurrency, maxSiteConcurrency)
+	}
+	if _, err := validateSearchAnalyticsQuery(startDate, endDate, dimensions, rowLimit, searchType, options); err != nil {
+		return nil, err
+	}
+
+	if len(siteURLs) == 0 {
+		sites, err := c.ListSites(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing properties: %w", err)
+		}
+		for _, site := range sites.Sites {
+			siteURLs = append(siteURLs, site.SiteURL)
+		}
+		if len(siteURLs) == 0 {
+			return nil, errors.New("the service account has access to no properties")
+		}
+	}
+
+	results := make([]SiteQueryResult, len(siteURLs))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, siteURL := range siteURLs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			resp, err := c.QuerySearchAnalytics(ctx, siteURL, startDate, endDate, dimensions, rowLimit, searchType, options)
+			results[i] = SiteQueryResult{SiteURL: siteURL, Response: resp, Err: err}
+			if err != nil || len(dimensions) == 0 {
+				return
+			}
+			totals, err := c.QuerySearchAnalytics(ctx, siteURL, startDate, endDate, nil, 0, searchType, SearchAnalyticsOptions{
+				DimensionFilterGroups: options.DimensionFilterGroups,
+				AggregationType:       options.AggregationType,
+				DataState:             options.DataState,
+			})
+			if err != nil {
+				results[i] = SiteQueryResult{SiteURL: siteURL, Err: fmt.Errorf("querying totals: %w", err)}
+				return
+			}
+			results[i].TotalsResponse = totals
+		}()
+	}
+	wg.Wait()
+	return results, nil
+}
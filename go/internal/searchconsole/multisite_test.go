@@ -0,0 +1,145 @@
+package searchconsole
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueryMultipleSites_BoundsConcurrencyAndReportsPerSiteErrors(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		if strings.Contains(r.URL.Path, "broken.example") {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error":"backend error"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"rows":[{"clicks":5,"impressions":50,"ctr":0.1,"position":3}]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	sites := []string{"a.example", "b.example", "broken.example", "c.example", "d.example"}
+	results, err := NewTestClient(srv.Client()).QueryMultipleSites(
+		context.Background(), sites, "2026-02-01", "2026-02-28", nil, 0, "", SearchAnalyticsOptions{}, 2)
+	if err != nil {
+		t.Fatalf("QueryMultipleSites: %v", err)
+	}
+	if maxInFlight > 2 {
+		t.Errorf("max in-flight requests = %d, want at most 2", maxInFlight)
+	}
+	if len(results) != len(sites) {
+		t.Fatalf("results = %d, want %d", len(results), len(sites))
+	}
+	for i, result := range results {
+		if result.SiteURL != sites[i] {
+			t.Errorf("results[%d].SiteURL = %q, want %q", i, result.SiteURL, sites[i])
+		}
+		if failed := sites[i] == "broken.example"; failed != (result.Err != nil) || failed == (result.Response != nil) {
+			t.Errorf("results[%d] = %+v, want failure only for broken.example", i, result)
+		}
+	}
+}
+
+func TestQueryMultipleSites_NoSites_QueriesEveryListedProperty(t *testing.T) {
+	var mu sync.Mutex
+	var queried []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/sites" {
+			_, _ = w.Write([]byte(`{"siteEntry":[{"siteUrl":"sc-domain:a.example"},{"siteUrl":"https://b.example/"}]}`))
+			return
+		}
+		mu.Lock()
+		queried = append(queried, r.URL.Path)
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	results, err := NewTestClient(srv.Client()).QueryMultipleSites(
+		context.Background(), nil, "2026-02-01", "2026-02-28", nil, 0, "", SearchAnalyticsOptions{}, 0)
+	if err != nil {
+		t.Fatalf("QueryMultipleSites: %v", err)
+	}
+	if len(results) != 2 || results[0].SiteURL != "sc-domain:a.example" || results[1].SiteURL != "https://b.example/" {
+		t.Errorf("results = %+v, want both listed properties", results)
+	}
+	if len(queried) != 2 {
+		t.Errorf("queried = %v, want 2 queries", queried)
+	}
+}
+
+func TestQueryMultipleSites_InvalidInput_ReturnsErrorWithoutHTTPCall(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+	client := NewTestClient(srv.Client())
+
+	if _, err := client.QueryMultipleSites(
+		context.Background(), []string{"a.example"}, "2026-02-01", "2026-02-28", nil, 0, "", SearchAnalyticsOptions{}, 11,
+	); err == nil || !strings.Contains(err.Error(), "invalid concurrency 11") {
+		t.Errorf("concurrency 11: error = %v", err)
+	}
+	if _, err := client.QueryMultipleSites(
+		context.Background(), nil, "2026-03-01", "2026-02-28", nil, 0, "", SearchAnalyticsOptions{}, 0,
+	); err == nil || !strings.Contains(err.Error(), "is after end_date") {
+		t.Errorf("reversed dates: error = %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("made %d requests, want 0", requests)
+	}
+}
+
+func TestQueryMultipleSites_WithDimensions_QueriesTotalsWithoutThem(t *testing.T) {
+	var mu sync.Mutex
+	var dimensioned, dimensionless int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.Contains(string(body), `"dimensions"`) {
+			dimensioned++
+			_, _ = w.Write([]byte(`{"rows":[{"keys":["x"],"clicks":5,"impressions":50,"ctr":0.1,"position":3}]}`))
+			return
+		}
+		dimensionless++
+		_, _ = w.Write([]byte(`{"rows":[{"clicks":9,"impressions":90,"ctr":0.1,"position":3}]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+
+	results, err := NewTestClient(srv.Client()).QueryMultipleSites(
+		context.Background(), []string{"a.example", "b.example"}, "2026-02-01", "2026-02-28",
+		[]string{"query"}, 0, "", SearchAnalyticsOptions{}, 0)
+	if err != nil {
+		t.Fatalf("QueryMultipleSites: %v", err)
+	}
+	if dimensioned != 2 || dimensionless != 2 {
+		t.Errorf("queries = %d dimensioned, %d dimensionless; want 2 of each", dimensioned, dimensionless)
+	}
+	for _, result := range results {
+		if result.TotalsResponse == nil || len(result.TotalsResponse.Rows) != 1 || result.TotalsResponse.Rows[0].Clicks != 9 {
+			t.Errorf("%s totals response = %+v, want the dimensionless row", result.SiteURL, result.TotalsResponse)
+		}
+	}
+}
@@ -100,6 +100,30 @@ func ResolveSiteURL(ctx context.Context, lister SiteLister, input string) (strin
 		input, accessible)
 }
 
+// SiteDomain returns the lowercased apex domain of a site reference in any
+// supported input format, so "devleader.ca", "sc-domain:devleader.ca", and
+// "https://www.devleader.ca/" all identify the same site.
+func SiteDomain(input string) string {
+	return strings.ToLower(extractApexFromInput(input))
+}
+
+// CoveredByDomainProperty reports whether the URL-prefix property siteURL is
+// part of domainProperty ("sc-domain:example.com"), which spans every protocol
+// and subdomain of its domain, so both report the same traffic.
+func CoveredByDomainProperty(siteURL, domainProperty string) bool {
+	domain, ok := strings.CutPrefix(domainProperty, "sc-domain:")
+	if !ok || strings.HasPrefix(siteURL, "sc-domain:") {
+		return false
+	}
+	u, err := url.Parse(siteURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	domain = strings.ToLower(domain)
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
 // extractApexFromInput returns the apex domain from any supported input format.
 func extractApexFromInput(input string) string {
 	input = strings.TrimSpace(input)
@@ -43,6 +43,21 @@ func TestNormalizeSiteURL(t *testing.T) {
 	}
 }
 
+func TestSiteDomain(t *testing.T) {
+	t.Parallel()
+	for _, input := range []string{
+		"devleader.ca",
+		"sc-domain:devleader.ca",
+		"https://www.devleader.ca/",
+		"https://www.DevLeader.ca/blog/",
+		"devleader.ca/blog",
+	} {
+		if got := searchconsole.SiteDomain(input); got != "devleader.ca" {
+			t.Errorf("SiteDomain(%q) = %q, want devleader.ca", input, got)
+		}
+	}
+}
+
 // mockSiteLister implements SiteLister for testing.
 type mockSiteLister struct {
 	sites []searchconsole.Site
@@ -56,6 +71,27 @@ func (m *mockSiteLister) ListSites(_ context.Context) (*searchconsole.SiteList,
 	return &searchconsole.SiteList{Sites: m.sites}, nil
 }
 
+func TestCoveredByDomainProperty(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		siteURL, domainProperty string
+		want                    bool
+	}{
+		{"https://www.devleader.ca/", "sc-domain:devleader.ca", true},
+		{"http://DevLeader.ca/blog/", "sc-domain:devleader.ca", true},
+		{"https://shop.devleader.ca:8443/", "sc-domain:devleader.ca", true},
+		{"https://notdevleader.ca/", "sc-domain:devleader.ca", false},
+		{"https://www.devleader.ca/", "sc-domain:www.devleader.ca", true},
+		{"https://devleader.ca/", "sc-domain:www.devleader.ca", false},
+		{"sc-domain:devleader.ca", "sc-domain:devleader.ca", false},
+		{"https://www.devleader.ca/", "https://devleader.ca/", false},
+	} {
+		if got := searchconsole.CoveredByDomainProperty(tc.siteURL, tc.domainProperty); got != tc.want {
+			t.Errorf("CoveredByDomainProperty(%q, %q) = %v, want %v", tc.siteURL, tc.domainProperty, got, tc.want)
+		}
+	}
+}
+
 func TestResolveSiteURL_DomainProperty_Found(t *testing.T) {
 	t.Parallel()
 	lister := &mockSiteLister{sites: []searchconsole.Site{
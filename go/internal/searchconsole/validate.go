@@ -0,0 +1,87 @@
+package searchconsole
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// retentionMonths is how far back Search Console keeps performance data.
+const retentionMonths = 16
+
+// validDimensions are the upstream API's supported dimension values. "hour"
+// is only valid together with data_state "hourly_all".
+var validDimensions = []string{"query", "page", "country", "device", "date", "searchAppearance", "hour"}
+
+// noQuerySearchTypes are the search types whose reports have no
+// queries, so the query dimension cannot be used with them.
+var noQuerySearchTypes = map[string]bool{
+	"discover":   true,
+	"googleNews": true,
+}
+
+// EarliestRetainedDate returns the oldest day, in Pacific Time, Search
+// Console still has data for.
+func EarliestRetainedDate() time.Time {
+	return addMonthsClamped(pacificToday(), -retentionMonths)
+}
+
+// clampToRetention moves a valid startDate older than Search Console's
+// retention forward to EarliestRetainedDate, so the response reports the
+// dates it actually covers.
+func clampToRetention(startDate string) string {
+	return max(startDate, EarliestRetainedDate().Format(time.DateOnly))
+}
+
+// validateDates rejects dates that are malformed, out of order, entirely
+// older than Search Console's retention, or in the future. A range that only
+// starts before retention is valid, and is queried from its earliest day.
+func validateDates(startDate, endDate string) error {
+	start, err := time.Parse(time.DateOnly, startDate)
+	if err != nil {
+		return fmt.Errorf("invalid start_date %q: must be YYYY-MM-DD", startDate)
+	}
+	end, err := time.Parse(time.DateOnly, endDate)
+	if err != nil {
+		return fmt.Errorf("invalid end_date %q: must be YYYY-MM-DD", endDate)
+	}
+	if start.After(end) {
+		return fmt.Errorf("start_date %s is after end_date %s", startDate, endDate)
+	}
+	earliest := EarliestRetainedDate().Format(time.DateOnly)
+	if endDate < earliest {
+		return fmt.Errorf(
+			"end_date %s is older than Search Console's %d-month retention: the earliest date with data is %s",
+			endDate, retentionMonths, earliest)
+	}
+	if today := pacificToday().Format(time.DateOnly); startDate > today {
+		return fmt.Errorf("start_date %s is in the future: today in Pacific Time is %s", startDate, today)
+	}
+	return nil
+}
+
+// validateDimensions rejects unknown and repeated dimensions, and the
+// combinations upstream does not support.
+func validateDimensions(dimensions []string, searchType string) error {
+	seen := make(map[string]bool, len(dimensions))
+	for _, dimension := range dimensions {
+		if !slices.Contains(validDimensions, dimension) {
+			return fmt.Errorf(
+				"invalid dimension %q: must be one of query, page, country, device, date, searchAppearance, hour", dimension)
+		}
+		if seen[dimension] {
+			return fmt.Errorf("dimension %q is listed more than once", dimension)
+		}
+		seen[dimension] = true
+	}
+	if seen["searchAppearance"] && len(dimensions) > 1 {
+		return errors.New(
+			"the searchAppearance dimension cannot be combined with other dimensions: query it alone, or filter by searchAppearance instead")
+	}
+	if seen["query"] && noQuerySearchTypes[searchType] {
+		return fmt.Errorf(
+			"the query dimension is not available for search_type %q: Search Console reports no queries for it", searchType)
+	}
+	return nil
+}
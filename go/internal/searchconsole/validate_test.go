@@ -0,0 +1,101 @@
+package searchconsole
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testNow is the clock every test in the package starts from. Fixtures use
+// fixed dates, which must stay inside Search Console's retention window as
+// real time moves on.
+var testNow = time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+
+func TestMain(m *testing.M) {
+	SetTestNow(testNow)
+	os.Exit(m.Run())
+}
+
+func TestQuerySearchAnalytics_InvalidInput_ReturnsErrorWithoutHTTPCall(t *testing.T) {
+	// 20:00 UTC on May 1 is 13:00 on May 1 in Pacific Time, so the earliest
+	// retained date is January 1, 2025.
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+	client := NewTestClient(srv.Client())
+
+	for _, tc := range []struct {
+		startDate, endDate string
+		dimensions         []string
+		searchType         string
+		want               string
+	}{
+		{startDate: "2026-2-01", endDate: "2026-02-28", want: `invalid start_date "2026-2-01": must be YYYY-MM-DD`},
+		{startDate: "2026-02-01", endDate: "2026-02-30", want: `invalid end_date "2026-02-30"`},
+		{startDate: "2026-03-01", endDate: "2026-02-28", want: "start_date 2026-03-01 is after end_date 2026-02-28"},
+		{startDate: "2024-11-01", endDate: "2024-12-31", want: "the earliest date with data is 2025-01-01"},
+		{startDate: "2026-05-02", endDate: "2026-05-03", want: "start_date 2026-05-02 is in the future"},
+		{dimensions: []string{"keyword"}, want: `invalid dimension "keyword"`},
+		{dimensions: []string{"query", "page", "query"}, want: `dimension "query" is listed more than once`},
+		{dimensions: []string{"searchAppearance", "device"}, want: "searchAppearance dimension cannot be combined"},
+		{dimensions: []string{"query"}, searchType: "discover", want: `query dimension is not available for search_type "discover"`},
+		{dimensions: []string{"query"}, searchType: "googleNews", want: `search_type "googleNews"`},
+	} {
+		startDate, endDate := tc.startDate, tc.endDate
+		if startDate == "" {
+			startDate, endDate = "2026-02-01", "2026-02-28"
+		}
+		_, err := client.QuerySearchAnalytics(
+			context.Background(), "devleader.ca", startDate, endDate, tc.dimensions, 0, tc.searchType, SearchAnalyticsOptions{})
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s..%s %v %q: error = %v, want %q", startDate, endDate, tc.dimensions, tc.searchType, err, tc.want)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("made %d requests, want 0", requests)
+	}
+}
+
+func TestQuerySearchAnalytics_ValidEdgeInput_IsAccepted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer srv.Close()
+	defer SetTestAPIBaseURL(srv.URL)()
+	client := NewTestClient(srv.Client())
+
+	for _, tc := range []struct {
+		startDate, endDate string
+		dimensions         []string
+		searchType         string
+	}{
+		// A range that only partly predates retention still has data, and
+		// is clamped to retention with a warning.
+		{startDate: "2024-12-01", endDate: "2025-01-01"},
+		{startDate: "2026-05-01", endDate: "2026-05-01"},
+		{startDate: "2026-02-01", endDate: "2026-02-28", dimensions: []string{"searchAppearance"}},
+		{startDate: "2026-02-01", endDate: "2026-02-28", dimensions: []string{"page", "date"}, searchType: "discover"},
+	} {
+		resp, err := client.QuerySearchAnalytics(
+			context.Background(), "devleader.ca", tc.startDate, tc.endDate, tc.dimensions, 0, tc.searchType, SearchAnalyticsOptions{})
+		if err != nil {
+			t.Errorf("%+v: unexpected error: %v", tc, err)
+			continue
+		}
+		partlyRetained := len(resp.Warnings) > 0 && resp.Warnings[0].Code == WarningOutsideRetention
+		if partlyRetained != (tc.startDate < "2025-01-01") {
+			t.Errorf("%+v: warnings = %+v", tc, resp.Warnings)
+		}
+		if wantStart := max(tc.startDate, "2025-01-01"); resp.StartDate != wantStart {
+			t.Errorf("%+v: startDate = %s, want %s", tc, resp.StartDate, wantStart)
+		}
+	}
+}
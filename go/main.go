@@ -5,25 +5,30 @@
 //
 //	google-search-console-mcp [--transport stdio|http]
 //	    [--listen-address <address>] [--port <port>] [--allowed-hosts <list>]
-//	    [--service-account-file <path>]
+//	    [--service-account-file <path>] [--analysis-config-file <path>]
 //
 // Credential resolution order: --service-account-file flag,
 // GOOGLE_SERVICE_ACCOUNT_FILE env var, GOOGLE_SERVICE_ACCOUNT_JSON env var, .env file.
+// Per-property analysis settings, such as brand terms, are read from
+// --analysis-config-file or the GSC_ANALYSIS_CONFIG_FILE env var.
 // When --transport http, MCP_LISTEN_ADDRESS and PORT configure the listener.
 package main
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
 	"syscall"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
 	"github.com/ncosentino/google-search-console-mcp/go/internal/config"
 	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
 )
@@ -37,6 +42,8 @@ var listSitesInputSchema = json.RawMessage(`{"type":"object","properties":{},"re
 
 func main() {
 	serviceAccountFile := flag.String("service-account-file", "", "Path to Google service account JSON key file")
+	analysisConfigFile := flag.String("analysis-config-file", "",
+		"Path to a JSON file of per-property analysis settings such as brand terms (default GSC_ANALYSIS_CONFIG_FILE)")
 	transport := flag.String("transport", "stdio", "Transport mode: stdio or http")
 	listenAddress := flag.String(
 		"listen-address",
@@ -69,7 +76,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	srv := newServer(client)
+	analysisConfig, err := config.LoadAnalysisConfig(*analysisConfigFile)
+	if err != nil {
+		slog.Error("failed to load analysis config", "err", err)
+		os.Exit(1)
+	}
+
+	srv := newServerWithConfig(client, analysisConfig)
 
 	switch *transport {
 	case "http":
@@ -118,6 +131,12 @@ func main() {
 // Extracted so tests can exercise real tool registration/dispatch via an in-memory or
 // IOTransport session, instead of only unit-testing the handler functions directly.
 func newServer(client *searchconsole.Client) *mcp.Server {
+	return newServerWithConfig(client, config.AnalysisConfig{})
+}
+
+// newServerWithConfig is newServer with per-property analysis settings, such as
+// brand terms, available to the tools that use them.
+func newServerWithConfig(client *searchconsole.Client, analysisConfig config.AnalysisConfig) *mcp.Server {
 	srv := mcp.NewServer(&mcp.Implementation{
 		Name:    "google-search-console-mcp",
 		Version: version,
@@ -130,10 +149,180 @@ func newServer(client *searchconsole.Client) *mcp.Server {
 	mcp.AddTool(srv,
 		&mcp.Tool{
 			Name:        "query_search_analytics",
-			Description: "Query Google Search Console search analytics. Returns clicks, impressions, CTR, and average position grouped by the specified dimensions (query, page, country, device, date). The site_url parameter accepts flexible input: bare domain (\"devleader.ca\"), full URL (\"https://www.devleader.ca\"), or canonical GSC property format (\"sc-domain:devleader.ca\", \"https://www.devleader.ca/\"). The server normalizes the input and automatically retries with property discovery on 403 errors. row_limit defaults to 1000 if omitted. dimensions may be omitted entirely or passed as an empty array to get aggregate totals -- both are equivalent. search_type filters which Google Search results the metrics come from: \"web\" (default, the combined/All tab), \"image\", \"video\", \"news\", \"discover\", or \"googleNews\". Note: search_type=\"video\" reports clicks/impressions/CTR/position for Google Video search -- it is NOT the Video Indexing report and must not be treated as proof that any specific video is indexed, rendered, or eligible for rich results.",
+			Description: "Query Google Search Console search analytics. Returns clicks, impressions, CTR, and average position grouped by the specified dimensions (query, page, country, device, date). Specify the period either with start_date and end_date (YYYY-MM-DD) or with date_range, not both. date_range accepts last_7_days, last_28_days, last_N_days, last_3_months, last_N_months, month_to_date, previous_month, or an offset such as -90d; it is resolved in Pacific Time (Search Console's day boundary) to end on the latest day with settled data (today minus 2 days), and the response echoes dateRange alongside the resolved startDate and endDate. The site_url parameter accepts flexible input: bare domain (\"devleader.ca\"), full URL (\"https://www.devleader.ca\"), or canonical GSC property format (\"sc-domain:devleader.ca\", \"https://www.devleader.ca/\"). The server normalizes the input and automatically retries with property discovery on 403 errors. Dates and dimensions are checked before any request: dates must be valid YYYY-MM-DD with start_date not after end_date or today, end_date must fall within Search Console's 16-month retention (a start_date before it is clamped to the earliest retained day, with an outside_retention warning), dimensions must be known and unique, searchAppearance must be the only dimension, and query is unavailable for search_type discover and googleNews. row_limit defaults to 1000 if omitted and may not exceed 25000. start_row skips that many rows (zero-based) for manual paging. all_rows=true pages through every row automatically (row_limit then sets the page size, default 25000) until upstream runs out or max_rows (default 100000) is reached. The response's truncated field is true when the row limit or max_rows was hit, meaning more rows may exist. dimensions may be omitted entirely or passed as an empty array to get aggregate totals -- both are equivalent. search_type filters which Google Search results the metrics come from: \"web\" (default, the combined/All tab), \"image\", \"video\", \"news\", \"discover\", or \"googleNews\". Note: search_type=\"video\" reports clicks/impressions/CTR/position for Google Video search -- it is NOT the Video Indexing report and must not be treated as proof that any specific video is indexed, rendered, or eligible for rich results. dimension_filter_groups restricts rows before they are returned: each group has filters of {dimension, operator, expression}, where dimension is query, page, country, device, or searchAppearance; operator is equals (default), notEquals, contains, notContains, includingRegex, or excludingRegex (RE2 syntax); and group_type is \"and\" (default, the only upstream-supported value). country expects an ISO 3166-1 alpha-3 code (\"usa\") and device one of DESKTOP, MOBILE, TABLET; both accept only equals/notEquals. Example: [{\"filters\":[{\"dimension\":\"page\",\"operator\":\"contains\",\"expression\":\"/blog/\"}]}]. aggregation_type is auto (default), byPage, byProperty, or byNewsShowcasePanel; byProperty cannot be combined with the page dimension or a page filter, and byNewsShowcasePanel requires search_type discover or googleNews plus a searchAppearance equals NEWS_SHOWCASE filter. data_state is final (default, matches settled GSC numbers) or all (includes fresh, still-changing data, matching the GSC UI's latest days). The response echoes the effective aggregationType and dataState. segment=\"brand\" (requires the query dimension) marks each row's segment as \"branded\" or \"non_branded\" and adds a segments array with each segment's totals; brand terms come from the server's analysis config for the property, or from brand_terms (matched case-insensitively as whole words, with or without the spaces between them) and brand_patterns (case-insensitive RE2 regexes), which override the config for the call. classify_intent: true (requires the query dimension) sets each row's intent to informational, navigational, commercial, transactional, or unclassified by rules, and adds an intents array with each intent's totals: a query is navigational if it names the brand (brand terms as for segment, when any are configured or passed) or has a navigational keyword such as login, otherwise transactional (buy, price, ...), commercial (best, vs, review, ...), or informational (guide, tutorial, ..., or starting with a question word such as how or what), in that order of precedence. Keywords match whole words case-insensitively; intent_rules ({informational, navigational, commercial, transactional, question_words: [...], replace_defaults}) or the property's intents entry in the server's analysis config extends the built-in lists, or replaces them with replace_defaults: true. named_dimensions: true replaces each row's positional keys array with fields named after its dimensions (query, page, country, device, date, hour, searchAppearance), so {\"keys\": [\"blazor\", \"https://...\"]} becomes {\"query\": \"blazor\", \"page\": \"https://...\"}. fill_date_gaps: true (requires the date dimension) inserts a zero row with filled: true for every date from start_date through the last date with data that has no row, once per combination of the other dimensions seen in the response, and orders rows by date, so series can be charted and trended directly; dates after the last one with data are never filled. It pages through every row as all_rows does, and fails rather than fill if max_rows is reached. max_output_tokens sets an approximate budget (about 4 characters per token) for the formatted response; when the full result would exceed it, only the top rows by summary_metric (clicks, the default, or impressions) that fit are returned, and a summary object reports summarized, sortedBy, rowsShown, rowsOmitted, an other bucket totalling the omitted rows, and totals across every row. The budget also applies to query_multiple_sites; the analysis tools, such as new_queries, lost_queries, and intent_rollup, cap their output with their own limits instead." + outputFormatDescription,
 		},
 		func(ctx context.Context, _ *mcp.CallToolRequest, input querySearchAnalyticsInput) (*mcp.CallToolResult, any, error) {
-			return querySearchAnalytics(ctx, client, input)
+			return querySearchAnalytics(ctx, client, analysisConfig, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "query_hourly_performance",
+			Description: "Query hour-level Google Search Console performance (clicks, impressions, CTR, position) for recent days, to watch traffic the same day a change ships. Google keeps hourly data only for the last few days and reports hours in Pacific Time; each row's hour is converted to timezone (an IANA name such as \"Europe/London\", default \"UTC\") and hourPacific keeps the original. start_date and end_date (YYYY-MM-DD, Pacific Time) default to the last 3 days including today when both are omitted. dimensions optionally splits each hour further (query, page, country, device); the row's keys follow that order. site_url, search_type, and dimension_filter_groups behave exactly as in query_search_analytics. Rows are returned in chronological order and include fresh, not-yet-final data." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input queryHourlyPerformanceInput) (*mcp.CallToolResult, any, error) {
+			return queryHourlyPerformance(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "compare_periods",
+			Description: "Compare Google Search Console performance between two periods in one call, answering \"what changed versus last period\". Runs the same query for the current period and for the comparison period, joins rows by their dimension keys, and returns absolute change and percentChange for clicks, impressions, CTR, and position, plus overall totals. comparison is \"previous_period\" (default: the equally long period immediately before) or \"year_over_year\" (the same dates one year earlier). The current period is given by start_date/end_date (YYYY-MM-DD) or date_range, as in query_search_analytics. Rows present in only one period are included with status \"new\" or \"lost\"; a row's position fields are null for a period it did not appear in, and a negative position change is an improvement. percentChange is null when the previous value is 0. Rows are ordered by the size of the click change. dimensions, search_type, dimension_filter_groups, and max_rows apply to both periods exactly as in query_search_analytics. Both periods are paged through every row by default; all_rows: false fetches only the first row_limit rows of each, and truncated is then true if either period hit the limit, in which case rows near it may be misreported as new or lost. totals comes from a separate query without dimensions for each period, so it covers the whole period regardless of row limits." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input comparePeriodsInput) (*mcp.CallToolResult, any, error) {
+			return comparePeriods(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "top_movers",
+			Description: "Rank the biggest winners and losers between two periods for one dimension, answering \"which queries or pages gained or lost the most\". Fetches every row of both periods (paging past the 1000-row default, up to max_rows per period, default 100000) so movers outside the top rows are not missed. dimension is one of query (default), page, country, device, searchAppearance. metric is clicks (default), impressions, or position; for position only rows ranking in both periods are ranked and a winner moved up (negative position change). min_impressions drops rows whose impressions are below the threshold in both periods, filtering out noise. limit (default 10) caps winners and losers separately. The current period and comparison work exactly as in compare_periods, and each winner or loser has the same shape as a compare_periods row; totals comes from a separate query without dimensions for each period, as in compare_periods. candidateCount is how many rows passed the threshold." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input topMoversInput) (*mcp.CallToolResult, any, error) {
+			return topMovers(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "find_cannibalization",
+			Description: "Find keyword cannibalization: queries for which two or more of the site's pages compete. Fetches every query+page row for the period (up to max_rows, default 100000), groups them by query, and flags queries where at least two pages each take min_share (a fraction, default 0.1 = 10%, at most 0.5) of the query's impressions. Each flagged query reports its totals (impression-weighted position) and every page that ranked for it, ordered by impressions, with the page's clicks, impressions, CTR, position, impressionShare, clickShare (fractions 0-1), and whether it counts as competing. Queries with fewer than min_impressions total impressions are skipped (default 0). Flagged queries are ordered by impressions; limit (default 50) caps how many are returned, and cannibalizedCount reports how many were found. The period is start_date/end_date (YYYY-MM-DD) or date_range, as in query_search_analytics; search_type and dimension_filter_groups also work as there." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input findCannibalizationInput) (*mcp.CallToolResult, any, error) {
+			return findCannibalization(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "striking_distance_keywords",
+			Description: "Find striking-distance keywords: queries ranking just off the top of the results that could gain the most clicks by moving up. Fetches every query+page row for the period (up to max_rows, default 100000) and returns the rows whose position is between min_position (default 8) and max_position (default 20) with at least min_impressions impressions (default 100; pass 0 for all), each with its landing page. For each, estimatedClicksAtTarget is impressions times the expected CTR at target_position (default 3), read from a CTR-by-position curve fitted from the property's own rows in the same query; estimatedClickUplift is that minus current clicks. Results are ordered by estimatedClickUplift; limit (default 50) caps how many are returned and opportunityCount reports how many qualified. ctrCurve holds the fitted curve: per rounded position, observedCtr and the smoothed, non-increasing ctr used for estimates (positions past 30 share the last bucket). The period is start_date/end_date (YYYY-MM-DD) or date_range, as in query_search_analytics; search_type and dimension_filter_groups also work as there." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input strikingDistanceInput) (*mcp.CallToolResult, any, error) {
+			return strikingDistanceKeywords(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "find_ctr_anomalies",
+			Description: "Find queries or pages whose click-through rate falls well below what the property usually gets at the same position, which usually points to a weak title or snippet. Fetches every query row for the period (up to max_rows, default 100000) and fits an expected CTR-by-position curve from them: per rounded position, observedCtr is clicks over impressions and ctr is smoothed with impression-weighted isotonic regression so it never rises as position worsens (positions past 30 share the last bucket). The curve is returned as ctrCurve so the result can be explained. dimension is query (default) or page; for page, page rows are fetched too and compared against the same query-fitted curve. A row is flagged when it has at least min_impressions (default 100; pass 0 for all), its ctrRatio (CTR over expectedCtr at its position) is at most max_ctr_ratio (default 0.5), and its zScore (standard deviations its clicks fall below expectedClicks) is -2 or lower, so small-sample noise is not flagged. Anomalies are ordered by missingClicks (expectedClicks minus clicks); limit (default 50) caps how many are returned and anomalyCount reports how many were found. The period is start_date/end_date (YYYY-MM-DD) or date_range, as in query_search_analytics; search_type and dimension_filter_groups also work as there and apply to both fetches." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input findCTRAnomaliesInput) (*mcp.CallToolResult, any, error) {
+			return findCTRAnomalies(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "detect_anomalies",
+			Description: "Detect days on which clicks, impressions, CTR, or position broke from their trend, telling real drops and spikes apart from normal weekly cycles. Fetches daily rows for the period plus baseline_weeks (default 4, 2-12) of history before it, optionally split into one series per device, country, or page (split_by). Each day is compared with a weekday-adjusted rolling baseline: expected is the median of the same weekday over the preceding baseline_weeks weeks, and zScore is the deviation from expected over the baseline's robust spread (1.4826 times the median absolute deviation of each baseline day from its weekday median). Days with |zScore| at or above threshold (default 3.5) are returned with direction \"spike\" or \"drop\"; for position, a drop is a ranking improvement. metric is clicks (default), impressions, ctr, or position. Missing days count as zero clicks and impressions, except before the first or after the last date with any data, which are not scored, so days Search Console has not reported yet are never drops. History is not fetched from before Search Console's 16-month retention, and the call fails rather than score a response cut off by max_rows. min_impressions skips series with fewer total impressions (useful with split_by page). Anomalies are ordered by |zScore|; limit (default 50) caps how many are returned and anomalyCount reports how many were found. The period is start_date/end_date (YYYY-MM-DD) or date_range, as in query_search_analytics; search_type and dimension_filter_groups also work as there." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input detectAnomaliesInput) (*mcp.CallToolResult, any, error) {
+			return detectAnomalies(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "brand_split",
+			Description: "Split search performance into branded and non-branded queries, with totals for each compared against a previous period and a trend over time -- non-branded growth is usually the KPI that matters. Fetches every date+query row for both periods (up to max_rows per period, default 100000). Brand terms come from the server's analysis config for the property, or from brand_terms (matched case-insensitively as whole words, with or without the spaces between them, so \"dev leader\" matches \"devleader\" but \"go\" does not match \"google\") and brand_patterns (case-insensitive RE2 regexes), which override the config for the call. branded and nonBranded report current, previous, change, and percentChange for clicks, impressions, CTR, and impression-weighted position; brandedClickShare compares the branded fraction (0-1) of clicks. trend breaks the current period down by granularity: week (default, weeks starting Monday) or day. Totals cover only the queries Search Console reports; anonymized queries are in neither segment. The current period and comparison (previous_period, the default, or year_over_year) work exactly as in compare_periods; search_type and dimension_filter_groups work as in query_search_analytics." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input brandSplitInput) (*mcp.CallToolResult, any, error) {
+			return brandSplit(ctx, client, analysisConfig, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "cluster_queries",
+			Description: "Condense every query of a period into topics: queries are lowercased, stop words (the, how, to, ...) are dropped, and the remaining words are stemmed, so \"blazor component\" and \"blazor components\" share a topic. Each cluster holds every query containing one n-gram of 1 to max_ngram (default 2, at most 3) stemmed words; a query belongs to every cluster it matches, so cluster totals overlap. Clusters report summed clicks and impressions, CTR recomputed from the sums, impression-weighted position, the number of queries, the most-searched spelling as label, and up to 5 top queries. Clusters with fewer than min_queries (default 2) queries are dropped; the top limit (default 50) are returned, ordered by sort_by: impressions (default) or clicks. Fetches every query row for the period (up to max_rows, default 100000), so use this instead of query_search_analytics to see which topics a site ranks for. Dates, search_type, and dimension_filter_groups work as in query_search_analytics." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input clusterQueriesInput) (*mcp.CallToolResult, any, error) {
+			return clusterQueries(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "rollup_pages",
+			Description: "Roll page performance up into site sections such as /blog/ or /docs/ instead of individual URLs. Pass groups as [{\"name\": \"Blog\", \"pattern\": \"/blog/*\"}, ...]: patterns match the whole URL path, \"*\" matches anything including \"/\", a trailing \"/*\" also matches the bare directory (\"/blog/*\" covers \"/blog\"), and each page joins the first group it matches, or \"(other)\". Without groups, the property's page_groups from the server's analysis config are used; without those, or when depth is passed, pages are grouped by their first depth (default 1, at most 10) path segments, so at depth 1 \"/blog/2026/post\" is in \"/blog/*\". Each group reports summed clicks and impressions, CTR recomputed from the sums, impression-weighted position, its page count, and its top 5 pages by clicks. Groups are ordered by clicks; the top limit (default 50) are returned. Fetches every page row for the period (up to max_rows, default 100000). Dates, search_type, and dimension_filter_groups work as in query_search_analytics." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input rollupPagesInput) (*mcp.CallToolResult, any, error) {
+			return rollupPages(ctx, client, analysisConfig, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "find_decaying_content",
+			Description: "Find pages whose clicks have declined for months, not just dipped, to prioritise content refreshes. Queries each page's clicks for every one of the last months (default 6, at most 15, the most that lie wholly within Search Console's 16-month retention) complete calendar months, then flags a page when each of its last sustained_months (default 3) months has at least min_decline percent (default 30) fewer clicks than its peak month before them, and that peak had at least min_peak_clicks (default 10) clicks. A one-month dip is not flagged. For each page it returns peakMonth and peakClicks, currentMonth (the latest complete month) and currentClicks, declinePercent, lostClicks, impressions in both months, monthlyClicks aligned with months, and topQueryDrops: its top_queries (default 5) queries that lost the most clicks between the peak and current months. Pages are ordered by lost clicks; the top limit (default 25) are returned. Runs one query per month plus one per distinct peak month for the query drill-down, each fetching every row (up to max_rows, default 100000). site_url, search_type, and dimension_filter_groups work as in query_search_analytics." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input findDecayingContentInput) (*mcp.CallToolResult, any, error) {
+			return findDecayingContent(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "forecast_traffic",
+			Description: "Forecast a property's daily clicks and impressions, for example to set quarterly targets. Fits an additive Holt-Winters model with weekly seasonality (level, trend, and day-of-week pattern) to each metric's daily history -- date_range or start_date and end_date, default last_180_days, with end_date clamped to the latest complete date; days without data count as zero, except that the model is fitted only through the last day with data (trainingStart, trainingEnd, and trainingDays report the window fitted, which needs at least 28 days) -- choosing the smoothing parameters that minimise the one-step-ahead squared error. Returns, per metric, horizon_days (default 90, at most 365) daily points from the day after trainingEnd, each with a value and a lower and upper bound of a confidence (80, 90, 95 default, or 99) percent prediction interval that widens with distance; historyTotal and forecastTotal; and diagnostics to judge reliability: alpha, beta, gamma (the fitted level, trend, and seasonal smoothing), observations, the in-sample one-step rmse, mae, and mape (percent, over days with traffic), and a backtest that refits without the last holdoutDays days (up to the horizon or a quarter of the history) and reports holdoutMape and holdoutCoverage, the share (0-1) of those days inside the intervals. A holdoutCoverage well below the confidence, or a high holdoutMape, means the forecast should not be relied on. Values are never negative. search_type and dimension_filter_groups work as in query_search_analytics." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input forecastTrafficInput) (*mcp.CallToolResult, any, error) {
+			return forecastTraffic(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "new_queries",
+			Description: "List the queries that gained impressions from zero: those with impressions in the current period and none in the comparison period. Fetches every row of both periods (paging past the 1000-row default, up to max_rows per period, default 100000), so a query outside the first page of results is never mistaken for one that appeared or vanished; truncated is true if either period hit max_rows, in which case some results may be false. by_page: true compares query and page pairs instead, so a query that moved to a different page is reported for both. min_impressions (default 0) drops rows with fewer impressions; limit (default 50) caps the rows returned, ordered by impressions, and bounds the output in place of max_output_tokens, which this tool does not take. count, clicks, and impressions total every matching row before limit, and impressionShare is their fraction (0-1) of the current period's impressions. Each row has its current-period metrics. A query can also appear because it crossed Search Console's anonymization threshold. The current period and comparison (previous_period, the default, or year_over_year) work exactly as in compare_periods; search_type and dimension_filter_groups work as in query_search_analytics." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input queryChangesInput) (*mcp.CallToolResult, any, error) {
+			return newQueries(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "lost_queries",
+			Description: "List the queries that dropped to zero impressions: those with impressions in the comparison period and none in the current period. Fetches every row of both periods (paging past the 1000-row default, up to max_rows per period, default 100000), so a query outside the first page of results is never mistaken for one that appeared or vanished; truncated is true if either period hit max_rows, in which case some results may be false. by_page: true compares query and page pairs instead, so a query that moved to a different page is reported for both. min_impressions (default 0) drops rows with fewer impressions; limit (default 50) caps the rows returned, ordered by impressions, and bounds the output in place of max_output_tokens, which this tool does not take. count, clicks, and impressions total every matching row before limit, and impressionShare is their fraction (0-1) of the comparison period's impressions. Each row has its comparison-period metrics. A query can also vanish because it fell below Search Console's anonymization threshold. The current period and comparison (previous_period, the default, or year_over_year) work exactly as in compare_periods; search_type and dimension_filter_groups work as in query_search_analytics." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input queryChangesInput) (*mcp.CallToolResult, any, error) {
+			return lostQueries(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "intent_rollup",
+			Description: "Roll every query of a period up by search intent, to see which intents a site serves well. Each query is classified as informational, navigational, commercial, transactional, or unclassified exactly as query_search_analytics does with classify_intent: true, using intent_rules, the property's intents entry in the server's analysis config, and brand terms from brand_terms and brand_patterns or the config. Each intent reports queryCount, summed clicks and impressions, CTR recomputed from the sums, impression-weighted position, clickShare and impressionShare (its fractions, 0-1, of all queries' clicks and impressions), and its top_queries (default 5) queries by clicks; top_queries bounds the output in place of max_output_tokens, which this tool does not take. Every intent is listed, even one with no queries. Totals cover only the queries Search Console reports; anonymized queries are in no intent. Fetches every query row for the period (up to max_rows, default 100000). Dates, search_type, and dimension_filter_groups work as in query_search_analytics." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input intentRollupInput) (*mcp.CallToolResult, any, error) {
+			return intentRollup(ctx, client, analysisConfig, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "query_multiple_sites",
+			Description: "Run the same search analytics query across several Google Search Console properties at once, for agencies and portfolios. site_urls lists the properties (each in any form query_search_analytics accepts); omit it to query every property the service account can access. Up to concurrency (default 4, at most 10) properties are queried at a time. Returns totals, a table with one line per property that succeeded (rowCount, clicks, impressions, CTR, impression-weighted position, truncated), ordered by clicks; combined, the same metrics across all of them; and sites, each property's full response, or its error -- a property failing is reported there without failing the batch. A URL-prefix property (https://www.example.com/) that a domain property in the same call (sc-domain:example.com) already covers is marked with coveredBy in totals and left out of combined, so its traffic is not counted twice. With dimensions, each property is also queried without them, so its totals cover the whole period regardless of row limits and anonymized queries. totals_only: true drops the rows from each response. max_output_tokens and summary_metric work as in query_search_analytics across the whole response: when it would not fit, every property with more rows than fit keeps only the same number of its top rows, with a summary, while totals and combined are never cut. Dates, dimensions, search_type, row_limit, dimension_filter_groups, all_rows, max_rows, and data_state work as in query_search_analytics." + outputFormatDescription,
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input queryMultipleSitesInput) (*mcp.CallToolResult, any, error) {
+			return queryMultipleSites(ctx, client, input)
+		},
+	)
+
+	mcp.AddTool(srv,
+		&mcp.Tool{
+			Name:        "get_data_freshness",
+			Description: "Find how recent a Google Search Console property's data is. Probes the last 10 days (Pacific Time) by date, once for final data and once including fresh data, and returns latestFinalDate (the latest day with settled numbers), latestFreshDate (the latest day with any data), provisionalDays (days after latestFinalDate whose numbers may still change), and expectedFinalDate (the latest day final data usually covers, which date_range values end on). warnings has final_data_delayed when final data ends earlier than usual, or no_recent_data when no recent day has data. Call it before reporting on the last few days. site_url and search_type work as in query_search_analytics. query_search_analytics responses carry the related warnings provisional_range, when end_date is after expectedFinalDate, and fresh_data, when data_state is all.",
+		},
+		func(ctx context.Context, _ *mcp.CallToolRequest, input getDataFreshnessInput) (*mcp.CallToolResult, any, error) {
+			return getDataFreshness(ctx, client, input)
 		},
 	)
 
@@ -185,12 +374,61 @@ func splitAndTrim(s string) []string {
 
 // querySearchAnalyticsInput is the input schema for the query_search_analytics tool.
 type querySearchAnalyticsInput struct {
-	SiteURL    string   `json:"site_url"`
-	StartDate  string   `json:"start_date"`
-	EndDate    string   `json:"end_date"`
-	Dimensions []string `json:"dimensions,omitempty"`
-	SearchType string   `json:"search_type,omitempty"`
-	RowLimit   int      `json:"row_limit,omitempty"`
+	SiteURL               string                      `json:"site_url"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	DateRange             string                      `json:"date_range,omitempty"`
+	Dimensions            []string                    `json:"dimensions,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	RowLimit              int                         `json:"row_limit,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	StartRow              int                         `json:"start_row,omitempty"`
+	AllRows               bool                        `json:"all_rows,omitempty"`
+	MaxRows               int                         `json:"max_rows,omitempty"`
+	AggregationType       string                      `json:"aggregation_type,omitempty"`
+	DataState             string                      `json:"data_state,omitempty"`
+	Segment               string                      `json:"segment,omitempty"`
+	BrandTerms            []string                    `json:"brand_terms,omitempty"`
+	BrandPatterns         []string                    `json:"brand_patterns,omitempty"`
+	ClassifyIntent        bool                        `json:"classify_intent,omitempty"`
+	IntentRules           *intentRulesInput           `json:"intent_rules,omitempty"`
+	NamedDimensions       bool                        `json:"named_dimensions,omitempty"`
+	FillDateGaps          bool                        `json:"fill_date_gaps,omitempty"`
+	MaxOutputTokens       int                         `json:"max_output_tokens,omitempty"`
+	SummaryMetric         string                      `json:"summary_metric,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+// namedSearchAnalyticsResponse is the query_search_analytics result when
+// named_dimensions is set; its Rows replace the embedded response's.
+type namedSearchAnalyticsResponse struct {
+	*searchconsole.SearchAnalyticsResponse
+	Rows []searchconsole.NamedRow `json:"rows"`
+}
+
+// dimensionFilterGroupInput is one entry of a tool's dimension_filter_groups argument.
+type dimensionFilterGroupInput struct {
+	GroupType string                 `json:"group_type,omitempty"`
+	Filters   []dimensionFilterInput `json:"filters"`
+}
+
+// dimensionFilterInput is one filter within a dimensionFilterGroupInput.
+type dimensionFilterInput struct {
+	Dimension  string `json:"dimension"`
+	Operator   string `json:"operator,omitempty"`
+	Expression string `json:"expression"`
+}
+
+// queryHourlyPerformanceInput is the input schema for the query_hourly_performance tool.
+type queryHourlyPerformanceInput struct {
+	SiteURL               string                      `json:"site_url"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	Dimensions            []string                    `json:"dimensions,omitempty"`
+	Timezone              string                      `json:"timezone,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
 }
 
 // listSitesInput is the input schema for the list_sites tool (no parameters required).
@@ -208,9 +446,146 @@ type inspectURLInput struct {
 	LanguageCode  string `json:"language_code,omitempty"`
 }
 
-func querySearchAnalytics(ctx context.Context, client *searchconsole.Client, input querySearchAnalyticsInput) (*mcp.CallToolResult, any, error) {
-	result, err := client.QuerySearchAnalytics(ctx, input.SiteURL, input.StartDate, input.EndDate, input.Dimensions, input.RowLimit, input.SearchType)
-	return marshalToolResult("querying search analytics", result, err)
+func querySearchAnalytics(
+	ctx context.Context,
+	client *searchconsole.Client,
+	analysisConfig config.AnalysisConfig,
+	input querySearchAnalyticsInput,
+) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*searchconsole.SearchAnalyticsResponse]("querying search analytics", nil, err)
+	}
+	summaryMetric := input.SummaryMetric
+	if summaryMetric == "" {
+		summaryMetric = analysis.SummaryMetricClicks
+	}
+	if err := validateOutputBudget(input.MaxOutputTokens, summaryMetric); err != nil {
+		return marshalToolResult[*searchconsole.SearchAnalyticsResponse]("querying search analytics", nil, err)
+	}
+	if input.FillDateGaps && !slices.Contains(input.Dimensions, "date") {
+		err := errors.New("fill_date_gaps requires the date dimension")
+		return marshalToolResult[*searchconsole.SearchAnalyticsResponse]("querying search analytics", nil, err)
+	}
+	startDate, endDate, err := resolveDateInput(input.DateRange, input.StartDate, input.EndDate)
+	if err != nil {
+		return marshalToolResult[*searchconsole.SearchAnalyticsResponse]("querying search analytics", nil, err)
+	}
+	var brandMatcher *analysis.BrandMatcher
+	switch input.Segment {
+	case "":
+	case segmentBrand:
+		if !slices.Contains(input.Dimensions, "query") {
+			err := errors.New(`segment "brand" requires the query dimension`)
+			return marshalToolResult[*searchconsole.SearchAnalyticsResponse]("querying search analytics", nil, err)
+		}
+		brandMatcher, err = brandMatcherFor(analysisConfig, input.SiteURL, input.BrandTerms, input.BrandPatterns)
+		if err != nil {
+			return marshalToolResult[*searchconsole.SearchAnalyticsResponse]("querying search analytics", nil, err)
+		}
+	default:
+		err := fmt.Errorf("invalid segment %q: must be brand", input.Segment)
+		return marshalToolResult[*searchconsole.SearchAnalyticsResponse]("querying search analytics", nil, err)
+	}
+	var intentClassifier *analysis.IntentClassifier
+	if input.ClassifyIntent {
+		if !slices.Contains(input.Dimensions, "query") {
+			err := errors.New("classify_intent requires the query dimension")
+			return marshalToolResult[*searchconsole.SearchAnalyticsResponse]("querying search analytics", nil, err)
+		}
+		intentClassifier, err = intentClassifierFor(analysisConfig, input.SiteURL, input.IntentRules, input.BrandTerms, input.BrandPatterns)
+		if err != nil {
+			return marshalToolResult[*searchconsole.SearchAnalyticsResponse]("querying search analytics", nil, err)
+		}
+	}
+	// Filling gaps pages through every row, since filling a truncated
+	// response would turn the rows cut off into zeros.
+	options := searchconsole.SearchAnalyticsOptions{
+		DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+		StartRow:              input.StartRow,
+		AllRows:               input.AllRows || input.FillDateGaps,
+		MaxRows:               input.MaxRows,
+		AggregationType:       input.AggregationType,
+		DataState:             input.DataState,
+	}
+	result, err := client.QuerySearchAnalytics(ctx, input.SiteURL, startDate, endDate, input.Dimensions, input.RowLimit, input.SearchType, options)
+	if err != nil {
+		return marshalToolResult[*searchconsole.SearchAnalyticsResponse]("querying search analytics", nil, err)
+	}
+	result.DateRange = input.DateRange
+	if brandMatcher != nil {
+		if err := analysis.SegmentByBrand(result, brandMatcher); err != nil {
+			return marshalToolResult[*searchconsole.SearchAnalyticsResponse]("querying search analytics", nil, err)
+		}
+	}
+	if intentClassifier != nil {
+		if err := analysis.ClassifyIntents(result, intentClassifier); err != nil {
+			return marshalToolResult[*searchconsole.SearchAnalyticsResponse]("querying search analytics", nil, err)
+		}
+	}
+	if input.FillDateGaps {
+		if err := analysis.FillDateGaps(result); err != nil {
+			return marshalToolResult[*searchconsole.SearchAnalyticsResponse]("querying search analytics", nil, err)
+		}
+	}
+	text, err := fitOutputBudget(input.MaxOutputTokens, len(result.Rows), func(keep int) (string, error) {
+		resp, err := summarizeForBudget(result, summaryMetric, keep, input.MaxOutputTokens)
+		if err != nil {
+			return "", err
+		}
+		if input.NamedDimensions {
+			named := namedSearchAnalyticsResponse{SearchAnalyticsResponse: resp, Rows: resp.NamedRows()}
+			return renderToolResult(input.OutputFormat, nil, named)
+		}
+		return renderToolResult(input.OutputFormat, input.Dimensions, resp)
+	})
+	if err != nil {
+		return marshalToolResult[*searchconsole.SearchAnalyticsResponse]("querying search analytics", nil, err)
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil, nil
+}
+
+// resolveDateInput returns the concrete dates for a tool call that accepts
+// either a date_range or explicit start_date and end_date, but not both.
+func resolveDateInput(dateRange, startDate, endDate string) (string, string, error) {
+	if dateRange != "" {
+		if startDate != "" || endDate != "" {
+			return "", "", errors.New("date_range cannot be combined with start_date or end_date")
+		}
+		return searchconsole.ResolveDateRange(dateRange)
+	}
+	if startDate == "" || endDate == "" {
+		return "", "", errors.New("start_date and end_date are required unless date_range is set")
+	}
+	return startDate, endDate, nil
+}
+
+// toDimensionFilterGroups converts tool-level filter input into the client's
+// representation; validation and defaulting are left to the client.
+func toDimensionFilterGroups(groups []dimensionFilterGroupInput) []searchconsole.DimensionFilterGroup {
+	if len(groups) == 0 {
+		return nil
+	}
+	out := make([]searchconsole.DimensionFilterGroup, len(groups))
+	for i, group := range groups {
+		filters := make([]searchconsole.DimensionFilter, len(group.Filters))
+		for j, filter := range group.Filters {
+			filters[j] = searchconsole.DimensionFilter(filter)
+		}
+		out[i] = searchconsole.DimensionFilterGroup{GroupType: group.GroupType, Filters: filters}
+	}
+	return out
+}
+
+func queryHourlyPerformance(ctx context.Context, client *searchconsole.Client, input queryHourlyPerformanceInput) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*searchconsole.HourlyPerformanceResponse]("querying hourly performance", nil, err)
+	}
+	options := searchconsole.SearchAnalyticsOptions{
+		DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+	}
+	result, err := client.QueryHourlyPerformance(
+		ctx, input.SiteURL, input.StartDate, input.EndDate, input.Dimensions, input.SearchType, input.Timezone, options)
+	return formatToolResult("querying hourly performance", input.OutputFormat, input.Dimensions, result, err)
 }
 
 func listSites(ctx context.Context, client *searchconsole.Client) (*mcp.CallToolResult, any, error) {
@@ -3,17 +3,28 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/config"
 	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
 )
 
+// TestMain pins the clock, so fixtures with fixed dates stay inside Search
+// Console's retention window as real time moves on.
+func TestMain(m *testing.M) {
+	searchconsole.SetTestNow(time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC))
+	os.Exit(m.Run())
+}
+
 const completeURLInspectionResponse = `{
 	"inspectionResult": {
 		"inspectionResultLink": "https://search.google.com/search-console/inspect/example",
@@ -73,7 +84,29 @@ func TestNewServer_RegistersTools(t *testing.T) {
 	for _, tool := range result.Tools {
 		names = append(names, tool.Name)
 	}
-	for _, want := range []string{"query_search_analytics", "list_sites", "list_sitemaps", "inspect_url"} {
+	for _, want := range []string{
+		"query_search_analytics",
+		"query_hourly_performance",
+		"compare_periods",
+		"top_movers",
+		"find_cannibalization",
+		"striking_distance_keywords",
+		"find_ctr_anomalies",
+		"detect_anomalies",
+		"brand_split",
+		"cluster_queries",
+		"rollup_pages",
+		"find_decaying_content",
+		"forecast_traffic",
+		"new_queries",
+		"lost_queries",
+		"intent_rollup",
+		"query_multiple_sites",
+		"get_data_freshness",
+		"list_sites",
+		"list_sitemaps",
+		"inspect_url",
+	} {
 		found := false
 		for _, n := range names {
 			if n == want {
@@ -99,7 +132,7 @@ func TestQuerySearchAnalytics_Success_ReturnsMarshaledResult(t *testing.T) {
 	defer searchconsole.SetTestAPIBaseURL(srv.URL)()
 
 	client := searchconsole.NewTestClient(srv.Client())
-	result, _, err := querySearchAnalytics(context.Background(), client, querySearchAnalyticsInput{
+	result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, querySearchAnalyticsInput{
 		SiteURL:   "devleader.ca",
 		StartDate: "2025-01-01",
 		EndDate:   "2025-12-31",
@@ -130,7 +163,7 @@ func TestQuerySearchAnalytics_APIError_ReturnsErrorContent(t *testing.T) {
 	defer searchconsole.SetTestAPIBaseURL(srv.URL)()
 
 	client := searchconsole.NewTestClient(srv.Client())
-	result, _, err := querySearchAnalytics(context.Background(), client, querySearchAnalyticsInput{
+	result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, querySearchAnalyticsInput{
 		SiteURL:   "devleader.ca",
 		StartDate: "2025-01-01",
 		EndDate:   "2025-12-31",
@@ -703,6 +736,24 @@ func TestQuerySearchAnalyticsInput_SearchType_IsNotRequired(t *testing.T) {
 	}
 }
 
+// TestQuerySearchAnalyticsInput_PagingFields_AreNotRequired confirms the
+// paging controls are optional, since omitting all of them must keep the
+// original single-request behavior.
+func TestQuerySearchAnalyticsInput_PagingFields_AreNotRequired(t *testing.T) {
+	t.Parallel()
+
+	schema, err := jsonschema.For[querySearchAnalyticsInput](nil)
+	if err != nil {
+		t.Fatalf("schema inference failed: %v", err)
+	}
+
+	for _, field := range []string{"start_row", "all_rows", "max_rows", "dimension_filter_groups"} {
+		if slices.Contains(schema.Required, field) {
+			t.Errorf("%s must not be in schema.Required (got %v)", field, schema.Required)
+		}
+	}
+}
+
 // TestQuerySearchAnalytics_InvalidSearchType_ReturnsErrorContent confirms an
 // invalid search_type is surfaced as CallToolResult content rather than a Go
 // error, matching this repo's established error-handling convention, and
@@ -720,7 +771,7 @@ func TestQuerySearchAnalytics_InvalidSearchType_ReturnsErrorContent(t *testing.T
 	defer searchconsole.SetTestAPIBaseURL(srv.URL)()
 
 	client := searchconsole.NewTestClient(srv.Client())
-	result, _, err := querySearchAnalytics(context.Background(), client, querySearchAnalyticsInput{
+	result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, querySearchAnalyticsInput{
 		SiteURL:    "devleader.ca",
 		StartDate:  "2025-01-01",
 		EndDate:    "2025-12-31",
@@ -792,6 +843,211 @@ func TestNewServer_CallQuerySearchAnalyticsTool_WithSearchType_ViaRealSession(t
 	}
 }
 
+// TestNewServer_CallQuerySearchAnalyticsTool_WithFilters_ViaRealSession
+// confirms dimension_filter_groups flows end-to-end through real schema
+// validation and tool dispatch, including when a client stringifies the array.
+func TestNewServer_CallQuerySearchAnalyticsTool_WithFilters_ViaRealSession(t *testing.T) {
+	var gotBody struct {
+		DimensionFilterGroups []searchconsole.DimensionFilterGroup `json:"dimensionFilterGroups"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer srv.Close()
+	defer searchconsole.SetTestAPIBaseURL(srv.URL)()
+
+	clientSession := connectTestSession(t, newServer(searchconsole.NewTestClient(srv.Client())))
+
+	result, err := clientSession.CallTool(context.Background(), &mcp.CallToolParams{
+		Name: "query_search_analytics",
+		Arguments: map[string]any{
+			"site_url":                "devleader.ca",
+			"start_date":              "2025-01-01",
+			"end_date":                "2025-12-31",
+			"dimensions":              []string{"query"},
+			"dimension_filter_groups": `[{"filters":[{"dimension":"page","operator":"contains","expression":"/blog/"}]}]`,
+		},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("CallTool returned an error result: %+v", result.Content)
+	}
+	if len(gotBody.DimensionFilterGroups) != 1 || len(gotBody.DimensionFilterGroups[0].Filters) != 1 {
+		t.Fatalf("upstream dimensionFilterGroups = %+v, want one group with one filter", gotBody.DimensionFilterGroups)
+	}
+	got := gotBody.DimensionFilterGroups[0].Filters[0]
+	if got.Dimension != "page" || got.Operator != "contains" || got.Expression != "/blog/" {
+		t.Errorf("upstream filter = %+v, want page contains /blog/", got)
+	}
+}
+
+// TestQuerySearchAnalytics_InvalidFilter_ReturnsErrorContent confirms an
+// invalid filter is surfaced as CallToolResult content rather than a Go error.
+func TestQuerySearchAnalytics_InvalidFilter_ReturnsErrorContent(t *testing.T) {
+	client := searchconsole.NewTestClient(http.DefaultClient)
+	result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, querySearchAnalyticsInput{
+		SiteURL:   "devleader.ca",
+		StartDate: "2025-01-01",
+		EndDate:   "2025-12-31",
+		DimensionFilterGroups: []dimensionFilterGroupInput{{
+			Filters: []dimensionFilterInput{{Dimension: "device", Operator: "contains", Expression: "MOBILE"}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("querySearchAnalytics returned a Go error instead of error content: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "querying search analytics:") || !strings.Contains(text, "device") {
+		t.Errorf("result text = %q, want a device operator error", text)
+	}
+}
+
+// connectTestSession connects an in-memory MCP client session to srv and
+// registers cleanup for both ends.
+func connectTestSession(t *testing.T, srv *mcp.Server) *mcp.ClientSession {
+	t.Helper()
+
+	ctx := context.Background()
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	serverSession, err := srv.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server.Connect: %v", err)
+	}
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	mcpClient := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, nil)
+	clientSession, err := mcpClient.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client.Connect: %v", err)
+	}
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	return clientSession
+}
+
+// TestNewServer_CallQueryHourlyPerformanceTool_ViaRealSession confirms the
+// hourly tool requests the hour dimension with hourly_all upstream and returns
+// hours converted to the requested time zone.
+func TestNewServer_CallQueryHourlyPerformanceTool_ViaRealSession(t *testing.T) {
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"rows":[{"keys":["2025-06-10T09:00:00-07:00"],"clicks":4,"impressions":40,"ctr":0.1,"position":2}]}`))
+	}))
+	defer srv.Close()
+	defer searchconsole.SetTestAPIBaseURL(srv.URL)()
+
+	clientSession := connectTestSession(t, newServer(searchconsole.NewTestClient(srv.Client())))
+
+	result, err := clientSession.CallTool(context.Background(), &mcp.CallToolParams{
+		Name: "query_hourly_performance",
+		Arguments: map[string]any{
+			"site_url":   "devleader.ca",
+			"start_date": "2025-06-09",
+			"end_date":   "2025-06-10",
+			"timezone":   "Europe/London",
+		},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("CallTool returned an error result: %+v", result.Content)
+	}
+	if gotBody["dataState"] != "hourly_all" {
+		t.Errorf(`upstream "dataState" = %v, want hourly_all`, gotBody["dataState"])
+	}
+
+	var payload searchconsole.HourlyPerformanceResponse
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if len(payload.Rows) != 1 || payload.Rows[0].Hour != "2025-06-10T17:00:00+01:00" {
+		t.Errorf("rows = %+v, want one row at 2025-06-10T17:00:00+01:00", payload.Rows)
+	}
+}
+
+// TestQuerySearchAnalytics_DateRange_ResolvesAndEchoes confirms a named
+// date_range is resolved to concrete dates before the upstream call, and that
+// the response echoes both the range and the resolved dates.
+func TestQuerySearchAnalytics_DateRange_ResolvesAndEchoes(t *testing.T) {
+	defer searchconsole.SetTestNow(time.Date(2026, 3, 15, 20, 0, 0, 0, time.UTC))()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"rows":[]}`))
+	}))
+	defer srv.Close()
+	defer searchconsole.SetTestAPIBaseURL(srv.URL)()
+
+	client := searchconsole.NewTestClient(srv.Client())
+	result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, querySearchAnalyticsInput{
+		SiteURL:   "devleader.ca",
+		DateRange: "last_7_days",
+	})
+	if err != nil {
+		t.Fatalf("querySearchAnalytics: %v", err)
+	}
+
+	if gotBody["startDate"] != "2026-03-07" || gotBody["endDate"] != "2026-03-13" {
+		t.Errorf("upstream dates = %v..%v, want 2026-03-07..2026-03-13", gotBody["startDate"], gotBody["endDate"])
+	}
+	var payload searchconsole.SearchAnalyticsResponse
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if payload.DateRange != "last_7_days" || payload.StartDate != "2026-03-07" || payload.EndDate != "2026-03-13" {
+		t.Errorf("echoed range = %q %s..%s, want last_7_days 2026-03-07..2026-03-13",
+			payload.DateRange, payload.StartDate, payload.EndDate)
+	}
+}
+
+func TestQuerySearchAnalytics_DateInputConflicts_ReturnErrorContent(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  querySearchAnalyticsInput
+		marker string
+	}{
+		{
+			name:   "range with explicit dates",
+			input:  querySearchAnalyticsInput{SiteURL: "devleader.ca", DateRange: "last_7_days", StartDate: "2025-01-01"},
+			marker: "date_range cannot be combined with start_date or end_date",
+		},
+		{
+			name:   "no dates at all",
+			input:  querySearchAnalyticsInput{SiteURL: "devleader.ca"},
+			marker: "start_date and end_date are required unless date_range is set",
+		},
+		{
+			name:   "unknown range",
+			input:  querySearchAnalyticsInput{SiteURL: "devleader.ca", DateRange: "forever"},
+			marker: "invalid date_range",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := searchconsole.NewTestClient(http.DefaultClient)
+			result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, tt.input)
+			if err != nil {
+				t.Fatalf("querySearchAnalytics returned a Go error instead of error content: %v", err)
+			}
+			text := result.Content[0].(*mcp.TextContent).Text
+			if !strings.Contains(text, tt.marker) {
+				t.Errorf("result text = %q, want it to contain %q", text, tt.marker)
+			}
+		})
+	}
+}
+
 // TestListSites_InputSchema validates the production listSitesInputSchema variable to
 // ensure it is compatible with strict MCP clients (e.g. Copilot CLI) that require
 // explicit properties, required, and additionalProperties fields.
@@ -831,3 +1087,196 @@ func TestListSites_InputSchema(t *testing.T) {
 		}
 	}
 }
+
+func TestQuerySearchAnalytics_NamedDimensions_ReplacesKeysWithNamedFields(t *testing.T) {
+	srv := newPeriodServer(t, map[string]string{
+		"2026-02-01": `[{"keys":["https://www.devleader.ca/blazor","blazor forms"],"clicks":10,"impressions":200,"ctr":0.05,"position":4.5}]`,
+	}, nil)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, querySearchAnalyticsInput{
+		SiteURL:         "devleader.ca",
+		StartDate:       "2026-02-01",
+		EndDate:         "2026-02-28",
+		Dimensions:      []string{"page", "query"},
+		NamedDimensions: true,
+	})
+	if err != nil {
+		t.Fatalf("querySearchAnalytics: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+
+	var payload struct {
+		SiteURL  string           `json:"siteUrl"`
+		RowCount int              `json:"rowCount"`
+		Rows     []map[string]any `json:"rows"`
+	}
+	if err := json.Unmarshal([]byte(text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if payload.SiteURL == "" || payload.RowCount != 1 {
+		t.Errorf("payload = %+v, want the response fields kept", payload)
+	}
+	if len(payload.Rows) != 1 {
+		t.Fatalf("rows = %v, want 1", payload.Rows)
+	}
+	row := payload.Rows[0]
+	if row["query"] != "blazor forms" || row["page"] != "https://www.devleader.ca/blazor" || row["clicks"] != float64(10) {
+		t.Errorf("row = %v, want named query and page fields", row)
+	}
+	if strings.Contains(text, `"keys"`) {
+		t.Errorf("named output still contains keys: %s", text)
+	}
+}
+
+func TestQuerySearchAnalytics_MaxOutputTokens_SummarizesToFitBudget(t *testing.T) {
+	var rows []string
+	for i := range 200 {
+		rows = append(rows, fmt.Sprintf(`{"keys":["query number %d"],"clicks":%d,"impressions":%d,"ctr":0.01,"position":5}`, i, i, 100*i))
+	}
+	srv := newPeriodServer(t, map[string]string{"2026-02-01": "[" + strings.Join(rows, ",") + "]"}, nil)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, querySearchAnalyticsInput{
+		SiteURL:         "devleader.ca",
+		StartDate:       "2026-02-01",
+		EndDate:         "2026-02-28",
+		Dimensions:      []string{"query"},
+		MaxOutputTokens: 1000,
+	})
+	if err != nil {
+		t.Fatalf("querySearchAnalytics: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if len(text) > 4000 {
+		t.Errorf("result is %d bytes, want at most 4000", len(text))
+	}
+
+	var payload searchconsole.SearchAnalyticsResponse
+	if err := json.Unmarshal([]byte(text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	s := payload.Summary
+	if s == nil || !s.Summarized || s.MaxOutputTokens != 1000 || s.SortedBy != "clicks" {
+		t.Fatalf("summary = %+v, want a clicks summary", s)
+	}
+	if len(payload.Rows) == 0 || s.RowsShown != len(payload.Rows) || s.RowsShown+s.RowsOmitted != 200 {
+		t.Errorf("rowsShown = %d, rowsOmitted = %d, rows = %d", s.RowsShown, s.RowsOmitted, len(payload.Rows))
+	}
+	if payload.Rows[0].Keys[0] != "query number 199" {
+		t.Errorf("first row = %v, want the top row by clicks", payload.Rows[0].Keys)
+	}
+	if s.Totals.Clicks != 199*200/2 || s.Other.Clicks+sumClicks(payload.Rows) != s.Totals.Clicks {
+		t.Errorf("other = %+v, totals = %+v, want shown plus other to equal totals", s.Other, s.Totals)
+	}
+}
+
+func TestQuerySearchAnalytics_MaxOutputTokens_UnderBudgetIsNotSummarized(t *testing.T) {
+	srv := newPeriodServer(t, map[string]string{
+		"2026-02-01": `[{"keys":["blazor"],"clicks":10,"impressions":200,"ctr":0.05,"position":4.5}]`,
+	}, nil)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, querySearchAnalyticsInput{
+		SiteURL:         "devleader.ca",
+		StartDate:       "2026-02-01",
+		EndDate:         "2026-02-28",
+		Dimensions:      []string{"query"},
+		MaxOutputTokens: 1000,
+	})
+	if err != nil {
+		t.Fatalf("querySearchAnalytics: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; strings.Contains(text, `"summary"`) {
+		t.Errorf("result = %s, want no summary", text)
+	}
+}
+
+func TestQuerySearchAnalytics_InvalidOutputBudget_ReturnsErrorContent(t *testing.T) {
+	for name, input := range map[string]querySearchAnalyticsInput{
+		"invalid summary_metric":       {SummaryMetric: "ctr", MaxOutputTokens: 100},
+		"invalid max_output_tokens -1": {MaxOutputTokens: -1},
+	} {
+		var requests []map[string]any
+		srv := newPeriodServer(t, nil, &requests)
+		client := searchconsole.NewTestClient(srv.Client())
+		input.SiteURL, input.StartDate, input.EndDate = "devleader.ca", "2026-02-01", "2026-02-28"
+
+		result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, input)
+		if err != nil {
+			t.Fatalf("querySearchAnalytics: %v", err)
+		}
+		if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, name) {
+			t.Errorf("result = %s, want %q", text, name)
+		}
+		if len(requests) != 0 {
+			t.Errorf("%s: made %d requests, want 0", name, len(requests))
+		}
+	}
+}
+
+func sumClicks(rows []searchconsole.SearchAnalyticsRow) float64 {
+	var total float64
+	for _, row := range rows {
+		total += row.Clicks
+	}
+	return total
+}
+
+func TestQuerySearchAnalytics_FillDateGaps_AddsFilledRows(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, map[string]string{
+		"2026-02-01": `[{"keys":["2026-02-01"],"clicks":3,"impressions":30,"ctr":0.1,"position":5},{"keys":["2026-02-03"],"clicks":1,"impressions":20,"ctr":0.05,"position":6}]`,
+	}, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, querySearchAnalyticsInput{
+		SiteURL:      "devleader.ca",
+		StartDate:    "2026-02-01",
+		EndDate:      "2026-02-03",
+		Dimensions:   []string{"date"},
+		FillDateGaps: true,
+	})
+	if err != nil {
+		t.Fatalf("querySearchAnalytics: %v", err)
+	}
+	if len(requests) != 1 || requests[0]["rowLimit"] != float64(25000) {
+		t.Errorf("requests = %v, want every row paged through", requests)
+	}
+	var payload searchconsole.SearchAnalyticsResponse
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if payload.RowCount != 3 || len(payload.Rows) != 3 {
+		t.Fatalf("rows = %+v, want 3", payload.Rows)
+	}
+	if r := payload.Rows[1]; r.Keys[0] != "2026-02-02" || !r.Filled || r.Impressions != 0 {
+		t.Errorf("rows[1] = %+v, want a filled row for 2026-02-02", r)
+	}
+	if payload.Rows[0].Filled || payload.Rows[2].Filled {
+		t.Errorf("rows = %+v, want only 2026-02-02 filled", payload.Rows)
+	}
+}
+
+func TestQuerySearchAnalytics_FillDateGapsWithoutDate_ReturnsErrorContent(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, querySearchAnalyticsInput{
+		SiteURL:      "devleader.ca",
+		StartDate:    "2026-02-01",
+		EndDate:      "2026-02-03",
+		Dimensions:   []string{"query"},
+		FillDateGaps: true,
+	})
+	if err != nil {
+		t.Fatalf("querySearchAnalytics: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "fill_date_gaps requires the date dimension") {
+		t.Errorf("result = %s, want a date dimension error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("made %d requests, want 0", len(requests))
+	}
+}
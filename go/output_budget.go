@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// bytesPerToken approximates how many bytes of JSON, CSV, or Markdown make up
+// one model token. It errs towards overestimating tokens.
+const bytesPerToken = 4
+
+// validateOutputBudget checks the max_output_tokens and summary_metric
+// arguments of the tools that return raw search analytics rows,
+// query_search_analytics and query_multiple_sites. The analysis tools return
+// ranked results already capped by their own limits.
+func validateOutputBudget(maxOutputTokens int, summaryMetric string) error {
+	if maxOutputTokens < 0 {
+		return fmt.Errorf("invalid max_output_tokens %d: must not be negative", maxOutputTokens)
+	}
+	if summaryMetric != analysis.SummaryMetricClicks && summaryMetric != analysis.SummaryMetricImpressions {
+		return fmt.Errorf("invalid summary_metric %q: must be clicks or impressions", summaryMetric)
+	}
+	return nil
+}
+
+func estimateTokens(text string) int {
+	return (len(text) + bytesPerToken - 1) / bytesPerToken
+}
+
+// fitOutputBudget renders the full result with render(-1) and, when that
+// would exceed maxOutputTokens, renders instead the summary keeping as many
+// rows as fit, render(keep) for keep below maxRows. The summary without any
+// rows is returned even if it does not fit. A maxOutputTokens of 0 means no
+// budget.
+func fitOutputBudget(maxOutputTokens, maxRows int, render func(keep int) (string, error)) (string, error) {
+	text, err := render(-1)
+	if err != nil || maxOutputTokens == 0 || estimateTokens(text) <= maxOutputTokens {
+		return text, err
+	}
+
+	best, err := render(0)
+	if err != nil {
+		return "", err
+	}
+	// Output grows with every row kept, so binary search for the most rows
+	// that still fit.
+	low, high := 1, maxRows-1
+	for low <= high {
+		keep := (low + high) / 2
+		text, err := render(keep)
+		if err != nil {
+			return "", err
+		}
+		if estimateTokens(text) <= maxOutputTokens {
+			best, low = text, keep+1
+		} else {
+			high = keep - 1
+		}
+	}
+	return best, nil
+}
+
+// summarizeForBudget returns resp cut down to its top keep rows by
+// summaryMetric, as fitOutputBudget asks for, or resp itself when keep is
+// negative.
+func summarizeForBudget(
+	resp *searchconsole.SearchAnalyticsResponse,
+	summaryMetric string,
+	keep, maxOutputTokens int,
+) (*searchconsole.SearchAnalyticsResponse, error) {
+	if keep < 0 {
+		return resp, nil
+	}
+	summarized, err := analysis.SummarizeRows(resp, summaryMetric, keep)
+	if err != nil {
+		return nil, err
+	}
+	summarized.Summary.MaxOutputTokens = maxOutputTokens
+	return summarized, nil
+}
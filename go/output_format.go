@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Values of the output_format parameter the analytics tools accept.
+const (
+	outputFormatJSON     = "json"
+	outputFormatCompact  = "compact"
+	outputFormatCSV      = "csv"
+	outputFormatMarkdown = "markdown"
+)
+
+// outputFormatDescription is appended to the description of every tool that
+// accepts output_format.
+const outputFormatDescription = " output_format controls the response encoding: json (default) is the full object; compact turns every list of rows into {\"columns\": [...], \"rows\": [[...], ...]}; csv and markdown render each list as a table, after the remaining fields as \"# name: value\" comment lines (csv) or a bullet list (markdown). Outside json, each row's keys are expanded into columns named after its dimensions, nested objects are flattened into dotted column names such as clicks.current, and errors are still returned as JSON."
+
+func validateOutputFormat(format string) error {
+	switch format {
+	case "", outputFormatJSON, outputFormatCompact, outputFormatCSV, outputFormatMarkdown:
+		return nil
+	}
+	return fmt.Errorf("invalid output_format %q: must be json, compact, csv, or markdown", format)
+}
+
+// formatToolResult is marshalToolResult for tools that accept output_format.
+// dimensions names the entries of each row's keys array, in order. The format
+// must already have passed validateOutputFormat.
+func formatToolResult[T any](
+	operation string,
+	format string,
+	dimensions []string,
+	result T,
+	err error,
+) (*mcp.CallToolResult, any, error) {
+	if err != nil || format == "" || format == outputFormatJSON {
+		return marshalToolResult(operation, result, err)
+	}
+	text, err := renderToolResult(format, dimensions, result)
+	if err != nil {
+		return marshalToolResult(operation, result, err)
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil, nil
+}
+
+// renderToolResult encodes a successful result in format, as
+// formatToolResult would return it.
+func renderToolResult(format string, dimensions []string, result any) (string, error) {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("marshalling result: %w", err)
+	}
+	if format == "" || format == outputFormatJSON {
+		return string(b), nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	value, err := decodeOrdered(decoder)
+	if err != nil {
+		return "", fmt.Errorf("re-reading result: %w", err)
+	}
+	doc, ok := value.(jsonObject)
+	if !ok {
+		return string(b), nil
+	}
+
+	var text string
+	switch format {
+	case outputFormatCompact:
+		text, err = renderCompact(doc, dimensions)
+	case outputFormatCSV:
+		text, err = renderCSV(doc, dimensions)
+	case outputFormatMarkdown:
+		text = renderMarkdown(doc, dimensions)
+	}
+	if err != nil {
+		return "", fmt.Errorf("formatting result as %s: %w", format, err)
+	}
+	return text, nil
+}
+
+// jsonField is one member of a jsonObject.
+type jsonField struct {
+	Key   string
+	Value any
+}
+
+// jsonObject is a decoded JSON object that keeps its members in order, so the
+// columns of a formatted result follow the fields of the Go type.
+type jsonObject []jsonField
+
+// MarshalJSON encodes o with its members in order.
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// decodeOrdered reads the next JSON value from decoder, decoding objects as
+// jsonObject, arrays as []any, and numbers as json.Number.
+func decodeOrdered(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+	switch delim {
+	case '{':
+		object := jsonObject{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, jsonField{Key: key.(string), Value: value})
+		}
+		_, err = decoder.Token()
+		return object, err
+	default:
+		array := []any{}
+		for decoder.More() {
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = decoder.Token()
+		return array, err
+	}
+}
+
+// outputTable is one list of rows from a result, flattened into columns.
+type outputTable struct {
+	Columns []string `json:"columns"`
+	Rows    [][]any  `json:"rows"`
+}
+
+// asTable reports whether value is a list of rows -- an array of objects, or
+// an empty array -- and if so flattens it.
+func asTable(value any, dimensions []string) (outputTable, bool) {
+	array, ok := value.([]any)
+	if !ok {
+		return outputTable{}, false
+	}
+	objects := make([]jsonObject, len(array))
+	for i, element := range array {
+		if objects[i], ok = element.(jsonObject); !ok {
+			return outputTable{}, false
+		}
+	}
+
+	table := outputTable{Columns: []string{}, Rows: make([][]any, len(objects))}
+	index := make(map[string]int)
+	flat := make([]jsonObject, len(objects))
+	for i, object := range objects {
+		flat[i] = flattenObject(object, dimensions, "")
+		for _, field := range flat[i] {
+			if _, ok := index[field.Key]; !ok {
+				index[field.Key] = len(table.Columns)
+				table.Columns = append(table.Columns, field.Key)
+			}
+		}
+	}
+	for i, object := range flat {
+		row := make([]any, len(table.Columns))
+		for _, field := range object {
+			row[index[field.Key]] = field.Value
+		}
+		table.Rows[i] = row
+	}
+	return table, true
+}
+
+// flattenObject flattens nested objects into dotted keys and, for a row,
+// expands its keys array into one field per dimension. Keys that do not line
+// up with dimensions are named key1, key2, and so on.
+func flattenObject(object jsonObject, dimensions []string, prefix string) jsonObject {
+	var flat jsonObject
+	for _, field := range object {
+		name := prefix + field.Key
+		if keys, ok := field.Value.([]any); ok && field.Key == "keys" && prefix == "" && dimensions != nil {
+			for i, key := range keys {
+				column := "key" + strconv.Itoa(i+1)
+				if len(keys) == len(dimensions) {
+					column = dimensions[i]
+				}
+				flat = append(flat, jsonField{Key: column, Value: key})
+			}
+			continue
+		}
+		if nested, ok := field.Value.(jsonObject); ok {
+			flat = append(flat, flattenObject(nested, nil, name+".")...)
+			continue
+		}
+		flat = append(flat, jsonField{Key: name, Value: field.Value})
+	}
+	return flat
+}
+
+func renderCompact(doc jsonObject, dimensions []string) (string, error) {
+	out := make(jsonObject, len(doc))
+	for i, field := range doc {
+		out[i] = field
+		if table, ok := asTable(field.Value, dimensions); ok {
+			out[i].Value = table
+		}
+	}
+	b, err := json.Marshal(out)
+	return string(b), err
+}
+
+// splitTables separates the lists of rows in doc from its other fields, which
+// are flattened.
+func splitTables(doc jsonObject, dimensions []string) (fields jsonObject, names []string, tables []outputTable) {
+	var rest jsonObject
+	for _, field := range doc {
+		if table, ok := asTable(field.Value, dimensions); ok {
+			names = append(names, field.Key)
+			tables = append(tables, table)
+			continue
+		}
+		rest = append(rest, field)
+	}
+	return flattenObject(rest, nil, ""), names, tables
+}
+
+func renderCSV(doc jsonObject, dimensions []string) (string, error) {
+	fields, names, tables := splitTables(doc, dimensions)
+	var buf bytes.Buffer
+	for _, field := range fields {
+		fmt.Fprintf(&buf, "# %s: %s\n", field.Key, cellText(field.Value))
+	}
+	for i, table := range tables {
+		fmt.Fprintf(&buf, "\n# %s\n", names[i])
+		writer := csv.NewWriter(&buf)
+		if err := writer.Write(table.Columns); err != nil {
+			return "", err
+		}
+		for _, row := range table.Rows {
+			record := make([]string, len(row))
+			for j, value := range row {
+				record[j] = cellText(value)
+			}
+			if err := writer.Write(record); err != nil {
+				return "", err
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+func renderMarkdown(doc jsonObject, dimensions []string) string {
+	fields, names, tables := splitTables(doc, dimensions)
+	var buf strings.Builder
+	for _, field := range fields {
+		fmt.Fprintf(&buf, "- %s: %s\n", field.Key, markdownCell(field.Value))
+	}
+	for i, table := range tables {
+		fmt.Fprintf(&buf, "\n### %s\n\n", names[i])
+		if len(table.Rows) == 0 {
+			buf.WriteString("_No rows._\n")
+			continue
+		}
+		buf.WriteString("| " + strings.Join(table.Columns, " | ") + " |\n")
+		buf.WriteString("|" + strings.Repeat(" --- |", len(table.Columns)) + "\n")
+		for _, row := range table.Rows {
+			cells := make([]string, len(row))
+			for j, value := range row {
+				cells[j] = markdownCell(value)
+			}
+			buf.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+	}
+	return buf.String()
+}
+
+// cellText renders one value as plain text. Lists of plain values are joined
+// with "; "; anything more complex stays JSON.
+func cellText(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		return strconv.FormatBool(value)
+	case []any:
+		parts := make([]string, len(value))
+		for i, element := range value {
+			switch element.(type) {
+			case jsonObject, []any:
+				b, _ := json.Marshal(value)
+				return string(b)
+			}
+			parts[i] = cellText(element)
+		}
+		return strings.Join(parts, "; ")
+	}
+	b, _ := json.Marshal(value)
+	return string(b)
+}
+
+func markdownCell(value any) string {
+	text := strings.ReplaceAll(cellText(value), "|", `\|`)
+	return strings.ReplaceAll(text, "\n", " ")
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/config"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const outputFormatRows = `[
+	{"keys":["blazor, forms","https://www.devleader.ca/blazor"],"clicks":10,"impressions":200,"ctr":0.05,"position":4.5},
+	{"keys":["a|b","https://www.devleader.ca/pipes"],"clicks":1,"impressions":50,"ctr":0.02,"position":9}
+]`
+
+func queryWithOutputFormat(t *testing.T, format string) string {
+	t.Helper()
+	srv := newPeriodServer(t, map[string]string{"2026-02-01": outputFormatRows}, nil)
+	client := searchconsole.NewTestClient(srv.Client())
+	result, _, err := querySearchAnalytics(context.Background(), client, config.AnalysisConfig{}, querySearchAnalyticsInput{
+		SiteURL:      "devleader.ca",
+		StartDate:    "2026-02-01",
+		EndDate:      "2026-02-28",
+		Dimensions:   []string{"query", "page"},
+		OutputFormat: format,
+	})
+	if err != nil {
+		t.Fatalf("querySearchAnalytics: %v", err)
+	}
+	return result.Content[0].(*mcp.TextContent).Text
+}
+
+func TestOutputFormat_Compact_TurnsRowsIntoNamedColumns(t *testing.T) {
+	text := queryWithOutputFormat(t, "compact")
+
+	var payload struct {
+		SiteURL string `json:"siteUrl"`
+		Rows    struct {
+			Columns []string `json:"columns"`
+			Rows    [][]any  `json:"rows"`
+		} `json:"rows"`
+	}
+	if err := json.Unmarshal([]byte(text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v\n%s", err, text)
+	}
+	if payload.SiteURL == "" {
+		t.Error("siteUrl missing from compact output")
+	}
+	if got := strings.Join(payload.Rows.Columns, ","); got != "query,page,clicks,impressions,ctr,position" {
+		t.Errorf("columns = %s, want query,page,clicks,impressions,ctr,position", got)
+	}
+	if len(payload.Rows.Rows) != 2 || payload.Rows.Rows[0][0] != "blazor, forms" || payload.Rows.Rows[0][2] != float64(10) {
+		t.Errorf("rows = %v", payload.Rows.Rows)
+	}
+	if strings.Contains(text, `"keys"`) {
+		t.Errorf("compact output still contains keys arrays: %s", text)
+	}
+}
+
+func TestOutputFormat_CSV_WritesFieldsAsCommentsAndQuotesCells(t *testing.T) {
+	text := queryWithOutputFormat(t, "csv")
+
+	header, table, found := strings.Cut(text, "\n# rows\n")
+	if !found {
+		t.Fatalf("csv output has no rows section:\n%s", text)
+	}
+	if !strings.Contains(header, "# startDate: 2026-02-01\n") || !strings.Contains(header, "# dimensions: query; page\n") {
+		t.Errorf("csv header = %q, want startDate and dimensions comments", header)
+	}
+	records, err := csv.NewReader(strings.NewReader(table)).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv table: %v\n%s", err, table)
+	}
+	if len(records) != 3 || strings.Join(records[0], ",") != "query,page,clicks,impressions,ctr,position" {
+		t.Fatalf("records = %v", records)
+	}
+	if records[1][0] != "blazor, forms" || records[1][4] != "0.05" {
+		t.Errorf("first row = %v, want the comma kept inside the query cell", records[1])
+	}
+}
+
+func TestOutputFormat_Markdown_RendersTableAndEscapesPipes(t *testing.T) {
+	text := queryWithOutputFormat(t, "markdown")
+
+	for _, want := range []string{
+		"- siteUrl: ",
+		"### rows\n\n| query | page | clicks | impressions | ctr | position |\n| --- | --- | --- | --- | --- | --- |\n",
+		`| a\|b | https://www.devleader.ca/pipes | 1 | 50 | 0.02 | 9 |`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("markdown output missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestOutputFormat_Compact_FlattensNestedComparisons(t *testing.T) {
+	srv := newPeriodServer(t, map[string]string{
+		"2026-02-08": `[{"keys":["/a"],"clicks":40,"impressions":400,"ctr":0.1,"position":3}]`,
+		"2026-02-01": `[{"keys":["/a"],"clicks":10,"impressions":300,"ctr":0.03,"position":5}]`,
+	}, nil)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := topMovers(context.Background(), client, topMoversInput{
+		SiteURL:      "devleader.ca",
+		StartDate:    "2026-02-08",
+		EndDate:      "2026-02-14",
+		Dimension:    "page",
+		OutputFormat: "compact",
+	})
+	if err != nil {
+		t.Fatalf("topMovers: %v", err)
+	}
+
+	var payload struct {
+		Totals  map[string]any `json:"totals"`
+		Winners outputTable    `json:"winners"`
+		Losers  outputTable    `json:"losers"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if len(payload.Winners.Columns) < 3 || payload.Winners.Columns[0] != "page" || payload.Winners.Columns[2] != "clicks.current" {
+		t.Errorf("winner columns = %v, want page, status, clicks.current, ...", payload.Winners.Columns)
+	}
+	if len(payload.Losers.Rows) != 0 || payload.Losers.Columns == nil {
+		t.Errorf("losers = %+v, want an empty table", payload.Losers)
+	}
+	if _, ok := payload.Totals["clicks"].(map[string]any); !ok {
+		t.Errorf("totals = %v, want it left as a nested object", payload.Totals)
+	}
+}
+
+func TestOutputFormat_Invalid_ReturnsErrorContentWithoutHTTPCall(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := clusterQueries(context.Background(), client, clusterQueriesInput{
+		SiteURL:      "devleader.ca",
+		DateRange:    "last_28_days",
+		OutputFormat: "xml",
+	})
+	if err != nil {
+		t.Fatalf("clusterQueries returned a Go error instead of error content: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "clustering queries:") || !strings.Contains(text, `invalid output_format \"xml\"`) {
+		t.Errorf("result text = %q, want an invalid output_format error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected 0 HTTP calls, got %d", len(requests))
+	}
+}
+
+func TestOutputFormat_RenderingFailure_ReturnsErrorContent(t *testing.T) {
+	result, _, err := formatToolResult("finding top movers", outputFormatCSV, nil, map[string]float64{"clicks": math.NaN()}, nil)
+	if err != nil {
+		t.Fatalf("formatToolResult returned a Go error instead of error content: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, `"error":"finding top movers: marshalling result:`) {
+		t.Errorf("result text = %q, want a marshalling error", text)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"slices"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// propertyConfigFor returns the entry of a per-property section of the
+// analysis config that applies to siteURL: an exact key first, then any key
+// for the same domain, then "*".
+func propertyConfigFor[T any](entries map[string]T, siteURL string) (T, bool) {
+	if entry, ok := entries[siteURL]; ok {
+		return entry, true
+	}
+	domain := searchconsole.SiteDomain(siteURL)
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	// Sorted so that two keys for the same domain resolve the same way every time.
+	slices.Sort(keys)
+	for _, key := range keys {
+		if key != "*" && searchconsole.SiteDomain(key) == domain {
+			return entries[key], true
+		}
+	}
+	entry, ok := entries["*"]
+	return entry, ok
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ncosentino/google-search-console-mcp/go/internal/config"
+)
+
+func TestPropertyConfigFor_MatchesByDomainThenWildcard(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.AnalysisConfig{Brands: map[string]config.BrandConfig{
+		"sc-domain:devleader.ca": {Terms: []string{"devleader"}},
+		"*":                      {Terms: []string{"fallback"}},
+	}}
+
+	for siteURL, want := range map[string]string{
+		"sc-domain:devleader.ca":    "devleader",
+		"devleader.ca":              "devleader",
+		"https://www.devleader.ca/": "devleader",
+		"brandghost.ai":             "fallback",
+	} {
+		brand, ok := propertyConfigFor(cfg.Brands, siteURL)
+		if !ok || len(brand.Terms) != 1 || brand.Terms[0] != want {
+			t.Errorf("propertyConfigFor(%q) = %+v, %v; want terms [%s]", siteURL, brand, ok, want)
+		}
+	}
+
+	if _, ok := propertyConfigFor(config.AnalysisConfig{}.Brands, "devleader.ca"); ok {
+		t.Error("propertyConfigFor with no config reported a match")
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const defaultQueryChangeLimit = 50
+
+// queryChangesInput is the input schema for the new_queries and lost_queries
+// tools.
+type queryChangesInput struct {
+	SiteURL               string                      `json:"site_url"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	DateRange             string                      `json:"date_range,omitempty"`
+	Comparison            string                      `json:"comparison,omitempty"`
+	ByPage                bool                        `json:"by_page,omitempty"`
+	MinImpressions        float64                     `json:"min_impressions,omitempty"`
+	Limit                 int                         `json:"limit,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	MaxRows               int                         `json:"max_rows,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+func newQueries(ctx context.Context, client *searchconsole.Client, input queryChangesInput) (*mcp.CallToolResult, any, error) {
+	return queryChanges(ctx, client, "finding new queries", analysis.QueryChangeNew, input)
+}
+
+func lostQueries(ctx context.Context, client *searchconsole.Client, input queryChangesInput) (*mcp.CallToolResult, any, error) {
+	return queryChanges(ctx, client, "finding lost queries", analysis.QueryChangeLost, input)
+}
+
+// queryChanges fetches every query (or query and page) row of both periods,
+// so a row beyond the first page of results is never mistaken for new or
+// lost, and returns the rows of kind change.
+func queryChanges(
+	ctx context.Context,
+	client *searchconsole.Client,
+	operation string,
+	change string,
+	input queryChangesInput,
+) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*analysis.QueryChangeResult](operation, nil, err)
+	}
+	options := analysis.QueryChangeOptions{
+		MinImpressions: input.MinImpressions,
+		Limit:          input.Limit,
+	}
+	if options.Limit == 0 {
+		options.Limit = defaultQueryChangeLimit
+	}
+	if err := options.Validate(); err != nil {
+		return marshalToolResult[*analysis.QueryChangeResult](operation, nil, err)
+	}
+
+	dimensions := []string{"query"}
+	if input.ByPage {
+		dimensions = append(dimensions, "page")
+	}
+	current, previous, comparison, err := queryPeriodPair(ctx, client, periodQuery{
+		SiteURL:    input.SiteURL,
+		StartDate:  input.StartDate,
+		EndDate:    input.EndDate,
+		DateRange:  input.DateRange,
+		Comparison: input.Comparison,
+		Dimensions: dimensions,
+		SearchType: input.SearchType,
+		Options: searchconsole.SearchAnalyticsOptions{
+			DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+			MaxRows:               input.MaxRows,
+		},
+	})
+	if err != nil {
+		return marshalToolResult[*analysis.QueryChangeResult](operation, nil, err)
+	}
+	result, err := analysis.QueryChanges(current, previous, comparison, change, options)
+	return formatToolResult(operation, input.OutputFormat, dimensions, result, err)
+}
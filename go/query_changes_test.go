@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func queryChangesServer(t *testing.T, requests *[]map[string]any) *searchconsole.Client {
+	t.Helper()
+	srv := newPeriodServer(t, map[string]string{
+		"2026-02-08": `[{"keys":["blazor","/a"],"clicks":10,"impressions":300,"ctr":0.03,"position":3},{"keys":["blazor hosting","/b"],"clicks":4,"impressions":90,"ctr":0.04,"position":6}]`,
+		"2026-02-01": `[{"keys":["blazor","/a"],"clicks":12,"impressions":280,"ctr":0.04,"position":3},{"keys":["razor pages","/c"],"clicks":7,"impressions":70,"ctr":0.1,"position":5}]`,
+	}, requests)
+	return searchconsole.NewTestClient(srv.Client())
+}
+
+func TestNewQueries_PagesThroughBothPeriodsByPage(t *testing.T) {
+	var requests []map[string]any
+	client := queryChangesServer(t, &requests)
+
+	result, _, err := newQueries(context.Background(), client, queryChangesInput{
+		SiteURL:   "devleader.ca",
+		StartDate: "2026-02-08",
+		EndDate:   "2026-02-14",
+		ByPage:    true,
+	})
+	if err != nil {
+		t.Fatalf("newQueries: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("request count = %d, want 2", len(requests))
+	}
+	for _, req := range requests {
+		if req["rowLimit"] != float64(25000) || jsonString(req["dimensions"]) != `["query","page"]` {
+			t.Errorf("request = %v, want every query and page row", req)
+		}
+	}
+	var payload analysis.QueryChangeResult
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if payload.Change != "new" || len(payload.Rows) != 1 || payload.Rows[0].Keys[0] != "blazor hosting" || payload.Rows[0].Keys[1] != "/b" {
+		t.Errorf("payload = %+v, want blazor hosting on /b", payload)
+	}
+}
+
+func TestLostQueries_ReturnsQueriesGoneFromCurrentPeriod(t *testing.T) {
+	var requests []map[string]any
+	client := queryChangesServer(t, &requests)
+
+	result, _, err := lostQueries(context.Background(), client, queryChangesInput{
+		SiteURL:   "devleader.ca",
+		StartDate: "2026-02-08",
+		EndDate:   "2026-02-14",
+	})
+	if err != nil {
+		t.Fatalf("lostQueries: %v", err)
+	}
+	var payload analysis.QueryChangeResult
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	// The server ignores dimensions, so the keys still carry the page.
+	if payload.Change != "lost" || payload.Count != 1 || payload.Rows[0].Keys[0] != "razor pages" || payload.Clicks != 7 {
+		t.Errorf("payload = %+v, want razor pages", payload)
+	}
+	if jsonString(requests[0]["dimensions"]) != `["query"]` {
+		t.Errorf("dimensions = %v, want [query]", requests[0]["dimensions"])
+	}
+}
+
+func TestQueryChanges_InvalidOptions_ReturnsErrorContent(t *testing.T) {
+	var requests []map[string]any
+	client := queryChangesServer(t, &requests)
+
+	result, _, err := lostQueries(context.Background(), client, queryChangesInput{SiteURL: "devleader.ca", DateRange: "last_28_days", Limit: -5})
+	if err != nil {
+		t.Fatalf("lostQueries: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "finding lost queries: invalid limit -5") {
+		t.Errorf("result = %s, want an invalid limit error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("request count = %d, want 0", len(requests))
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+// queryMultipleSitesInput is the input schema for the query_multiple_sites tool.
+type queryMultipleSitesInput struct {
+	SiteURLs              []string                    `json:"site_urls,omitempty"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	DateRange             string                      `json:"date_range,omitempty"`
+	Dimensions            []string                    `json:"dimensions,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	RowLimit              int                         `json:"row_limit,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	AllRows               bool                        `json:"all_rows,omitempty"`
+	MaxRows               int                         `json:"max_rows,omitempty"`
+	DataState             string                      `json:"data_state,omitempty"`
+	TotalsOnly            bool                        `json:"totals_only,omitempty"`
+	MaxOutputTokens       int                         `json:"max_output_tokens,omitempty"`
+	SummaryMetric         string                      `json:"summary_metric,omitempty"`
+	Concurrency           int                         `json:"concurrency,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+func queryMultipleSites(ctx context.Context, client *searchconsole.Client, input queryMultipleSitesInput) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*analysis.MultiSiteResult]("querying multiple sites", nil, err)
+	}
+	summaryMetric := input.SummaryMetric
+	if summaryMetric == "" {
+		summaryMetric = analysis.SummaryMetricClicks
+	}
+	if err := validateOutputBudget(input.MaxOutputTokens, summaryMetric); err != nil {
+		return marshalToolResult[*analysis.MultiSiteResult]("querying multiple sites", nil, err)
+	}
+	startDate, endDate, err := resolveDateInput(input.DateRange, input.StartDate, input.EndDate)
+	if err != nil {
+		return marshalToolResult[*analysis.MultiSiteResult]("querying multiple sites", nil, err)
+	}
+	results, err := client.QueryMultipleSites(
+		ctx, input.SiteURLs, startDate, endDate, input.Dimensions, input.RowLimit, input.SearchType,
+		searchconsole.SearchAnalyticsOptions{
+			DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+			AllRows:               input.AllRows,
+			MaxRows:               input.MaxRows,
+			DataState:             input.DataState,
+		},
+		input.Concurrency)
+	if err != nil {
+		return marshalToolResult[*analysis.MultiSiteResult]("querying multiple sites", nil, err)
+	}
+	result := analysis.CombineSites(results, !input.TotalsOnly)
+	if result.SucceededCount == 0 {
+		result.StartDate, result.EndDate, result.Dimensions = startDate, endDate, input.Dimensions
+		result.SearchType = input.SearchType
+	}
+	if input.MaxOutputTokens == 0 {
+		return formatToolResult("querying multiple sites", input.OutputFormat, nil, result, nil)
+	}
+	maxRows := 0
+	for _, site := range result.Sites {
+		if site.Response != nil {
+			maxRows = max(maxRows, len(site.Response.Rows))
+		}
+	}
+	text, err := fitOutputBudget(input.MaxOutputTokens, maxRows, func(keep int) (string, error) {
+		budgeted, err := summarizeSitesForBudget(result, summaryMetric, keep, input.MaxOutputTokens)
+		if err != nil {
+			return "", err
+		}
+		return renderToolResult(input.OutputFormat, nil, budgeted)
+	})
+	if err != nil {
+		return marshalToolResult[*analysis.MultiSiteResult]("querying multiple sites", nil, err)
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil, nil
+}
+
+// summarizeSitesForBudget returns a copy of result in which every property
+// with more than keep rows keeps only its top keep rows by summaryMetric, or
+// result itself when keep is negative.
+func summarizeSitesForBudget(
+	result *analysis.MultiSiteResult,
+	summaryMetric string,
+	keep, maxOutputTokens int,
+) (*analysis.MultiSiteResult, error) {
+	if keep < 0 {
+		return result, nil
+	}
+	budgeted := *result
+	budgeted.Sites = slices.Clone(result.Sites)
+	for i, site := range budgeted.Sites {
+		if site.Response == nil || len(site.Response.Rows) <= keep {
+			continue
+		}
+		resp, err := summarizeForBudget(site.Response, summaryMetric, keep, maxOutputTokens)
+		if err != nil {
+			return nil, err
+		}
+		budgeted.Sites[i].Response = resp
+	}
+	return &budgeted, nil
+}
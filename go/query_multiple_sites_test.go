@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestQueryMultipleSites_ReturnsTotalsForEverySite(t *testing.T) {
+	srv := newPeriodServer(t, map[string]string{
+		"2026-02-01": `[{"clicks":10,"impressions":200,"ctr":0.05,"position":4}]`,
+	}, nil)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := queryMultipleSites(context.Background(), client, queryMultipleSitesInput{
+		SiteURLs:  []string{"devleader.ca", "example.com"},
+		StartDate: "2026-02-01",
+		EndDate:   "2026-02-28",
+	})
+	if err != nil {
+		t.Fatalf("queryMultipleSites: %v", err)
+	}
+	var payload struct {
+		SucceededCount int `json:"succeededCount"`
+		Combined       struct {
+			Clicks float64 `json:"clicks"`
+		} `json:"combined"`
+		Totals []struct {
+			SiteURL string  `json:"siteUrl"`
+			Clicks  float64 `json:"clicks"`
+		} `json:"totals"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if payload.SucceededCount != 2 || len(payload.Totals) != 2 || payload.Combined.Clicks != 20 {
+		t.Errorf("payload = %+v, want two sites with 20 combined clicks", payload)
+	}
+}
+
+func TestQueryMultipleSites_InvalidInput_ReturnsErrorContent(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := queryMultipleSites(context.Background(), client, queryMultipleSitesInput{
+		SiteURLs:    []string{"devleader.ca"},
+		StartDate:   "2026-02-01",
+		EndDate:     "2026-02-28",
+		Concurrency: 50,
+	})
+	if err != nil {
+		t.Fatalf("queryMultipleSites: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "querying multiple sites: invalid concurrency 50") {
+		t.Errorf("result = %s, want an invalid concurrency error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("made %d requests, want 0", len(requests))
+	}
+}
+
+func TestQueryMultipleSites_MaxOutputTokens_SummarizesEachSiteToFitBudget(t *testing.T) {
+	var rows []string
+	for i := range 200 {
+		rows = append(rows, fmt.Sprintf(`{"keys":["query number %d"],"clicks":%d,"impressions":%d,"ctr":0.01,"position":5}`, i, i, 100*i))
+	}
+	srv := newPeriodServer(t, map[string]string{"2026-02-01": "[" + strings.Join(rows, ",") + "]"}, nil)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := queryMultipleSites(context.Background(), client, queryMultipleSitesInput{
+		SiteURLs:        []string{"devleader.ca", "example.com"},
+		StartDate:       "2026-02-01",
+		EndDate:         "2026-02-28",
+		Dimensions:      []string{"query"},
+		MaxOutputTokens: 2000,
+	})
+	if err != nil {
+		t.Fatalf("queryMultipleSites: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if len(text) > 8000 {
+		t.Errorf("result is %d bytes, want at most 8000", len(text))
+	}
+
+	var payload analysis.MultiSiteResult
+	if err := json.Unmarshal([]byte(text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	for _, site := range payload.Sites {
+		s := site.Response.Summary
+		if s == nil || s.MaxOutputTokens != 2000 || s.RowsShown != len(site.Response.Rows) || s.RowsShown+s.RowsOmitted != 200 {
+			t.Errorf("%s summary = %+v, want the rows cut down to fit", site.SiteURL, s)
+		}
+	}
+	if payload.Combined.Clicks != 2*199*200/2 {
+		t.Errorf("combined clicks = %v, want every row of both sites", payload.Combined.Clicks)
+	}
+}
+
+func TestQueryMultipleSites_InvalidOutputBudget_ReturnsErrorContent(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := queryMultipleSites(context.Background(), client, queryMultipleSitesInput{
+		SiteURLs:        []string{"devleader.ca"},
+		StartDate:       "2026-02-01",
+		EndDate:         "2026-02-28",
+		MaxOutputTokens: 100,
+		SummaryMetric:   "ctr",
+	})
+	if err != nil {
+		t.Fatalf("queryMultipleSites: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "invalid summary_metric") {
+		t.Errorf("result = %s, want an invalid summary_metric error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("made %d requests, want 0", len(requests))
+	}
+}
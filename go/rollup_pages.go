@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/config"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const (
+	defaultPageGroupDepth = 1
+	defaultPageGroupLimit = 50
+)
+
+// pageGroupInput is one named site section of the rollup_pages tool.
+type pageGroupInput struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+// rollupPagesInput is the input schema for the rollup_pages tool.
+type rollupPagesInput struct {
+	SiteURL               string                      `json:"site_url"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	DateRange             string                      `json:"date_range,omitempty"`
+	Groups                []pageGroupInput            `json:"groups,omitempty"`
+	Depth                 int                         `json:"depth,omitempty"`
+	Limit                 int                         `json:"limit,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	MaxRows               int                         `json:"max_rows,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+func rollupPages(
+	ctx context.Context,
+	client *searchconsole.Client,
+	analysisConfig config.AnalysisConfig,
+	input rollupPagesInput,
+) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*analysis.PageGroupResult]("rolling up pages", nil, err)
+	}
+	options := analysis.PageGroupOptions{
+		Rules: pageGroupRules(analysisConfig, input),
+		Depth: input.Depth,
+		Limit: input.Limit,
+	}
+	if len(options.Rules) == 0 && options.Depth == 0 {
+		options.Depth = defaultPageGroupDepth
+	}
+	if options.Limit == 0 {
+		options.Limit = defaultPageGroupLimit
+	}
+	if err := options.Validate(); err != nil {
+		return marshalToolResult[*analysis.PageGroupResult]("rolling up pages", nil, err)
+	}
+
+	startDate, endDate, err := resolveDateInput(input.DateRange, input.StartDate, input.EndDate)
+	if err != nil {
+		return marshalToolResult[*analysis.PageGroupResult]("rolling up pages", nil, err)
+	}
+	resp, err := client.QuerySearchAnalytics(
+		ctx, input.SiteURL, startDate, endDate, []string{"page"}, 0, input.SearchType,
+		searchconsole.SearchAnalyticsOptions{
+			DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+			AllRows:               true,
+			MaxRows:               input.MaxRows,
+		})
+	if err != nil {
+		return marshalToolResult[*analysis.PageGroupResult]("rolling up pages", nil, err)
+	}
+	result, err := analysis.GroupPages(resp, options)
+	return formatToolResult("rolling up pages", input.OutputFormat, nil, result, err)
+}
+
+// pageGroupRules returns the groups passed with the call or, when there are
+// none and no depth was asked for, the property's configured page groups.
+func pageGroupRules(analysisConfig config.AnalysisConfig, input rollupPagesInput) []analysis.PageGroupRule {
+	var rules []analysis.PageGroupRule
+	for _, group := range input.Groups {
+		rules = append(rules, analysis.PageGroupRule{Name: group.Name, Pattern: group.Pattern})
+	}
+	if len(rules) > 0 || input.Depth != 0 {
+		return rules
+	}
+	groups, _ := propertyConfigFor(analysisConfig.PageGroups, input.SiteURL)
+	for _, group := range groups {
+		rules = append(rules, analysis.PageGroupRule{Name: group.Name, Pattern: group.Pattern})
+	}
+	return rules
+}
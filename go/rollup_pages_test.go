@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/config"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const rollupPageRows = `[
+	{"keys":["https://www.devleader.ca/blog/a"],"clicks":30,"impressions":300,"ctr":0.1,"position":3},
+	{"keys":["https://www.devleader.ca/docs/b"],"clicks":10,"impressions":100,"ctr":0.1,"position":5}
+]`
+
+func TestRollupPages_UsesConfiguredGroupsUnlessOverridden(t *testing.T) {
+	cfg := config.AnalysisConfig{PageGroups: map[string][]config.PageGroupConfig{
+		"sc-domain:devleader.ca": {{Name: "Blog", Pattern: "/blog/*"}},
+	}}
+
+	for _, tt := range []struct {
+		name  string
+		input rollupPagesInput
+		want  []string
+	}{
+		{name: "config", want: []string{"Blog", "(other)"}},
+		{name: "call groups", input: rollupPagesInput{Groups: []pageGroupInput{{Name: "Docs", Pattern: "/docs/*"}}}, want: []string{"(other)", "Docs"}},
+		{name: "depth", input: rollupPagesInput{Depth: 1}, want: []string{"/blog/*", "/docs/*"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []map[string]any
+			srv := newPeriodServer(t, map[string]string{"2026-02-01": rollupPageRows}, &requests)
+			client := searchconsole.NewTestClient(srv.Client())
+
+			tt.input.SiteURL = "devleader.ca"
+			tt.input.StartDate = "2026-02-01"
+			tt.input.EndDate = "2026-02-28"
+			result, _, err := rollupPages(context.Background(), client, cfg, tt.input)
+			if err != nil {
+				t.Fatalf("rollupPages: %v", err)
+			}
+
+			if dims, _ := requests[0]["dimensions"].([]any); len(dims) != 1 || dims[0] != "page" {
+				t.Errorf("request dimensions = %v, want [page]", requests[0]["dimensions"])
+			}
+			var payload analysis.PageGroupResult
+			if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+				t.Fatalf("unmarshal result: %v", err)
+			}
+			if len(payload.Groups) != len(tt.want) {
+				t.Fatalf("groups = %+v, want %v", payload.Groups, tt.want)
+			}
+			for i, name := range tt.want {
+				if payload.Groups[i].Name != name {
+					t.Errorf("groups[%d] = %q, want %q", i, payload.Groups[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+func TestRollupPages_NoConfig_DefaultsToDepthOne(t *testing.T) {
+	srv := newPeriodServer(t, map[string]string{"2026-02-01": rollupPageRows}, nil)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := rollupPages(context.Background(), client, config.AnalysisConfig{}, rollupPagesInput{
+		SiteURL:   "devleader.ca",
+		StartDate: "2026-02-01",
+		EndDate:   "2026-02-28",
+	})
+	if err != nil {
+		t.Fatalf("rollupPages: %v", err)
+	}
+	var payload analysis.PageGroupResult
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if payload.Depth != 1 || payload.GroupCount != 2 {
+		t.Errorf("depth/groups = %d/%d, want 1/2", payload.Depth, payload.GroupCount)
+	}
+}
+
+func TestRollupPages_GroupsAndDepth_ReturnsErrorContentWithoutHTTPCall(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	result, _, err := rollupPages(context.Background(), client, config.AnalysisConfig{}, rollupPagesInput{
+		SiteURL:   "devleader.ca",
+		DateRange: "last_28_days",
+		Groups:    []pageGroupInput{{Name: "Blog", Pattern: "/blog/*"}},
+		Depth:     2,
+	})
+	if err != nil {
+		t.Fatalf("rollupPages returned a Go error instead of error content: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "rolling up pages:") || !strings.Contains(text, "not both") {
+		t.Errorf("result text = %q, want a groups-or-depth error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected 0 HTTP calls, got %d", len(requests))
+	}
+}
@@ -56,8 +56,8 @@ func TestStdioTransport_ServesRealSession(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ListTools over stdio-equivalent transport: %v", err)
 	}
-	if len(toolsResult.Tools) != 4 {
-		t.Errorf("got %d tools over stdio-equivalent transport, want 4", len(toolsResult.Tools))
+	if len(toolsResult.Tools) != 21 {
+		t.Errorf("got %d tools over stdio-equivalent transport, want 21", len(toolsResult.Tools))
 	}
 
 	callResult, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const (
+	defaultStrikingMinPosition    = 8
+	defaultStrikingMaxPosition    = 20
+	defaultStrikingMinImpressions = 100
+	defaultStrikingTargetPosition = 3
+	defaultStrikingLimit          = 50
+)
+
+// strikingDistanceInput is the input schema for the striking_distance_keywords tool.
+type strikingDistanceInput struct {
+	SiteURL               string                      `json:"site_url"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	DateRange             string                      `json:"date_range,omitempty"`
+	MinPosition           float64                     `json:"min_position,omitempty"`
+	MaxPosition           float64                     `json:"max_position,omitempty"`
+	MinImpressions        *float64                    `json:"min_impressions,omitempty"`
+	TargetPosition        float64                     `json:"target_position,omitempty"`
+	Limit                 int                         `json:"limit,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	MaxRows               int                         `json:"max_rows,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+func strikingDistanceKeywords(ctx context.Context, client *searchconsole.Client, input strikingDistanceInput) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*analysis.StrikingDistanceResult]("finding striking distance keywords", nil, err)
+	}
+	options := analysis.StrikingDistanceOptions{
+		MinPosition:    input.MinPosition,
+		MaxPosition:    input.MaxPosition,
+		MinImpressions: defaultStrikingMinImpressions,
+		TargetPosition: input.TargetPosition,
+		Limit:          input.Limit,
+	}
+	if options.MinPosition == 0 {
+		options.MinPosition = defaultStrikingMinPosition
+	}
+	if options.MaxPosition == 0 {
+		options.MaxPosition = defaultStrikingMaxPosition
+	}
+	// A pointer, so callers can pass 0 to see every row in range.
+	if input.MinImpressions != nil {
+		options.MinImpressions = *input.MinImpressions
+	}
+	if options.TargetPosition == 0 {
+		options.TargetPosition = defaultStrikingTargetPosition
+	}
+	if options.Limit == 0 {
+		options.Limit = defaultStrikingLimit
+	}
+	if err := options.Validate(); err != nil {
+		return marshalToolResult[*analysis.StrikingDistanceResult]("finding striking distance keywords", nil, err)
+	}
+
+	startDate, endDate, err := resolveDateInput(input.DateRange, input.StartDate, input.EndDate)
+	if err != nil {
+		return marshalToolResult[*analysis.StrikingDistanceResult]("finding striking distance keywords", nil, err)
+	}
+	resp, err := client.QuerySearchAnalytics(
+		ctx, input.SiteURL, startDate, endDate, []string{"query", "page"}, 0, input.SearchType,
+		searchconsole.SearchAnalyticsOptions{
+			DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+			AllRows:               true,
+			MaxRows:               input.MaxRows,
+		})
+	if err != nil {
+		return marshalToolResult[*analysis.StrikingDistanceResult]("finding striking distance keywords", nil, err)
+	}
+	result, err := analysis.StrikingDistance(resp, options)
+	return formatToolResult("finding striking distance keywords", input.OutputFormat, nil, result, err)
+}
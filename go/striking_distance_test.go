@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestStrikingDistanceKeywords_AppliesDefaultsAndZeroMinImpressions(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, map[string]string{
+		"2026-02-01": `[
+			{"keys":["top","/top"],"clicks":200,"impressions":1000,"ctr":0.2,"position":3},
+			{"keys":["close","/close"],"clicks":1,"impressions":40,"ctr":0.025,"position":9}
+		]`,
+	}, &requests)
+	client := searchconsole.NewTestClient(srv.Client())
+
+	decode := func(result *mcp.CallToolResult) analysis.StrikingDistanceResult {
+		t.Helper()
+		var payload analysis.StrikingDistanceResult
+		if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+			t.Fatalf("unmarshal result: %v", err)
+		}
+		return payload
+	}
+
+	input := strikingDistanceInput{SiteURL: "devleader.ca", StartDate: "2026-02-01", EndDate: "2026-02-28"}
+	result, _, err := strikingDistanceKeywords(context.Background(), client, input)
+	if err != nil {
+		t.Fatalf("strikingDistanceKeywords: %v", err)
+	}
+	payload := decode(result)
+	if payload.MinPosition != 8 || payload.MaxPosition != 20 || payload.MinImpressions != 100 || payload.TargetPosition != 3 {
+		t.Errorf("defaults = %v-%v, %v impressions, target %v; want 8-20, 100, 3",
+			payload.MinPosition, payload.MaxPosition, payload.MinImpressions, payload.TargetPosition)
+	}
+	if payload.OpportunityCount != 0 {
+		t.Errorf("opportunityCount = %d, want 0 under the default 100-impression threshold", payload.OpportunityCount)
+	}
+
+	zero := 0.0
+	input.MinImpressions = &zero
+	result, _, err = strikingDistanceKeywords(context.Background(), client, input)
+	if err != nil {
+		t.Fatalf("strikingDistanceKeywords: %v", err)
+	}
+	if payload := decode(result); payload.OpportunityCount != 1 || payload.Keywords[0].Query != "close" {
+		t.Errorf("keywords = %+v, want close once min_impressions is 0", payload.Keywords)
+	}
+
+	if dims, _ := requests[0]["dimensions"].([]any); len(dims) != 2 || dims[0] != "query" || dims[1] != "page" {
+		t.Errorf("request dimensions = %v, want [query page]", requests[0]["dimensions"])
+	}
+}
+
+func TestStrikingDistanceKeywords_InvalidRange_ReturnsErrorContentWithoutHTTPCall(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, nil, &requests)
+
+	client := searchconsole.NewTestClient(srv.Client())
+	result, _, err := strikingDistanceKeywords(context.Background(), client, strikingDistanceInput{
+		SiteURL:        "devleader.ca",
+		DateRange:      "last_28_days",
+		TargetPosition: 9,
+	})
+	if err != nil {
+		t.Fatalf("strikingDistanceKeywords returned a Go error instead of error content: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "finding striking distance keywords:") || !strings.Contains(text, "invalid target_position 9") {
+		t.Errorf("result text = %q, want an invalid target_position error", text)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected 0 HTTP calls, got %d", len(requests))
+	}
+}
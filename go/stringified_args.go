@@ -12,7 +12,23 @@ import (
 // repair; it is intentionally a plain data map (not per-tool duplicated logic),
 // so every tool with an array-typed parameter is covered by one code path.
 var toolArrayFields = map[string][]string{
-	"query_search_analytics": {"dimensions"},
+	"query_search_analytics":     {"dimensions", "dimension_filter_groups", "brand_terms", "brand_patterns"},
+	"query_hourly_performance":   {"dimensions", "dimension_filter_groups"},
+	"compare_periods":            {"dimensions", "dimension_filter_groups"},
+	"top_movers":                 {"dimension_filter_groups"},
+	"find_cannibalization":       {"dimension_filter_groups"},
+	"striking_distance_keywords": {"dimension_filter_groups"},
+	"find_ctr_anomalies":         {"dimension_filter_groups"},
+	"detect_anomalies":           {"dimension_filter_groups"},
+	"brand_split":                {"dimension_filter_groups", "brand_terms", "brand_patterns"},
+	"cluster_queries":            {"dimension_filter_groups"},
+	"rollup_pages":               {"groups", "dimension_filter_groups"},
+	"find_decaying_content":      {"dimension_filter_groups"},
+	"forecast_traffic":           {"dimension_filter_groups"},
+	"new_queries":                {"dimension_filter_groups"},
+	"lost_queries":               {"dimension_filter_groups"},
+	"intent_rollup":              {"dimension_filter_groups", "brand_terms", "brand_patterns"},
+	"query_multiple_sites":       {"site_urls", "dimensions", "dimension_filter_groups"},
 }
 
 // coerceStringifiedArrayArgs returns a receiving middleware that repairs a
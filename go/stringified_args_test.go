@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/config"
 	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
 )
 
@@ -37,7 +38,7 @@ func newTestServerAndClient(t *testing.T, registerMiddleware func(*mcp.Server))
 	mcp.AddTool(server,
 		&mcp.Tool{Name: "query_search_analytics", Description: "test"},
 		func(ctx context.Context, _ *mcp.CallToolRequest, input querySearchAnalyticsInput) (*mcp.CallToolResult, any, error) {
-			return querySearchAnalytics(ctx, client, input)
+			return querySearchAnalytics(ctx, client, config.AnalysisConfig{}, input)
 		},
 	)
 
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+const (
+	defaultMoverDimension = "query"
+	defaultMoverLimit     = 10
+)
+
+// moverDimensions are the dimensions top_movers can rank by.
+var moverDimensions = []string{"query", "page", "country", "device", "searchAppearance"}
+
+// topMoversInput is the input schema for the top_movers tool.
+type topMoversInput struct {
+	SiteURL               string                      `json:"site_url"`
+	StartDate             string                      `json:"start_date,omitempty"`
+	EndDate               string                      `json:"end_date,omitempty"`
+	DateRange             string                      `json:"date_range,omitempty"`
+	Comparison            string                      `json:"comparison,omitempty"`
+	Dimension             string                      `json:"dimension,omitempty"`
+	Metric                string                      `json:"metric,omitempty"`
+	MinImpressions        float64                     `json:"min_impressions,omitempty"`
+	Limit                 int                         `json:"limit,omitempty"`
+	SearchType            string                      `json:"search_type,omitempty"`
+	DimensionFilterGroups []dimensionFilterGroupInput `json:"dimension_filter_groups,omitempty"`
+	MaxRows               int                         `json:"max_rows,omitempty"`
+	OutputFormat          string                      `json:"output_format,omitempty"`
+}
+
+func topMovers(ctx context.Context, client *searchconsole.Client, input topMoversInput) (*mcp.CallToolResult, any, error) {
+	if err := validateOutputFormat(input.OutputFormat); err != nil {
+		return marshalToolResult[*analysis.MoversResult]("finding top movers", nil, err)
+	}
+	dimension := input.Dimension
+	if dimension == "" {
+		dimension = defaultMoverDimension
+	}
+	if !slices.Contains(moverDimensions, dimension) {
+		err := fmt.Errorf("invalid dimension %q: must be one of query, page, country, device, searchAppearance", dimension)
+		return marshalToolResult[*analysis.MoversResult]("finding top movers", nil, err)
+	}
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultMoverLimit
+	}
+	options := analysis.MoverOptions{Metric: input.Metric, MinImpressions: input.MinImpressions, Limit: limit}
+	if err := options.Validate(); err != nil {
+		return marshalToolResult[*analysis.MoversResult]("finding top movers", nil, err)
+	}
+
+	query := periodQuery{
+		SiteURL:    input.SiteURL,
+		StartDate:  input.StartDate,
+		EndDate:    input.EndDate,
+		DateRange:  input.DateRange,
+		Comparison: input.Comparison,
+		Dimensions: []string{dimension},
+		SearchType: input.SearchType,
+		Options: searchconsole.SearchAnalyticsOptions{
+			DimensionFilterGroups: toDimensionFilterGroups(input.DimensionFilterGroups),
+			MaxRows:               input.MaxRows,
+		},
+	}
+	current, previous, comparison, err := queryPeriodPair(ctx, client, query)
+	if err != nil {
+		return marshalToolResult[*analysis.MoversResult]("finding top movers", nil, err)
+	}
+	currentTotals, previousTotals, err := queryPeriodTotals(ctx, client, query, current, previous)
+	if err != nil {
+		return marshalToolResult[*analysis.MoversResult]("finding top movers", nil, err)
+	}
+	result, err := analysis.TopMovers(current, previous, currentTotals, previousTotals, comparison, options)
+	return formatToolResult("finding top movers", input.OutputFormat, []string{dimension}, result, err)
+}
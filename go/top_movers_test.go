@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/analysis"
+	"github.com/ncosentino/google-search-console-mcp/go/internal/searchconsole"
+)
+
+func TestTopMovers_PagesThroughBothPeriodsAndRanks(t *testing.T) {
+	var requests []map[string]any
+	srv := newPeriodServer(t, map[string]string{
+		"2026-02-08": `[{"keys":["/a"],"clicks":40,"impressions":400,"ctr":0.1,"position":3},{"keys":["/b"],"clicks":1,"impressions":100,"ctr":0.01,"position":9}]`,
+		"2026-02-01": `[{"keys":["/a"],"clicks":10,"impressions":300,"ctr":0.03,"position":5},{"keys":["/b"],"clicks":20,"impressions":200,"ctr":0.1,"position":4}]`,
+	}, &requests)
+
+	client := searchconsole.NewTestClient(srv.Client())
+	result, _, err := topMovers(context.Background(), client, topMoversInput{
+		SiteURL:   "devleader.ca",
+		StartDate: "2026-02-08",
+		EndDate:   "2026-02-14",
+		Dimension: "page",
+	})
+	if err != nil {
+		t.Fatalf("topMovers: %v", err)
+	}
+
+	// Both periods, then a dimensionless totals query for each.
+	if len(requests) != 4 {
+		t.Fatalf("request count = %d, want 4", len(requests))
+	}
+	if requests[2]["dimensions"] != nil || requests[3]["startDate"] != "2026-02-01" {
+		t.Errorf("totals requests = %v, %v; want dimensionless queries for both periods", requests[2], requests[3])
+	}
+	for _, req := range requests[:2] {
+		if req["rowLimit"] != float64(25000) {
+			t.Errorf("request rowLimit = %v, want 25000 (paging every row)", req["rowLimit"])
+		}
+		if dims, _ := req["dimensions"].([]any); len(dims) != 1 || dims[0] != "page" {
+			t.Errorf("request dimensions = %v, want [page]", req["dimensions"])
+		}
+	}
+
+	var payload analysis.MoversResult
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &payload); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if len(payload.Winners) != 1 || payload.Winners[0].Keys[0] != "/a" {
+		t.Errorf("winners = %+v, want /a", payload.Winners)
+	}
+	if len(payload.Losers) != 1 || payload.Losers[0].Keys[0] != "/b" {
+		t.Errorf("losers = %+v, want /b", payload.Losers)
+	}
+}
+
+func TestTopMovers_InvalidInput_ReturnsErrorContentWithoutHTTPCall(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		input  topMoversInput
+		marker string
+	}{
+		{name: "dimension", input: topMoversInput{Dimension: "date"}, marker: `invalid dimension \"date\"`},
+		{name: "metric", input: topMoversInput{Metric: "ctr"}, marker: `invalid metric \"ctr\"`},
+		{name: "min_impressions", input: topMoversInput{MinImpressions: -5}, marker: "invalid min_impressions -5"},
+		{name: "limit", input: topMoversInput{Limit: -1}, marker: "invalid limit -1: must be positive"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []map[string]any
+			srv := newPeriodServer(t, nil, &requests)
+			client := searchconsole.NewTestClient(srv.Client())
+
+			tt.input.SiteURL = "devleader.ca"
+			tt.input.DateRange = "last_28_days"
+			result, _, err := topMovers(context.Background(), client, tt.input)
+			if err != nil {
+				t.Fatalf("topMovers returned a Go error instead of error content: %v", err)
+			}
+			text := result.Content[0].(*mcp.TextContent).Text
+			if !strings.Contains(text, "finding top movers:") || !strings.Contains(text, tt.marker) {
+				t.Errorf("result text = %q, want it to contain %q", text, tt.marker)
+			}
+			if len(requests) != 0 {
+				t.Errorf("expected 0 HTTP calls, got %d", len(requests))
+			}
+		})
+	}
+}